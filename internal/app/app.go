@@ -7,12 +7,16 @@ import (
 	"github.com/wlachs/blog/internal/jwt"
 	"github.com/wlachs/blog/internal/logger"
 	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/scheduler"
+	"github.com/wlachs/blog/internal/services"
 )
 
 // Run initializes the application:
 // - Create logger
 // - Establish DB connection
+// - Load JWT keys
 // - Define configuration container
+// - Start background jobs
 // - Bind application routes
 func Run() {
 	log := logger.CreateLogger()
@@ -20,14 +24,45 @@ func Run() {
 	rep := repository.CreateRepository(database)
 	postRepository := repository.CreatePostRepository(log, rep)
 	userRepository := repository.CreateUserRepository(log, rep)
-	jwtUtils := jwt.CreateTokenUtils(log)
+	tagRepository := repository.CreateTagRepository(log, rep)
+	categoryRepository := repository.CreateCategoryRepository(log, rep)
+	seriesRepository := repository.CreateSeriesRepository(log, rep)
+	revisionRepository := repository.CreateRevisionRepository(log, rep)
+	searchRepository := repository.CreateSearchRepository(log, rep)
+	relatedPostRepository := repository.CreateRelatedPostRepository(log, rep)
+	refreshTokenRepository := repository.CreateRefreshTokenRepository(log, rep)
+	apiTokenRepository := repository.CreateAPITokenRepository(log, rep)
+	twoFactorRepository := repository.CreateTwoFactorRepository(log, rep)
+	keyring, err := jwt.LoadKeyring(log)
+	if err != nil {
+		log.Fatalf("failed to load JWT keys: %v", err)
+	}
+	jwtUtils := jwt.CreateTokenUtils(log, keyring)
 
 	cont := container.CreateContainer(
 		log,
 		postRepository,
 		userRepository,
+		tagRepository,
+		categoryRepository,
+		seriesRepository,
+		revisionRepository,
+		searchRepository,
+		relatedPostRepository,
+		refreshTokenRepository,
+		apiTokenRepository,
+		twoFactorRepository,
 		jwtUtils,
 	)
 
+	sched := scheduler.CreateScheduler(
+		cont,
+		services.CreatePostService(cont),
+		services.CreateRelatedPostService(cont),
+		services.CreateTokenService(cont),
+	)
+	sched.Start()
+	defer sched.Stop()
+
 	controller.CreateRoutes(cont)
 }
@@ -0,0 +1,92 @@
+package auth_test
+
+import (
+	"encoding/base32"
+	"github.com/wlachs/blog/internal/auth"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the secret of the RFC 6238 test vectors, "12345678901234567890", base32-encoded.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// TestTOTPCode tests the TOTP codes against the SHA1 test vectors of RFC 6238, truncated to 6 digits.
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := auth.TOTPCode(rfcSecret, auth.TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if code != expected {
+			t.Errorf("code at %d should be %s, got %s", unix, expected, code)
+		}
+	}
+}
+
+// TestValidateTOTPCode tests accepting the codes of the current and the neighbouring periods.
+func TestValidateTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1111111109, 0)
+	step := auth.TOTPStep(now)
+
+	for _, s := range []int64{step - 1, step, step + 1} {
+		code, _ := auth.TOTPCode(rfcSecret, s)
+		matched, ok := auth.ValidateTOTPCode(rfcSecret, code, now)
+		if !ok || matched != s {
+			t.Errorf("code of step %d should be accepted", s)
+		}
+	}
+
+	code, _ := auth.TOTPCode(rfcSecret, step+2)
+	if _, ok := auth.ValidateTOTPCode(rfcSecret, code, now); ok {
+		t.Errorf("code of a distant step should be rejected")
+	}
+
+	if _, ok := auth.ValidateTOTPCode(rfcSecret, "12345", now); ok {
+		t.Errorf("code with incorrect length should be rejected")
+	}
+}
+
+// TestGenerateTOTPSecret tests generating random TOTP secrets.
+func TestGenerateTOTPSecret(t *testing.T) {
+	t.Parallel()
+
+	s1, _ := auth.GenerateTOTPSecret()
+	s2, _ := auth.GenerateTOTPSecret()
+
+	if len(s1) != 32 {
+		t.Errorf("secret should have 160 bits")
+	}
+	if s1 == s2 {
+		t.Errorf("secrets should be random")
+	}
+}
+
+// TestTOTPURI tests the otpauth URI read by authenticator apps.
+func TestTOTPURI(t *testing.T) {
+	t.Parallel()
+
+	uri := auth.TOTPURI("My Blog", "Laszlo", "SECRET")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/My%20Blog:Laszlo?") {
+		t.Errorf("incorrect label in %s", uri)
+	}
+	for _, param := range []string{"secret=SECRET", "issuer=My+Blog", "digits=6", "period=30", "algorithm=SHA1"} {
+		if !strings.Contains(uri, param) {
+			t.Errorf("%s missing from %s", param, uri)
+		}
+	}
+}
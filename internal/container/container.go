@@ -12,6 +12,15 @@ type Container interface {
 
 	GetPostRepository() repository.PostRepository
 	GetUserRepository() repository.UserRepository
+	GetTagRepository() repository.TagRepository
+	GetCategoryRepository() repository.CategoryRepository
+	GetSeriesRepository() repository.SeriesRepository
+	GetRevisionRepository() repository.RevisionRepository
+	GetSearchRepository() repository.SearchRepository
+	GetRelatedPostRepository() repository.RelatedPostRepository
+	GetRefreshTokenRepository() repository.RefreshTokenRepository
+	GetAPITokenRepository() repository.APITokenRepository
+	GetTwoFactorRepository() repository.TwoFactorRepository
 
 	GetJWTUtils() jwt.TokenUtils
 }
@@ -20,8 +29,17 @@ type Container interface {
 type container struct {
 	logger *zap.SugaredLogger
 
-	postRepository repository.PostRepository
-	userRepository repository.UserRepository
+	postRepository      repository.PostRepository
+	userRepository      repository.UserRepository
+	tagRepository       repository.TagRepository
+	categoryRepository  repository.CategoryRepository
+	seriesRepository    repository.SeriesRepository
+	revisionRepository  repository.RevisionRepository
+	searchRepository    repository.SearchRepository
+	relatedRepository   repository.RelatedPostRepository
+	tokenRepository     repository.RefreshTokenRepository
+	apiTokenRepository  repository.APITokenRepository
+	twoFactorRepository repository.TwoFactorRepository
 
 	jwtUtils jwt.TokenUtils
 }
@@ -31,9 +49,32 @@ func CreateContainer(
 	log *zap.SugaredLogger,
 	postRepository repository.PostRepository,
 	userRepository repository.UserRepository,
+	tagRepository repository.TagRepository,
+	categoryRepository repository.CategoryRepository,
+	seriesRepository repository.SeriesRepository,
+	revisionRepository repository.RevisionRepository,
+	searchRepository repository.SearchRepository,
+	relatedRepository repository.RelatedPostRepository,
+	tokenRepository repository.RefreshTokenRepository,
+	apiTokenRepository repository.APITokenRepository,
+	twoFactorRepository repository.TwoFactorRepository,
 	jwtUtils jwt.TokenUtils,
 ) Container {
-	return &container{log, postRepository, userRepository, jwtUtils}
+	return &container{
+		log,
+		postRepository,
+		userRepository,
+		tagRepository,
+		categoryRepository,
+		seriesRepository,
+		revisionRepository,
+		searchRepository,
+		relatedRepository,
+		tokenRepository,
+		apiTokenRepository,
+		twoFactorRepository,
+		jwtUtils,
+	}
 }
 
 // GetLogger returns the logger implementation stored in the container
@@ -51,6 +92,51 @@ func (cont container) GetUserRepository() repository.UserRepository {
 	return cont.userRepository
 }
 
+// GetTagRepository returns the tag repository implementation stored in the container
+func (cont container) GetTagRepository() repository.TagRepository {
+	return cont.tagRepository
+}
+
+// GetCategoryRepository returns the category repository implementation stored in the container
+func (cont container) GetCategoryRepository() repository.CategoryRepository {
+	return cont.categoryRepository
+}
+
+// GetSeriesRepository returns the series repository implementation stored in the container
+func (cont container) GetSeriesRepository() repository.SeriesRepository {
+	return cont.seriesRepository
+}
+
+// GetRevisionRepository returns the revision repository implementation stored in the container
+func (cont container) GetRevisionRepository() repository.RevisionRepository {
+	return cont.revisionRepository
+}
+
+// GetSearchRepository returns the search repository implementation stored in the container
+func (cont container) GetSearchRepository() repository.SearchRepository {
+	return cont.searchRepository
+}
+
+// GetRelatedPostRepository returns the related post repository implementation stored in the container
+func (cont container) GetRelatedPostRepository() repository.RelatedPostRepository {
+	return cont.relatedRepository
+}
+
+// GetRefreshTokenRepository returns the refresh token repository implementation stored in the container
+func (cont container) GetRefreshTokenRepository() repository.RefreshTokenRepository {
+	return cont.tokenRepository
+}
+
+// GetAPITokenRepository returns the API token repository implementation stored in the container
+func (cont container) GetAPITokenRepository() repository.APITokenRepository {
+	return cont.apiTokenRepository
+}
+
+// GetTwoFactorRepository returns the two-factor authentication repository implementation stored in the container
+func (cont container) GetTwoFactorRepository() repository.TwoFactorRepository {
+	return cont.twoFactorRepository
+}
+
 // GetJWTUtils returns the JWT utility implementation stored in the container.
 func (cont container) GetJWTUtils() jwt.TokenUtils {
 	return cont.jwtUtils
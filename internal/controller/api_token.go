@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strconv"
+)
+
+// APITokenController interface defining personal API token-related middleware methods to handle HTTP requests.
+type APITokenController interface {
+	GetAPITokens(c *gin.Context)
+	AddAPIToken(c *gin.Context)
+	DeleteAPIToken(c *gin.Context)
+}
+
+// apiTokenController is a concrete implementation of the APITokenController interface.
+type apiTokenController struct {
+	cont            container.Container
+	apiTokenService services.APITokenService
+}
+
+// CreateAPITokenController instantiates the API token controller using the application container.
+func CreateAPITokenController(cont container.Container, apiTokenService services.APITokenService) APITokenController {
+	return &apiTokenController{cont, apiTokenService}
+}
+
+// GetAPITokens middleware. Top level handler of /tokens GET requests.
+// Lists the personal API tokens of the logged-in user.
+func (controller apiTokenController) GetAPITokens(c *gin.Context) {
+	apiTokenService := controller.apiTokenService
+
+	tokens, err := apiTokenService.GetAPITokens(c.GetString("UserID"))
+
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedAPITokenError{})
+		return
+	}
+
+	t := populateAPITokens(tokens)
+	c.IndentedJSON(http.StatusOK, types.APITokens{Tokens: &t})
+}
+
+// AddAPIToken middleware. Top level handler of /tokens POST requests.
+// Creates a personal API token for the logged-in user and returns it once.
+func (controller apiTokenController) AddAPIToken(c *gin.Context) {
+	apiTokenService := controller.apiTokenService
+
+	var body types.NewAPIToken
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	scopes := make([]repository.Scope, 0, len(body.Scopes))
+	for _, scope := range body.Scopes {
+		scopes = append(scopes, repository.Scope(scope))
+	}
+
+	token, secret, err := apiTokenService.CreateAPIToken(c.GetString("UserID"), body.Name, scopes)
+
+	switch err.(type) {
+	case nil:
+		t := populateAPIToken(token)
+		t.Token = &secret
+		c.IndentedJSON(http.StatusCreated, t)
+	case errortypes.InvalidAPITokenNameError, errortypes.InvalidScopeError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedAPITokenError{})
+	}
+}
+
+// DeleteAPIToken middleware. Top level handler of /tokens/:TokenID DELETE requests.
+// Revokes a personal API token of the logged-in user.
+func (controller apiTokenController) DeleteAPIToken(c *gin.Context) {
+	apiTokenService := controller.apiTokenService
+
+	tokenID, err := apiTokenID(c)
+	if err == nil {
+		err = apiTokenService.RevokeAPIToken(c.GetString("UserID"), tokenID)
+	}
+
+	switch err.(type) {
+	case nil:
+		c.Status(http.StatusOK)
+	case errortypes.InvalidAPITokenIDError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.APITokenNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedAPITokenError{})
+	}
+}
+
+// apiTokenID parses the TokenID path parameter.
+func apiTokenID(c *gin.Context) (uint, error) {
+	tokenID, _ := c.Params.Get("TokenID")
+
+	id, err := strconv.ParseUint(tokenID, 10, 0)
+	if err != nil || id == 0 {
+		return 0, errortypes.InvalidAPITokenIDError{ID: tokenID}
+	}
+
+	return uint(id), nil
+}
+
+// populateAPIToken maps a repository.APIToken model to types.APIToken. The token itself is not known anymore.
+func populateAPIToken(token repository.APIToken) types.APIToken {
+	scopes := make([]types.Scope, 0, len(token.Scopes))
+	for _, scope := range token.Scopes {
+		scopes = append(scopes, types.Scope(scope))
+	}
+
+	return types.APIToken{
+		Id:           int(token.ID),
+		Name:         token.Name,
+		Scopes:       scopes,
+		CreationTime: token.CreatedAt,
+		LastUsedTime: token.LastUsedAt,
+	}
+}
+
+// populateAPITokens maps a slice of repository.APIToken models to a types.APIToken slice
+func populateAPITokens(tokens []repository.APIToken) []types.APIToken {
+	t := make([]types.APIToken, 0, len(tokens))
+
+	for _, token := range tokens {
+		t = append(t, populateAPIToken(token))
+	}
+
+	return t
+}
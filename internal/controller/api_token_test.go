@@ -0,0 +1,178 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// apiTokenTestContext contains commonly used services, controllers and other objects relevant for testing the APITokenController.
+type apiTokenTestContext struct {
+	mockAPITokenService *mocks.MockAPITokenService
+	sut                 controller.APITokenController
+	ctx                 *gin.Context
+	rec                 *httptest.ResponseRecorder
+}
+
+// createAPITokenControllerContext creates the context for testing the APITokenController and reduces code duplication.
+func createAPITokenControllerContext(t *testing.T) *apiTokenTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockAPITokenService := mocks.NewMockAPITokenService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateAPITokenController(cont, mockAPITokenService)
+	ctx, rec := test.CreateControllerContext()
+	ctx.Set("UserID", "testUser")
+
+	return &apiTokenTestContext{mockAPITokenService, sut, ctx, rec}
+}
+
+// TestAPITokenController_GetAPITokens tests listing the personal API tokens of the logged-in user.
+func TestAPITokenController_GetAPITokens(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	creationTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tokens := []repository.APIToken{
+		{ID: 1, Name: "CI", Scopes: []repository.Scope{repository.ScopePostsWrite}, CreatedAt: creationTime, LastUsedAt: &creationTime},
+		{ID: 2, Name: "Backup", Scopes: []repository.Scope{repository.ScopePostsRead}, CreatedAt: creationTime},
+	}
+	expectedTokens := []types.APIToken{
+		{Id: 1, Name: "CI", Scopes: []types.Scope{types.PostsWrite}, CreationTime: creationTime, LastUsedTime: &creationTime},
+		{Id: 2, Name: "Backup", Scopes: []types.Scope{types.PostsRead}, CreationTime: creationTime},
+	}
+
+	c.mockAPITokenService.EXPECT().GetAPITokens("testUser").Return(tokens, nil)
+
+	c.sut.GetAPITokens(c.ctx)
+
+	var output types.APITokens
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, expectedTokens, *output.Tokens, "response body should match")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAPITokenController_AddAPIToken tests creating a personal API token.
+func TestAPITokenController_AddAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{
+		"name":   "CI",
+		"scopes": []string{"posts:write"},
+	})
+
+	scopes := []repository.Scope{repository.ScopePostsWrite}
+	token := repository.APIToken{ID: 1, Name: "CI", Scopes: scopes}
+	c.mockAPITokenService.EXPECT().CreateAPIToken("testUser", "CI", scopes).Return(token, "blog_pat_secret", nil)
+
+	c.sut.AddAPIToken(c.ctx)
+
+	var output types.APIToken
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, "blog_pat_secret", *output.Token, "token should be returned upon creation")
+	assert.Equal(t, []types.Scope{types.PostsWrite}, output.Scopes, "incorrect scopes")
+	assert.Equal(t, 201, c.rec.Code, "incorrect response status")
+}
+
+// TestAPITokenController_AddAPIToken_Invalid_Scope tests creating a personal API token with an unknown scope.
+func TestAPITokenController_AddAPIToken_Invalid_Scope(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{
+		"name":   "CI",
+		"scopes": []string{"everything"},
+	})
+
+	expectedError := errortypes.InvalidScopeError{Scope: "everything"}
+	c.mockAPITokenService.EXPECT().CreateAPIToken("testUser", "CI", []repository.Scope{"everything"}).Return(repository.APIToken{}, "", expectedError)
+
+	c.sut.AddAPIToken(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestAPITokenController_DeleteAPIToken tests revoking a personal API token.
+func TestAPITokenController_DeleteAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	c.ctx.AddParam("TokenID", "3")
+	c.mockAPITokenService.EXPECT().RevokeAPIToken("testUser", uint(3)).Return(nil)
+
+	c.sut.DeleteAPIToken(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAPITokenController_DeleteAPIToken_Not_Found tests revoking a personal API token that doesn't exist or belongs to someone else.
+func TestAPITokenController_DeleteAPIToken_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	c.ctx.AddParam("TokenID", "3")
+	expectedError := errortypes.APITokenNotFoundError{ID: 3}
+	c.mockAPITokenService.EXPECT().RevokeAPIToken("testUser", uint(3)).Return(expectedError)
+
+	c.sut.DeleteAPIToken(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestAPITokenController_DeleteAPIToken_Invalid_ID tests revoking a personal API token with a malformed ID.
+func TestAPITokenController_DeleteAPIToken_Invalid_ID(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	c.ctx.AddParam("TokenID", "abc")
+	expectedError := errortypes.InvalidAPITokenIDError{ID: "abc"}
+
+	c.sut.DeleteAPIToken(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestAPITokenController_DeleteAPIToken_Unexpected_Error tests revoking a personal API token while encountering an unexpected error.
+func TestAPITokenController_DeleteAPIToken_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenControllerContext(t)
+
+	c.ctx.AddParam("TokenID", "3")
+	c.mockAPITokenService.EXPECT().RevokeAPIToken("testUser", uint(3)).Return(fmt.Errorf("unexpected error"))
+
+	c.sut.DeleteAPIToken(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, errortypes.UnexpectedAPITokenError{}.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strconv"
+)
+
+// ArchiveController interface defining archive-related middleware methods to handle HTTP requests
+type ArchiveController interface {
+	GetArchive(c *gin.Context)
+	GetArchivePosts(c *gin.Context)
+}
+
+// archiveController is a concrete implementation of the ArchiveController interface
+type archiveController struct {
+	cont           container.Container
+	archiveService services.ArchiveService
+}
+
+// CreateArchiveController instantiates an archive controller using the application container.
+func CreateArchiveController(cont container.Container, archiveService services.ArchiveService) ArchiveController {
+	return &archiveController{cont, archiveService}
+}
+
+// GetArchive middleware. Top level handler of /archive GET requests.
+func (controller archiveController) GetArchive(c *gin.Context) {
+	archiveService := controller.archiveService
+	months, err := archiveService.GetArchive()
+
+	switch err.(type) {
+	case nil:
+		years := populateArchiveYears(months)
+		c.IndentedJSON(http.StatusOK, types.Archive{Years: &years})
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedArchiveError{})
+	}
+}
+
+// GetArchivePosts middleware. Top level handler of /archive/:Year and /archive/:Year/:Month GET requests.
+func (controller archiveController) GetArchivePosts(c *gin.Context) {
+	archiveService := controller.archiveService
+
+	year, err := strconv.Atoi(c.Param("Year"))
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, errortypes.InvalidArchivePeriodError{Period: c.Param("Year")})
+		return
+	}
+
+	// The whole year is requested if no month is provided
+	month := 0
+	if value, found := c.Params.Get("Month"); found {
+		if month, err = strconv.Atoi(value); err != nil || month == 0 {
+			_ = c.AbortWithError(http.StatusBadRequest, errortypes.InvalidArchivePeriodError{Period: c.Param("Year") + "/" + value})
+			return
+		}
+	}
+
+	page := c.Query("page")
+	pageId, err := strconv.Atoi(page)
+
+	var posts []repository.Post
+	var pages int
+
+	// If no page query is provided, call the default service
+	if err != nil {
+		posts, pages, err = archiveService.GetArchivePosts(year, month)
+	} else {
+		posts, pages, err = archiveService.GetArchivePostsPage(year, month, pageId)
+	}
+
+	switch err.(type) {
+	case nil:
+		p := populatePostMetadataSlice(posts)
+		c.IndentedJSON(http.StatusOK, types.Posts{Posts: &p, Pages: &pages})
+	case errortypes.InvalidArchivePeriodError, errortypes.InvalidPostPageError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedArchiveError{})
+	}
+}
+
+// populateArchiveYears groups the archive months by their year, keeping their order
+func populateArchiveYears(months []repository.ArchiveMonth) []types.ArchiveYear {
+	years := make([]types.ArchiveYear, 0)
+
+	for _, month := range months {
+		if len(years) == 0 || years[len(years)-1].Year != month.Year {
+			years = append(years, types.ArchiveYear{Year: month.Year, Months: []types.ArchiveMonth{}})
+		}
+
+		year := &years[len(years)-1]
+		year.PostCount += month.Count
+		year.Months = append(year.Months, types.ArchiveMonth{Month: month.Month, PostCount: month.Count})
+	}
+
+	return years
+}
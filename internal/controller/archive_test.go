@@ -0,0 +1,163 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// archiveTestContext contains commonly used services, controllers and other objects relevant for testing the ArchiveController.
+type archiveTestContext struct {
+	mockArchiveService *mocks.MockArchiveService
+	sut                controller.ArchiveController
+	ctx                *gin.Context
+	rec                *httptest.ResponseRecorder
+}
+
+// createArchiveControllerContext creates the context for testing the ArchiveController and reduces code duplication.
+func createArchiveControllerContext(t *testing.T) *archiveTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockArchiveService := mocks.NewMockArchiveService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateArchiveController(cont, mockArchiveService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &archiveTestContext{mockArchiveService, sut, ctx, rec}
+}
+
+// TestArchiveController_GetArchive tests getting the post counts grouped by year and month.
+func TestArchiveController_GetArchive(t *testing.T) {
+	t.Parallel()
+	c := createArchiveControllerContext(t)
+
+	months := []repository.ArchiveMonth{
+		{Year: 2024, Month: 3, Count: 2},
+		{Year: 2024, Month: 1, Count: 1},
+		{Year: 2023, Month: 12, Count: 4},
+	}
+	expectedOutput := types.Archive{
+		Years: &[]types.ArchiveYear{
+			{Year: 2024, PostCount: 3, Months: []types.ArchiveMonth{{Month: 3, PostCount: 2}, {Month: 1, PostCount: 1}}},
+			{Year: 2023, PostCount: 4, Months: []types.ArchiveMonth{{Month: 12, PostCount: 4}}},
+		},
+	}
+
+	c.mockArchiveService.EXPECT().GetArchive().Return(months, nil)
+
+	c.sut.GetArchive(c.ctx)
+
+	var output types.Archive
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedOutput, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestArchiveController_GetArchive_Unexpected_Error tests handling unexpected errors of the archive.
+func TestArchiveController_GetArchive_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createArchiveControllerContext(t)
+
+	c.mockArchiveService.EXPECT().GetArchive().Return(nil, fmt.Errorf("error"))
+
+	c.sut.GetArchive(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, errortypes.UnexpectedArchiveError{}.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
+
+// TestArchiveController_GetArchivePosts_Year tests getting the posts created in a year.
+func TestArchiveController_GetArchivePosts_Year(t *testing.T) {
+	t.Parallel()
+	c := createArchiveControllerContext(t)
+
+	title := "testTitle"
+	posts := []repository.Post{{URLHandle: "testUrlHandle", Title: &title, Author: repository.User{UserName: "testAuthor"}}}
+	pages := 1
+	expectedOutput := types.Posts{
+		Posts: &[]types.PostMetadata{{Id: "testUrlHandle", Title: title, Author: "testAuthor"}},
+		Pages: &pages,
+	}
+
+	c.ctx.AddParam("Year", "2024")
+	c.mockArchiveService.EXPECT().GetArchivePosts(2024, 0).Return(posts, pages, nil)
+
+	c.sut.GetArchivePosts(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedOutput, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestArchiveController_GetArchivePosts_Month_Page tests getting a page of the posts created in a month.
+func TestArchiveController_GetArchivePosts_Month_Page(t *testing.T) {
+	t.Parallel()
+	c := createArchiveControllerContext(t)
+
+	c.ctx.AddParam("Year", "2024")
+	c.ctx.AddParam("Month", "03")
+	c.ctx.Request.URL, _ = url.Parse("?page=2")
+	c.mockArchiveService.EXPECT().GetArchivePostsPage(2024, 3, 2).Return([]repository.Post{}, 2, nil)
+
+	c.sut.GetArchivePosts(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestArchiveController_GetArchivePosts_Invalid_Year tests getting the posts of a malformed year.
+func TestArchiveController_GetArchivePosts_Invalid_Year(t *testing.T) {
+	t.Parallel()
+	c := createArchiveControllerContext(t)
+
+	expectedError := errortypes.InvalidArchivePeriodError{Period: "latest"}
+
+	c.ctx.AddParam("Year", "latest")
+
+	c.sut.GetArchivePosts(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestArchiveController_GetArchivePosts_Invalid_Month tests getting the posts of a month out of range.
+func TestArchiveController_GetArchivePosts_Invalid_Month(t *testing.T) {
+	t.Parallel()
+	c := createArchiveControllerContext(t)
+
+	expectedError := errortypes.InvalidArchivePeriodError{Period: "2024/13"}
+
+	c.ctx.AddParam("Year", "2024")
+	c.ctx.AddParam("Month", "13")
+	c.mockArchiveService.EXPECT().GetArchivePosts(2024, 13).Return(nil, -1, expectedError)
+
+	c.sut.GetArchivePosts(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
@@ -4,7 +4,11 @@ import (
 	"github.com/wlachs/blog/api/types"
 	"github.com/wlachs/blog/internal/container"
 	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/jwt"
+	"github.com/wlachs/blog/internal/repository"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/wlachs/blog/internal/services"
@@ -13,51 +17,259 @@ import (
 // AuthController interface defining authentication-related methods to handler HTTP requests.
 type AuthController interface {
 	Login(c *gin.Context)
+	Refresh(c *gin.Context)
+	Logout(c *gin.Context)
 	Protect(c *gin.Context)
+	RequireScope(scope repository.Scope) gin.HandlerFunc
+	RequireSession(c *gin.Context)
+	GetJWKS(c *gin.Context)
 }
 
 // authController is a concrete implementation of the AuthController interface.
 type authController struct {
-	cont        container.Container
-	userService services.UserService
+	cont             container.Container
+	userService      services.UserService
+	tokenService     services.TokenService
+	apiTokenService  services.APITokenService
+	twoFactorService services.TwoFactorService
 }
 
 // CreateAuthController instantiates the AuthController using the application container.
-func CreateAuthController(cont container.Container, userService services.UserService) AuthController {
-	return &authController{cont, userService}
+func CreateAuthController(cont container.Container, userService services.UserService, tokenService services.TokenService,
+	apiTokenService services.APITokenService, twoFactorService services.TwoFactorService) AuthController {
+	return &authController{cont, userService, tokenService, apiTokenService, twoFactorService}
 }
 
 // Login middleware. Top level handler of /login POST requests.
+// Starts a new session and returns its access and refresh tokens in the response headers.
+// Users with two-factor authentication have to provide a TOTP or recovery code along with their password.
+// If it is missing, the X-Two-Factor-Required header tells the client to ask for it.
 func (auth authController) Login(c *gin.Context) {
 	userService := auth.userService
+	tokenService := auth.tokenService
+	twoFactorService := auth.twoFactorService
 
 	var u types.DoLoginJSONBody
 	if err := c.BindJSON(&u); err != nil {
 		return
 	}
 
-	token, err := userService.AuthenticateUser(u.UserID, u.Password)
+	user, err := userService.AuthenticateUser(u.UserID, u.Password)
 
 	if err != nil {
 		_ = c.AbortWithError(http.StatusUnauthorized, err)
 		return
 	}
 
-	c.Header("X-Auth-Token", token)
+	var code string
+	if u.Code != nil {
+		code = *u.Code
+	}
+
+	switch err = twoFactorService.Verify(user, code); err.(type) {
+	case nil:
+	case errortypes.TwoFactorRequiredError:
+		c.Header("X-Two-Factor-Required", "true")
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+		return
+	case errortypes.InvalidTwoFactorCodeError:
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+		return
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTwoFactorError{})
+		return
+	}
+
+	tokens, err := tokenService.IssueTokens(user)
+
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTokenError{})
+		return
+	}
+
+	setTokenHeaders(c, tokens)
+	c.Status(http.StatusOK)
+}
+
+// Refresh middleware. Top level handler of /token/refresh POST requests.
+// Exchanges a refresh token for a new token pair of the same session.
+func (auth authController) Refresh(c *gin.Context) {
+	tokenService := auth.tokenService
+
+	var body types.RefreshTokenJSONBody
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	tokens, err := tokenService.RefreshTokens(body.RefreshToken)
+
+	switch err.(type) {
+	case nil:
+		setTokenHeaders(c, tokens)
+		c.Status(http.StatusOK)
+	case errortypes.InvalidRefreshTokenError:
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTokenError{})
+	}
+}
+
+// Logout middleware. Top level handler of /logout POST requests.
+// Ends the session of the access token, so neither its access nor its refresh tokens can be used anymore.
+func (auth authController) Logout(c *gin.Context) {
+	tokenService := auth.tokenService
+
+	if err := tokenService.RevokeSession(c.GetString("SessionID")); err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTokenError{})
+		return
+	}
+
 	c.Status(http.StatusOK)
 }
 
 // Protect middleware. Can be used before any middleware to make sure only authenticated users are able to use an endpoint.
+// Both access tokens and personal API tokens are accepted.
+// The user, role and session carried by the token are stored in the context for later handlers.
 func (auth authController) Protect(c *gin.Context) {
-	jwtUtils := auth.cont.GetJWTUtils()
 	token := c.Request.Header.Get("X-Auth-Token")
 
 	if token == "" {
 		_ = c.AbortWithError(http.StatusUnauthorized, errortypes.MissingAuthTokenError{})
-	} else if u, err := jwtUtils.ParseJWT(token); err == nil {
-		c.Set("UserID", u)
-		c.Next()
+		return
+	}
+
+	var err error
+	if strings.HasPrefix(token, services.APITokenPrefix) {
+		err = auth.protectWithAPIToken(c, token)
 	} else {
-		_ = c.AbortWithError(http.StatusUnauthorized, errortypes.InvalidAuthTokenError{})
+		err = auth.protectWithAccessToken(c, token)
+	}
+
+	switch err.(type) {
+	case nil:
+		c.Next()
+	case errortypes.InvalidAuthTokenError:
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTokenError{})
+	}
+}
+
+// protectWithAccessToken validates an access token and stores its user, role and session in the context.
+func (auth authController) protectWithAccessToken(c *gin.Context, token string) error {
+	tokenService := auth.tokenService
+
+	claims, err := tokenService.ValidateAccessToken(token)
+	if err != nil {
+		return err
+	}
+
+	c.Set("UserID", claims.UserName)
+	c.Set("Role", claims.Role)
+	c.Set("SessionID", claims.SessionID)
+	return nil
+}
+
+// protectWithAPIToken validates a personal API token and stores its user, the current role of the user and its scopes in the context.
+func (auth authController) protectWithAPIToken(c *gin.Context, token string) error {
+	apiTokenService := auth.apiTokenService
+
+	apiToken, err := apiTokenService.ValidateAPIToken(token)
+	if err != nil {
+		return err
+	}
+
+	c.Set("UserID", apiToken.User.UserName)
+	c.Set("Role", string(apiToken.User.Role))
+	c.Set("Scopes", apiToken.Scopes)
+	return nil
+}
+
+// RequireScope middleware. Can be used after Protect to make sure personal API tokens are only used within their scopes.
+// Access tokens are not restricted by scopes.
+func (auth authController) RequireScope(scope repository.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := c.Get("Scopes")
+		if ok && !slices.Contains(scopes.([]repository.Scope), scope) {
+			_ = c.AbortWithError(http.StatusForbidden, errortypes.MissingScopeError{Scope: string(scope)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireSession middleware. Can be used after Protect to make sure an endpoint cannot be used with personal API tokens.
+func (auth authController) RequireSession(c *gin.Context) {
+	if c.GetString("SessionID") == "" {
+		_ = c.AbortWithError(http.StatusForbidden, errortypes.SessionRequiredError{})
+		return
 	}
+
+	c.Next()
+}
+
+// GetJWKS middleware. Top level handler of /.well-known/jwks.json GET requests.
+// Lists the public keys verifying the access tokens.
+func (auth authController) GetJWKS(c *gin.Context) {
+	tokenService := auth.tokenService
+
+	c.JSON(http.StatusOK, types.JSONWebKeySet{Keys: populateJSONWebKeys(tokenService.PublicKeys())})
+}
+
+// setTokenHeaders returns the token pair in the response headers.
+func setTokenHeaders(c *gin.Context, tokens services.TokenPair) {
+	c.Header("X-Auth-Token", tokens.AccessToken)
+	c.Header("X-Refresh-Token", tokens.RefreshToken)
+}
+
+// actor returns the authenticated user of the request, as stored in the context by the Protect middleware.
+func actor(c *gin.Context) services.Actor {
+	return services.Actor{
+		UserName: c.GetString("UserID"),
+		Role:     repository.Role(c.GetString("Role")),
+	}
+}
+
+// authorized reports whether a permission check passed.
+// If it didn't, the request is aborted with a status matching the error.
+func authorized(c *gin.Context, err error) bool {
+	switch err.(type) {
+	case nil:
+		return true
+	case errortypes.PermissionDeniedError:
+		_ = c.AbortWithError(http.StatusForbidden, err)
+	case errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPermissionError{})
+	}
+	return false
+}
+
+// populateJSONWebKeys maps the public keys to a types.JSONWebKey slice
+func populateJSONWebKeys(keys []jwt.JSONWebKey) []types.JSONWebKey {
+	k := make([]types.JSONWebKey, 0, len(keys))
+	for _, key := range keys {
+		k = append(k, types.JSONWebKey{
+			Kty: types.JSONWebKeyKty(key.KeyType),
+			Kid: key.KeyID,
+			Use: key.Use,
+			Alg: types.JSONWebKeyAlg(key.Algorithm),
+			N:   populateKeyParameter(key.N),
+			E:   populateKeyParameter(key.E),
+			Crv: populateKeyParameter(key.Curve),
+			X:   populateKeyParameter(key.X),
+		})
+	}
+	return k
+}
+
+// populateKeyParameter omits the key parameters not used by the key type
+func populateKeyParameter(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
 }
@@ -1,6 +1,7 @@
 package controller_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -8,8 +9,11 @@ import (
 	"github.com/wlachs/blog/internal/container"
 	"github.com/wlachs/blog/internal/controller"
 	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/jwt"
 	"github.com/wlachs/blog/internal/logger"
 	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
 	"github.com/wlachs/blog/internal/test"
 	"go.uber.org/mock/gomock"
 	"net/http/httptest"
@@ -18,11 +22,13 @@ import (
 
 // authTestContext contains commonly used services, controllers and other objects relevant for testing the AuthController.
 type authTestContext struct {
-	mockUserService *mocks.MockUserService
-	mockJwtUtils    *mocks.MockTokenUtils
-	sut             controller.AuthController
-	ctx             *gin.Context
-	rec             *httptest.ResponseRecorder
+	mockUserService      *mocks.MockUserService
+	mockTokenService     *mocks.MockTokenService
+	mockAPITokenService  *mocks.MockAPITokenService
+	mockTwoFactorService *mocks.MockTwoFactorService
+	sut                  controller.AuthController
+	ctx                  *gin.Context
+	rec                  *httptest.ResponseRecorder
 }
 
 // createAuthControllerContext creates the context for testing the AuthController and reduces code duplication.
@@ -30,13 +36,15 @@ func createAuthControllerContext(t *testing.T) *authTestContext {
 	t.Helper()
 
 	mockCtrl := gomock.NewController(t)
-	mockJwtUtils := mocks.NewMockTokenUtils(mockCtrl)
 	mockUserService := mocks.NewMockUserService(mockCtrl)
-	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, mockJwtUtils)
-	sut := controller.CreateAuthController(cont, mockUserService)
+	mockTokenService := mocks.NewMockTokenService(mockCtrl)
+	mockAPITokenService := mocks.NewMockAPITokenService(mockCtrl)
+	mockTwoFactorService := mocks.NewMockTwoFactorService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateAuthController(cont, mockUserService, mockTokenService, mockAPITokenService, mockTwoFactorService)
 	ctx, rec := test.CreateControllerContext()
 
-	return &authTestContext{mockUserService, mockJwtUtils, sut, ctx, rec}
+	return &authTestContext{mockUserService, mockTokenService, mockAPITokenService, mockTwoFactorService, sut, ctx, rec}
 }
 
 // TestAuthController_Login tests the login method on the AuthController with valid data.
@@ -54,7 +62,37 @@ func TestAuthController_Login(t *testing.T) {
 		"password": input.Password,
 	})
 
-	c.mockUserService.EXPECT().AuthenticateUser(input.UserID, input.Password).Return("token", nil)
+	user := repository.User{UserName: input.UserID}
+	tokens := services.TokenPair{AccessToken: "token", RefreshToken: "refresh"}
+
+	c.mockUserService.EXPECT().AuthenticateUser(input.UserID, input.Password).Return(user, nil)
+	c.mockTwoFactorService.EXPECT().Verify(user, "").Return(nil)
+	c.mockTokenService.EXPECT().IssueTokens(user).Return(tokens, nil)
+
+	c.sut.Login(c.ctx)
+	assert.Nil(t, c.ctx.Errors, "should complete without errors")
+	assert.Equal(t, "token", c.rec.Header().Get("X-Auth-Token"))
+	assert.Equal(t, "refresh", c.rec.Header().Get("X-Refresh-Token"))
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_Login_Two_Factor tests the login method on the AuthController with a two-factor authentication code.
+func TestAuthController_Login_Two_Factor(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{
+		"userID":   "TestUser",
+		"password": "TestPW1234$",
+		"code":     "123456",
+	})
+
+	user := repository.User{UserName: "TestUser"}
+	tokens := services.TokenPair{AccessToken: "token", RefreshToken: "refresh"}
+
+	c.mockUserService.EXPECT().AuthenticateUser("TestUser", "TestPW1234$").Return(user, nil)
+	c.mockTwoFactorService.EXPECT().Verify(user, "123456").Return(nil)
+	c.mockTokenService.EXPECT().IssueTokens(user).Return(tokens, nil)
 
 	c.sut.Login(c.ctx)
 	assert.Nil(t, c.ctx.Errors, "should complete without errors")
@@ -62,6 +100,60 @@ func TestAuthController_Login(t *testing.T) {
 	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
 }
 
+// TestAuthController_Login_Two_Factor_Required tests the login method on the AuthController without the required
+// two-factor authentication code.
+func TestAuthController_Login_Two_Factor_Required(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{
+		"userID":   "TestUser",
+		"password": "TestPW1234$",
+	})
+
+	user := repository.User{UserName: "TestUser"}
+	expectedError := errortypes.TwoFactorRequiredError{}
+
+	c.mockUserService.EXPECT().AuthenticateUser("TestUser", "TestPW1234$").Return(user, nil)
+	c.mockTwoFactorService.EXPECT().Verify(user, "").Return(expectedError)
+
+	c.sut.Login(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected one error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, "true", c.rec.Header().Get("X-Two-Factor-Required"), "client should be asked for a code")
+	assert.Empty(t, c.rec.Header().Get("X-Auth-Token"), "no token should be issued")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_Login_Two_Factor_Invalid_Code tests the login method on the AuthController with an incorrect
+// two-factor authentication code.
+func TestAuthController_Login_Two_Factor_Invalid_Code(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{
+		"userID":   "TestUser",
+		"password": "TestPW1234$",
+		"code":     "000000",
+	})
+
+	user := repository.User{UserName: "TestUser"}
+	expectedError := errortypes.InvalidTwoFactorCodeError{}
+
+	c.mockUserService.EXPECT().AuthenticateUser("TestUser", "TestPW1234$").Return(user, nil)
+	c.mockTwoFactorService.EXPECT().Verify(user, "000000").Return(expectedError)
+
+	c.sut.Login(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected one error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Empty(t, c.rec.Header().Get("X-Two-Factor-Required"), "code was provided")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
 // TestAuthController_Login_Incorrect_Password tests the login method on the AuthController with valid data but incorrect password.
 func TestAuthController_Login_Incorrect_Password(t *testing.T) {
 	t.Parallel()
@@ -78,7 +170,7 @@ func TestAuthController_Login_Incorrect_Password(t *testing.T) {
 	})
 
 	expectedError := errortypes.IncorrectUsernameOrPasswordError{}
-	c.mockUserService.EXPECT().AuthenticateUser(input.UserID, input.Password).Return("", expectedError)
+	c.mockUserService.EXPECT().AuthenticateUser(input.UserID, input.Password).Return(repository.User{}, expectedError)
 
 	c.sut.Login(c.ctx)
 
@@ -106,12 +198,15 @@ func TestAuthController_Protect(t *testing.T) {
 	c := createAuthControllerContext(t)
 
 	c.ctx.Request.Header.Add("X-Auth-Token", "token")
-	c.mockJwtUtils.EXPECT().ParseJWT("token").Return("test user", nil)
+	claims := jwt.Claims{UserName: "test user", Role: "editor", SessionID: "session"}
+	c.mockTokenService.EXPECT().ValidateAccessToken("token").Return(claims, nil)
 
 	c.sut.Protect(c.ctx)
 
 	assert.Nil(t, c.ctx.Errors, "expected no errors")
 	assert.Equal(t, "test user", c.ctx.GetString("UserID"), "incorrect user")
+	assert.Equal(t, "editor", c.ctx.GetString("Role"), "incorrect role")
+	assert.Equal(t, "session", c.ctx.GetString("SessionID"), "incorrect session")
 	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
 }
 
@@ -137,12 +232,203 @@ func TestAuthController_Protect_Token_Invalid(t *testing.T) {
 
 	expectedError := errortypes.InvalidAuthTokenError{}
 	c.ctx.Request.Header.Add("X-Auth-Token", "token")
-	c.mockJwtUtils.EXPECT().ParseJWT("token").Return("", fmt.Errorf("internal error"))
+	c.mockTokenService.EXPECT().ValidateAccessToken("token").Return(jwt.Claims{}, expectedError)
+
+	c.sut.Protect(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_Protect_Session_Check_Failed tests the protect middleware of the AuthController
+// when the session of the token cannot be checked.
+func TestAuthController_Protect_Session_Check_Failed(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	c.ctx.Request.Header.Add("X-Auth-Token", "token")
+	c.mockTokenService.EXPECT().ValidateAccessToken("token").Return(jwt.Claims{}, fmt.Errorf("internal error"))
 
 	c.sut.Protect(c.ctx)
 
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_Refresh tests exchanging a valid refresh token for a new token pair.
+func TestAuthController_Refresh(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	tokens := services.TokenPair{AccessToken: "newToken", RefreshToken: "newRefresh"}
+
+	test.MockJsonPost(c.ctx, types.RefreshTokenJSONBody{RefreshToken: "refresh"})
+	c.mockTokenService.EXPECT().RefreshTokens("refresh").Return(tokens, nil)
+
+	c.sut.Refresh(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without errors")
+	assert.Equal(t, "newToken", c.rec.Header().Get("X-Auth-Token"))
+	assert.Equal(t, "newRefresh", c.rec.Header().Get("X-Refresh-Token"))
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_Refresh_Invalid_Token tests exchanging a revoked refresh token.
+func TestAuthController_Refresh_Invalid_Token(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	expectedError := errortypes.InvalidRefreshTokenError{}
+
+	test.MockJsonPost(c.ctx, types.RefreshTokenJSONBody{RefreshToken: "refresh"})
+	c.mockTokenService.EXPECT().RefreshTokens("refresh").Return(services.TokenPair{}, expectedError)
+
+	c.sut.Refresh(c.ctx)
+
 	errors := c.ctx.Errors.Errors()
 	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
 	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
 	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
 }
+
+// TestAuthController_Logout tests ending the session of the current access token.
+func TestAuthController_Logout(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	c.ctx.Set("SessionID", "session")
+	c.mockTokenService.EXPECT().RevokeSession("session").Return(nil)
+
+	c.sut.Logout(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_GetJWKS tests listing the public keys verifying the access tokens.
+func TestAuthController_GetJWKS(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	keys := []jwt.JSONWebKey{
+		{KeyType: "OKP", KeyID: "new", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "x"},
+		{KeyType: "RSA", KeyID: "old", Use: "sig", Algorithm: "RS256", N: "n", E: "AQAB"},
+	}
+	c.mockTokenService.EXPECT().PublicKeys().Return(keys)
+
+	c.sut.GetJWKS(c.ctx)
+
+	var output types.JSONWebKeySet
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, 2, len(output.Keys), "incorrect number of keys")
+	assert.Equal(t, "new", output.Keys[0].Kid, "incorrect key ID")
+	assert.Equal(t, types.EdDSA, output.Keys[0].Alg, "incorrect algorithm")
+	assert.Equal(t, "x", *output.Keys[0].X, "incorrect public key")
+	assert.Nil(t, output.Keys[0].N, "RSA parameters should be omitted for OKP keys")
+	assert.Equal(t, "AQAB", *output.Keys[1].E, "incorrect exponent")
+	assert.Nil(t, output.Keys[1].Crv, "OKP parameters should be omitted for RSA keys")
+}
+
+// TestAuthController_Protect_API_Token tests the protect middleware of the AuthController with a personal API token.
+func TestAuthController_Protect_API_Token(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	token := services.APITokenPrefix + "secret"
+	c.ctx.Request.Header.Add("X-Auth-Token", token)
+	apiToken := repository.APIToken{
+		ID:     1,
+		Scopes: []repository.Scope{repository.ScopePostsWrite},
+		User:   repository.User{UserName: "ci", Role: repository.RoleAuthor},
+	}
+	c.mockAPITokenService.EXPECT().ValidateAPIToken(token).Return(apiToken, nil)
+
+	c.sut.Protect(c.ctx)
+
+	scopes, _ := c.ctx.Get("Scopes")
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, "ci", c.ctx.GetString("UserID"), "incorrect user")
+	assert.Equal(t, "author", c.ctx.GetString("Role"), "incorrect role")
+	assert.Equal(t, "", c.ctx.GetString("SessionID"), "API tokens should not have a session")
+	assert.Equal(t, apiToken.Scopes, scopes, "incorrect scopes")
+}
+
+// TestAuthController_Protect_Invalid_API_Token tests the protect middleware of the AuthController with an unknown personal API token.
+func TestAuthController_Protect_Invalid_API_Token(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	token := services.APITokenPrefix + "secret"
+	c.ctx.Request.Header.Add("X-Auth-Token", token)
+	expectedError := errortypes.InvalidAuthTokenError{}
+	c.mockAPITokenService.EXPECT().ValidateAPIToken(token).Return(repository.APIToken{}, expectedError)
+
+	c.sut.Protect(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_RequireScope tests that personal API tokens may use the routes of their scopes.
+func TestAuthController_RequireScope(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	c.ctx.Set("Scopes", []repository.Scope{repository.ScopePostsRead, repository.ScopePostsWrite})
+
+	c.sut.RequireScope(repository.ScopePostsWrite)(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_RequireScope_Missing_Scope tests that personal API tokens may not be used beyond their scopes.
+func TestAuthController_RequireScope_Missing_Scope(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	c.ctx.Set("Scopes", []repository.Scope{repository.ScopePostsRead})
+	expectedError := errortypes.MissingScopeError{Scope: "users:write"}
+
+	c.sut.RequireScope(repository.ScopeUsersWrite)(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_RequireScope_Access_Token tests that access tokens are not restricted by scopes.
+func TestAuthController_RequireScope_Access_Token(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	c.ctx.Set("SessionID", "session")
+
+	c.sut.RequireScope(repository.ScopeUsersWrite)(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestAuthController_RequireSession tests that endpoints requiring a session reject personal API tokens.
+func TestAuthController_RequireSession(t *testing.T) {
+	t.Parallel()
+	c := createAuthControllerContext(t)
+
+	c.ctx.Set("Scopes", []repository.Scope{repository.ScopeUsersWrite})
+	expectedError := errortypes.SessionRequiredError{}
+
+	c.sut.RequireSession(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
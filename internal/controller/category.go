@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+)
+
+// CategoryController interface defining category-related middleware methods to handle HTTP requests
+type CategoryController interface {
+	AddCategory(c *gin.Context)
+	UpdateCategory(c *gin.Context)
+	DeleteCategory(c *gin.Context)
+	GetCategory(c *gin.Context)
+	GetCategories(c *gin.Context)
+}
+
+// categoryController is a concrete implementation of the CategoryController interface
+type categoryController struct {
+	cont              container.Container
+	categoryService   services.CategoryService
+	permissionService services.PermissionService
+}
+
+// CreateCategoryController instantiates a category controller using the application container.
+func CreateCategoryController(cont container.Container, categoryService services.CategoryService,
+	permissionService services.PermissionService) CategoryController {
+	return &categoryController{cont, categoryService, permissionService}
+}
+
+// AddCategory middleware. Top level handler of /categories/:CategoryID POST requests.
+// Only admins and editors may add categories.
+func (controller categoryController) AddCategory(c *gin.Context) {
+	categoryService := controller.categoryService
+	permissionService := controller.permissionService
+
+	if !authorized(c, permissionService.CheckTaxonomyManagement(actor(c))) {
+		return
+	}
+
+	var body types.NewCategory
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	categoryID, _ := c.Params.Get("CategoryID")
+
+	newCategory := repository.Category{
+		URLHandle: categoryID,
+		Parent:    populateCategoryModel(body.Parent),
+	}
+
+	if body.Name != nil {
+		newCategory.Name = *body.Name
+	}
+
+	category, err := categoryService.AddCategory(newCategory)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusCreated, populateCategory(category))
+	case errortypes.MissingCategoryNameError, errortypes.CategoryNotFoundError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.DuplicateElementError:
+		_ = c.AbortWithError(http.StatusConflict, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedCategoryError{URLHandle: categoryID})
+	}
+}
+
+// UpdateCategory middleware. Top level handler of /categories/:CategoryID PUT requests.
+// Renames the category or moves it below another parent. Only admins and editors may update categories.
+func (controller categoryController) UpdateCategory(c *gin.Context) {
+	categoryService := controller.categoryService
+	permissionService := controller.permissionService
+
+	if !authorized(c, permissionService.CheckTaxonomyManagement(actor(c))) {
+		return
+	}
+
+	var body types.UpdatedCategory
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	categoryID, _ := c.Params.Get("CategoryID")
+
+	updatedCategory := repository.Category{
+		URLHandle: categoryID,
+		Parent:    populateCategoryModel(body.Parent),
+	}
+
+	if body.Name != nil {
+		updatedCategory.Name = *body.Name
+	}
+
+	category, err := categoryService.UpdateCategory(updatedCategory)
+
+	switch e := err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateCategory(category))
+	case errortypes.InvalidCategoryParentError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.CategoryNotFoundError:
+		// A missing parent is a bad request, a missing category is not found
+		if e.URLHandle == categoryID {
+			_ = c.AbortWithError(http.StatusNotFound, err)
+		} else {
+			_ = c.AbortWithError(http.StatusBadRequest, err)
+		}
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedCategoryError{URLHandle: categoryID})
+	}
+}
+
+// DeleteCategory middleware. Top level handler of /categories/:CategoryID DELETE requests.
+// Only admins and editors may delete categories.
+func (controller categoryController) DeleteCategory(c *gin.Context) {
+	categoryService := controller.categoryService
+	permissionService := controller.permissionService
+
+	if !authorized(c, permissionService.CheckTaxonomyManagement(actor(c))) {
+		return
+	}
+
+	categoryID, _ := c.Params.Get("CategoryID")
+	err := categoryService.DeleteCategory(categoryID)
+
+	switch err.(type) {
+	case nil:
+		c.Status(http.StatusOK)
+	case errortypes.CategoryNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errortypes.CategoryNotEmptyError:
+		_ = c.AbortWithError(http.StatusConflict, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedCategoryError{URLHandle: categoryID})
+	}
+}
+
+// GetCategory middleware. Top level handler of /categories/:CategoryID GET requests.
+func (controller categoryController) GetCategory(c *gin.Context) {
+	categoryService := controller.categoryService
+
+	categoryID, _ := c.Params.Get("CategoryID")
+	category, err := categoryService.GetCategory(categoryID)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateCategory(category))
+	case errortypes.CategoryNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedCategoryError{URLHandle: categoryID})
+	}
+}
+
+// GetCategories middleware. Top level handler of /categories GET requests.
+func (controller categoryController) GetCategories(c *gin.Context) {
+	categoryService := controller.categoryService
+
+	categories, err := categoryService.GetCategories()
+
+	switch err.(type) {
+	case nil:
+		cat := populateCategories(categories)
+		c.IndentedJSON(http.StatusOK, types.Categories{Categories: &cat})
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedCategoryError{})
+	}
+}
+
+// populateCategoryModel maps a category ID of a request to a repository.Category model.
+// If no ID was provided, nil is returned to leave the referenced category unchanged.
+func populateCategoryModel(id *string) *repository.Category {
+	if id == nil {
+		return nil
+	}
+
+	return &repository.Category{URLHandle: *id}
+}
+
+// populateCategory maps a repository.Category model to types.Category
+func populateCategory(category repository.Category) types.Category {
+	c := types.Category{
+		Id:   category.URLHandle,
+		Name: category.Name,
+	}
+
+	if category.Parent != nil {
+		c.Parent = &category.Parent.URLHandle
+	}
+
+	if len(category.Children) > 0 {
+		children := populateCategories(category.Children)
+		c.Children = &children
+	}
+
+	return c
+}
+
+// populateCategories maps a slice of repository.Category models to a types.Category slice
+func populateCategories(categories []repository.Category) []types.Category {
+	c := make([]types.Category, 0, len(categories))
+
+	for _, category := range categories {
+		c = append(c, populateCategory(category))
+	}
+
+	return c
+}
@@ -0,0 +1,341 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"testing"
+)
+
+// categoryTestContext contains commonly used services, controllers and other objects relevant for testing the CategoryController.
+type categoryTestContext struct {
+	mockCategoryService   *mocks.MockCategoryService
+	mockPermissionService *mocks.MockPermissionService
+	sut                   controller.CategoryController
+	ctx                   *gin.Context
+	rec                   *httptest.ResponseRecorder
+}
+
+// createCategoryControllerContext creates the context for testing the CategoryController and reduces code duplication.
+func createCategoryControllerContext(t *testing.T) *categoryTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockCategoryService := mocks.NewMockCategoryService(mockCtrl)
+	mockPermissionService := mocks.NewMockPermissionService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateCategoryController(cont, mockCategoryService, mockPermissionService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &categoryTestContext{mockCategoryService, mockPermissionService, sut, ctx, rec}
+}
+
+// TestCategoryController_AddCategory tests adding a new category below an existing parent.
+func TestCategoryController_AddCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	name := "Go"
+	parent := "engineering"
+	input := types.NewCategory{
+		Name:   &name,
+		Parent: &parent,
+	}
+	categoryModel := repository.Category{
+		URLHandle: "go",
+		Name:      name,
+		Parent:    &repository.Category{URLHandle: parent},
+	}
+	expectedCategory := types.Category{
+		Id:     "go",
+		Name:   name,
+		Parent: &parent,
+	}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().AddCategory(categoryModel).Return(categoryModel, nil)
+
+	c.sut.AddCategory(c.ctx)
+
+	var output types.Category
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedCategory, output, "incorrect output body")
+	assert.Equal(t, 201, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_AddCategory_Duplicate_Category tests adding a category with an existing ID.
+func TestCategoryController_AddCategory_Duplicate_Category(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	name := "Go"
+	expectedError := errortypes.DuplicateElementError{Key: "go"}
+
+	test.MockJsonPost(c.ctx, types.NewCategory{Name: &name})
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().AddCategory(gomock.Any()).Return(repository.Category{}, expectedError)
+
+	c.sut.AddCategory(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 409, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_AddCategory_Missing_Name tests adding a category without a name.
+func TestCategoryController_AddCategory_Missing_Name(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	expectedError := errortypes.MissingCategoryNameError{}
+
+	test.MockJsonPost(c.ctx, types.NewCategory{})
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().AddCategory(repository.Category{URLHandle: "go"}).Return(repository.Category{}, expectedError)
+
+	c.sut.AddCategory(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_UpdateCategory tests renaming a category.
+func TestCategoryController_UpdateCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	name := "Golang"
+	categoryModel := repository.Category{URLHandle: "go", Name: name}
+
+	test.MockJsonPost(c.ctx, types.UpdatedCategory{Name: &name})
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().UpdateCategory(categoryModel).Return(categoryModel, nil)
+
+	c.sut.UpdateCategory(c.ctx)
+
+	var output types.Category
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Category{Id: "go", Name: name}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_UpdateCategory_Missing_Category tests updating a non-existing category.
+func TestCategoryController_UpdateCategory_Missing_Category(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "go"}
+
+	test.MockJsonPost(c.ctx, types.UpdatedCategory{})
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().UpdateCategory(gomock.Any()).Return(repository.Category{}, expectedError)
+
+	c.sut.UpdateCategory(c.ctx)
+
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_UpdateCategory_Missing_Parent tests moving a category below a non-existing parent.
+func TestCategoryController_UpdateCategory_Missing_Parent(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	parent := "rust"
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: parent}
+
+	test.MockJsonPost(c.ctx, types.UpdatedCategory{Parent: &parent})
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().UpdateCategory(gomock.Any()).Return(repository.Category{}, expectedError)
+
+	c.sut.UpdateCategory(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_DeleteCategory tests deleting a category.
+func TestCategoryController_DeleteCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().DeleteCategory("go").Return(nil)
+
+	c.sut.DeleteCategory(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_DeleteCategory_Not_Empty tests deleting a category with subcategories.
+func TestCategoryController_DeleteCategory_Not_Empty(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	expectedError := errortypes.CategoryNotEmptyError{URLHandle: "engineering"}
+
+	c.ctx.AddParam("CategoryID", "engineering")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockCategoryService.EXPECT().DeleteCategory("engineering").Return(expectedError)
+
+	c.sut.DeleteCategory(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 409, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_AddCategory_Forbidden tests that authors cannot add categories.
+func TestCategoryController_AddCategory_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(author).Return(expectedError)
+
+	c.sut.AddCategory(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_UpdateCategory_Forbidden tests that authors cannot update categories.
+func TestCategoryController_UpdateCategory_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(author).Return(expectedError)
+
+	c.sut.UpdateCategory(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_DeleteCategory_Forbidden tests that authors cannot delete categories.
+func TestCategoryController_DeleteCategory_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(author).Return(expectedError)
+
+	c.sut.DeleteCategory(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_GetCategory_Missing_Category tests retrieving a non-existing category.
+func TestCategoryController_GetCategory_Missing_Category(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "go"}
+
+	c.ctx.AddParam("CategoryID", "go")
+	c.mockCategoryService.EXPECT().GetCategory("go").Return(repository.Category{}, expectedError)
+
+	c.sut.GetCategory(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_GetCategories tests retrieving the category tree.
+func TestCategoryController_GetCategories(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	categoryModels := []repository.Category{
+		{
+			URLHandle: "engineering",
+			Name:      "Engineering",
+			Children:  []repository.Category{{URLHandle: "go", Name: "Go"}},
+		},
+	}
+	children := []types.Category{{Id: "go", Name: "Go"}}
+	expectedCategories := []types.Category{{Id: "engineering", Name: "Engineering", Children: &children}}
+
+	c.mockCategoryService.EXPECT().GetCategories().Return(categoryModels, nil)
+
+	c.sut.GetCategories(c.ctx)
+
+	var output types.Categories
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Categories{Categories: &expectedCategories}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestCategoryController_GetCategories_Unexpected_Error tests handling an unexpected error while retrieving the categories.
+func TestCategoryController_GetCategories_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createCategoryControllerContext(t)
+
+	expectedError := errortypes.UnexpectedCategoryError{}
+
+	c.mockCategoryService.EXPECT().GetCategories().Return(nil, fmt.Errorf("unexpected error"))
+
+	c.sut.GetCategories(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
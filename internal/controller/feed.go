@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/feeds"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// FeedController interface defining feed-related middleware methods to handle HTTP requests
+type FeedController interface {
+	GetRSS(c *gin.Context)
+	GetAtom(c *gin.Context)
+	GetJSON(c *gin.Context)
+}
+
+// feedController is a concrete implementation of the FeedController interface
+type feedController struct {
+	cont        container.Container
+	feedService services.FeedService
+}
+
+// feedEncoder serializes a feed in one of the supported formats
+type feedEncoder func(feed *feeds.Feed) (string, error)
+
+// CreateFeedController instantiates a feed controller using the application container.
+func CreateFeedController(cont container.Container, feedService services.FeedService) FeedController {
+	return &feedController{cont, feedService}
+}
+
+// GetRSS middleware. Top level handler of /feed.xml GET requests.
+func (controller feedController) GetRSS(c *gin.Context) {
+	controller.serveFeed(c, "application/rss+xml; charset=utf-8", (*feeds.Feed).ToRss)
+}
+
+// GetAtom middleware. Top level handler of /atom.xml GET requests.
+func (controller feedController) GetAtom(c *gin.Context) {
+	controller.serveFeed(c, "application/atom+xml; charset=utf-8", (*feeds.Feed).ToAtom)
+}
+
+// GetJSON middleware. Top level handler of /feed.json GET requests.
+func (controller feedController) GetJSON(c *gin.Context) {
+	controller.serveFeed(c, "application/feed+json; charset=utf-8", (*feeds.Feed).ToJSON)
+}
+
+// serveFeed builds the feed selected by the query parameters and writes it in the format of the encoder.
+// Clients that already have the current version of the feed only receive a 304 Not Modified response.
+// The version is identified by the content of the feed alone, since posts leaving the feed don't change its update
+// time.
+func (controller feedController) serveFeed(c *gin.Context, contentType string, encode feedEncoder) {
+	feedService := controller.feedService
+
+	feed, err := feedService.GetFeed(services.FeedOptions{
+		Author:  c.Query("author"),
+		Tag:     c.Query("tag"),
+		Content: services.FeedContent(c.Query("content")),
+	})
+
+	switch err.(type) {
+	case nil:
+	case errortypes.InvalidFeedContentError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	case errortypes.UserNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+		return
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedFeedError{})
+		return
+	}
+
+	body, err := encode(feed)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedFeedError{})
+		return
+	}
+
+	if notModified(c, []byte(body), time.Time{}) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
+
+// notModified sets the ETag and the Last-Modified headers of a cacheable response and checks whether the client
+// already has the same version of it. As usual, If-None-Match takes precedence over If-Modified-Since.
+func notModified(c *gin.Context, body []byte, lastModified time.Time) bool {
+	hash := sha256.Sum256(body)
+	etag := "\"" + hex.EncodeToString(hash[:16]) + "\""
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || lastModified.IsZero() {
+		return false
+	}
+
+	// HTTP dates have a precision of one second
+	return !lastModified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,224 @@
+package controller_test
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/feeds"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// feedTestContext contains commonly used services, controllers and other objects relevant for testing the FeedController.
+type feedTestContext struct {
+	mockFeedService *mocks.MockFeedService
+	sut             controller.FeedController
+	ctx             *gin.Context
+	rec             *httptest.ResponseRecorder
+}
+
+// createFeedControllerContext creates the context for testing the FeedController and reduces code duplication.
+func createFeedControllerContext(t *testing.T) *feedTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockFeedService := mocks.NewMockFeedService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateFeedController(cont, mockFeedService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &feedTestContext{mockFeedService, sut, ctx, rec}
+}
+
+// testFeed creates a feed with a single item updated at the given time.
+func testFeed(updated time.Time) *feeds.Feed {
+	return &feeds.Feed{
+		Title:   "Blog",
+		Link:    &feeds.Link{Href: "http://localhost:8080"},
+		Updated: updated,
+		Items: []*feeds.Item{
+			{
+				Id:      "http://localhost:8080/posts/testUrlHandle",
+				Title:   "testTitle",
+				Link:    &feeds.Link{Href: "http://localhost:8080/posts/testUrlHandle"},
+				Created: updated,
+				Updated: updated,
+			},
+		},
+	}
+}
+
+// TestFeedController_GetRSS tests getting the RSS feed of a tag.
+func TestFeedController_GetRSS(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	updated := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	c.ctx.Request.URL, _ = url.Parse("?tag=go&content=summary")
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{Tag: "go", Content: services.FeedContentSummary}).Return(testFeed(updated), nil)
+
+	c.sut.GetRSS(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, "application/rss+xml; charset=utf-8", c.rec.Header().Get("Content-Type"), "incorrect content type")
+	assert.Empty(t, c.rec.Header().Get("Last-Modified"), "feeds shouldn't be validated by their update time")
+	assert.NotEmpty(t, c.rec.Header().Get("ETag"), "missing entity tag")
+	assert.True(t, strings.Contains(c.rec.Body.String(), "<rss"), "response should be an RSS feed")
+}
+
+// TestFeedController_GetAtom tests getting the Atom feed of an author.
+func TestFeedController_GetAtom(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	c.ctx.Request.URL, _ = url.Parse("?author=testAuthor")
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{Author: "testAuthor"}).Return(testFeed(time.Now()), nil)
+
+	c.sut.GetAtom(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, "application/atom+xml; charset=utf-8", c.rec.Header().Get("Content-Type"), "incorrect content type")
+	assert.True(t, strings.Contains(c.rec.Body.String(), "<feed"), "response should be an Atom feed")
+}
+
+// TestFeedController_GetJSON tests getting the JSON feed.
+func TestFeedController_GetJSON(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(testFeed(time.Now()), nil)
+
+	c.sut.GetJSON(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, "application/feed+json; charset=utf-8", c.rec.Header().Get("Content-Type"), "incorrect content type")
+	assert.True(t, strings.Contains(c.rec.Body.String(), "https://jsonfeed.org/version/1"), "response should be a JSON feed")
+}
+
+// TestFeedController_GetRSS_If_None_Match tests that an unchanged feed isn't sent again to clients having its entity tag.
+func TestFeedController_GetRSS_If_None_Match(t *testing.T) {
+	t.Parallel()
+	first := createFeedControllerContext(t)
+	second := createFeedControllerContext(t)
+
+	feed := testFeed(time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC))
+
+	first.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(feed, nil)
+	first.sut.GetRSS(first.ctx)
+
+	second.ctx.Request.Header.Set("If-None-Match", "\"other\", "+first.rec.Header().Get("ETag"))
+	second.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(feed, nil)
+	second.sut.GetRSS(second.ctx)
+
+	assert.Nil(t, second.ctx.Errors, "expected no errors")
+	assert.Equal(t, 304, second.rec.Code, "incorrect response status")
+	assert.Empty(t, second.rec.Body.String(), "unchanged feed shouldn't be sent")
+}
+
+// TestFeedController_GetRSS_Removed_Post tests that a feed is sent again if a post left it without changing its update time.
+func TestFeedController_GetRSS_Removed_Post(t *testing.T) {
+	t.Parallel()
+	first := createFeedControllerContext(t)
+	second := createFeedControllerContext(t)
+
+	updated := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	feed := testFeed(updated)
+	feed.Add(&feeds.Item{
+		Id:      "http://localhost:8080/posts/removedUrlHandle",
+		Title:   "removedTitle",
+		Link:    &feeds.Link{Href: "http://localhost:8080/posts/removedUrlHandle"},
+		Created: updated.Add(-time.Hour),
+		Updated: updated.Add(-time.Hour),
+	})
+
+	first.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(feed, nil)
+	first.sut.GetRSS(first.ctx)
+
+	second.ctx.Request.Header.Set("If-None-Match", first.rec.Header().Get("ETag"))
+	second.ctx.Request.Header.Set("If-Modified-Since", "Tue, 05 Mar 2024 12:00:00 GMT")
+	second.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(testFeed(updated), nil)
+	second.sut.GetRSS(second.ctx)
+
+	assert.Nil(t, second.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, second.rec.Code, "incorrect response status")
+	assert.NotEqual(t, first.rec.Header().Get("ETag"), second.rec.Header().Get("ETag"), "entity tag should change")
+}
+
+// TestFeedController_GetRSS_If_Modified_Since tests that the modification time alone doesn't validate a feed.
+func TestFeedController_GetRSS_If_Modified_Since(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	c.ctx.Request.Header.Set("If-Modified-Since", "Tue, 05 Mar 2024 12:00:00 GMT")
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(testFeed(time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)), nil)
+
+	c.sut.GetRSS(c.ctx)
+
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestFeedController_GetRSS_Invalid_Content tests getting a feed with an unknown content mode.
+func TestFeedController_GetRSS_Invalid_Content(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	expectedError := errortypes.InvalidFeedContentError{Content: "partial"}
+
+	c.ctx.Request.URL, _ = url.Parse("?content=partial")
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{Content: "partial"}).Return(nil, expectedError)
+
+	c.sut.GetRSS(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestFeedController_GetRSS_Missing_Author tests getting the feed of an author that doesn't exist.
+func TestFeedController_GetRSS_Missing_Author(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	expectedError := errortypes.UserNotFoundError{UserName: "testAuthor"}
+
+	c.ctx.Request.URL, _ = url.Parse("?author=testAuthor")
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{Author: "testAuthor"}).Return(nil, expectedError)
+
+	c.sut.GetRSS(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestFeedController_GetRSS_Unexpected_Error tests handling unexpected errors of the feed.
+func TestFeedController_GetRSS_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createFeedControllerContext(t)
+
+	c.mockFeedService.EXPECT().GetFeed(services.FeedOptions{}).Return(nil, fmt.Errorf("error"))
+
+	c.sut.GetRSS(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, errortypes.UnexpectedFeedError{}.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
@@ -7,8 +7,12 @@ import (
 	"github.com/wlachs/blog/internal/errortypes"
 	"github.com/wlachs/blog/internal/repository"
 	"github.com/wlachs/blog/internal/services"
+	"gorm.io/gorm"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // PostController interface defining post-related middleware methods to handle HTTP requests
@@ -18,20 +22,31 @@ type PostController interface {
 	DeletePost(c *gin.Context)
 	GetPost(c *gin.Context)
 	GetPosts(c *gin.Context)
+	GetDraft(c *gin.Context)
+	GetDrafts(c *gin.Context)
+	PublishPost(c *gin.Context)
+	UnpublishPost(c *gin.Context)
+	ArchivePost(c *gin.Context)
+	RenamePost(c *gin.Context)
+	GetTrash(c *gin.Context)
+	RestorePost(c *gin.Context)
 }
 
 // postController is a concrete implementation of the PostController interface
 type postController struct {
-	cont        container.Container
-	postService services.PostService
+	cont              container.Container
+	postService       services.PostService
+	permissionService services.PermissionService
 }
 
 // CreatePostController instantiates a post controller using the application container.
-func CreatePostController(cont container.Container, postService services.PostService) PostController {
-	return &postController{cont, postService}
+func CreatePostController(cont container.Container, postService services.PostService,
+	permissionService services.PermissionService) PostController {
+	return &postController{cont, postService, permissionService}
 }
 
-// AddPost middleware. Top level handler of /posts/:PostID POST requests.
+// AddPost middleware. Top level handler of /posts and /posts/:PostID POST requests.
+// Without a post ID in the path, the ID is generated from the title of the post.
 func (controller postController) AddPost(c *gin.Context) {
 	postService := controller.postService
 
@@ -46,17 +61,27 @@ func (controller postController) AddPost(c *gin.Context) {
 
 	// Create new raw post item
 	newPost := repository.Post{
-		URLHandle: postID,
-		Title:     body.Title,
-		Summary:   body.Summary,
-		Body:      body.Body,
+		URLHandle:   postID,
+		Title:       body.Title,
+		Summary:     body.Summary,
+		Body:        body.Body,
+		BodyFormat:  populateBodyFormatModel(body.BodyFormat),
+		Tags:        populateTagModels(body.Tags),
+		Meta:        populateMetaModel(body.Meta),
+		Category:    populateCategoryModel(body.Category),
+		PublishAt:   body.PublishAt,
+		UnpublishAt: body.UnpublishAt,
 	}
 
 	post, err := postService.AddPost(newPost, author)
 
 	switch err.(type) {
 	case nil:
+		c.Header("Location", "/api/v0/posts/"+url.PathEscape(post.URLHandle))
 		c.IndentedJSON(http.StatusCreated, populatePost(post))
+	case errortypes.MissingTitleError, errortypes.InvalidPostScheduleError, errortypes.InvalidPostMetaError,
+		errortypes.InvalidBodyFormatError, errortypes.InvalidTagError, errortypes.CategoryNotFoundError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
 	case errortypes.DuplicateElementError:
 		_ = c.AbortWithError(http.StatusConflict, err)
 	default:
@@ -68,28 +93,43 @@ func (controller postController) AddPost(c *gin.Context) {
 // Updates an existing post with the given new metadata. If the post doesn't exist, an exception is thrown.
 func (controller postController) UpdatePost(c *gin.Context) {
 	postService := controller.postService
+	permissionService := controller.permissionService
+
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
 
 	var body types.UpdatedPost
 	if err := c.BindJSON(&body); err != nil {
 		return
 	}
 
-	// Set post ID from context
-	postID, _ := c.Params.Get("PostID")
+	// Set editor from context
+	editor := c.GetString("UserID")
 
 	// Create new raw post item
 	updatedPost := repository.Post{
-		URLHandle: postID,
-		Title:     body.Title,
-		Summary:   body.Summary,
-		Body:      body.Body,
+		URLHandle:   postID,
+		Title:       body.Title,
+		Summary:     body.Summary,
+		Body:        body.Body,
+		BodyFormat:  populateBodyFormatModel(body.BodyFormat),
+		Tags:        populateTagModels(body.Tags),
+		Meta:        populateMetaModel(body.Meta),
+		Category:    populateCategoryModel(body.Category),
+		PublishAt:   body.PublishAt,
+		UnpublishAt: body.UnpublishAt,
 	}
 
-	post, err := postService.UpdatePost(updatedPost)
+	post, err := postService.UpdatePost(updatedPost, editor)
 
 	switch err.(type) {
 	case nil:
 		c.IndentedJSON(http.StatusOK, populatePost(post))
+	case errortypes.InvalidPostScheduleError, errortypes.InvalidPostMetaError, errortypes.InvalidBodyFormatError,
+		errortypes.InvalidTagError, errortypes.CategoryNotFoundError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
 	case errortypes.PostNotFoundError:
 		_ = c.AbortWithError(http.StatusNotFound, err)
 	default:
@@ -100,9 +140,14 @@ func (controller postController) UpdatePost(c *gin.Context) {
 // DeletePost middleware. Top level handler of /posts/:PostID DELETE requests.
 func (controller postController) DeletePost(c *gin.Context) {
 	postService := controller.postService
+	permissionService := controller.permissionService
 
 	// Set post ID from context
 	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
 	err := postService.DeletePost(postID)
 
 	switch err.(type) {
@@ -130,6 +175,10 @@ func (controller postController) GetPost(c *gin.Context) {
 	switch err.(type) {
 	case nil:
 		c.IndentedJSON(http.StatusOK, populatePost(post))
+	case errortypes.PostMovedError:
+		newID := err.(errortypes.PostMovedError).NewURLHandle
+		c.Header("Location", "/api/v0/posts/"+url.PathEscape(newID))
+		c.IndentedJSON(http.StatusMovedPermanently, types.PostRedirect{Id: newID})
 	case errortypes.PostNotFoundError:
 		_ = c.AbortWithError(http.StatusNotFound, err)
 	default:
@@ -146,11 +195,151 @@ func (controller postController) GetPosts(c *gin.Context) {
 	var posts []repository.Post
 	var pages int
 
+	filter := repository.PostFilter{
+		Tag:      c.Query("tag"),
+		Category: c.Query("category"),
+		Meta:     populateMetaFilter(c),
+	}
+
+	// If no page query is provided, call the default service
+	if err != nil {
+		posts, pages, err = postService.GetPosts(filter)
+	} else {
+		posts, pages, err = postService.GetPostsPage(filter, pageId)
+	}
+
+	switch err.(type) {
+	case nil:
+		p := populatePostMetadataSlice(posts)
+		c.IndentedJSON(http.StatusOK, types.Posts{Posts: &p, Pages: &pages})
+	case errortypes.InvalidPostPageError, errortypes.InvalidPostMetaError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPostError{})
+	}
+}
+
+// GetDraft middleware. Top level handler of /drafts/:PostID GET requests.
+// Only the author of the draft is able to retrieve it.
+func (controller postController) GetDraft(c *gin.Context) {
+	postService := controller.postService
+
+	id, found := c.Params.Get("PostID")
+	if !found {
+		_ = c.AbortWithError(http.StatusBadRequest, errortypes.MissingUrlHandleError{})
+		return
+	}
+
+	author := c.GetString("UserID")
+	post, err := postService.GetDraft(id, author)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populatePost(post))
+	case errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPostError{URLHandle: id})
+	}
+}
+
+// GetDrafts middleware. Top level handler of /drafts GET requests.
+// Lists the drafts of the current user.
+func (controller postController) GetDrafts(c *gin.Context) {
+	postService := controller.postService
+	author := c.GetString("UserID")
+	page := c.Query("page")
+	pageId, err := strconv.Atoi(page)
+
+	var posts []repository.Post
+	var pages int
+
+	// If no page query is provided, call the default service
+	if err != nil {
+		posts, pages, err = postService.GetDrafts(author)
+	} else {
+		posts, pages, err = postService.GetDraftsPage(author, pageId)
+	}
+
+	switch err.(type) {
+	case nil:
+		p := populatePostMetadataSlice(posts)
+		c.IndentedJSON(http.StatusOK, types.Posts{Posts: &p, Pages: &pages})
+	case errortypes.InvalidPostPageError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPostError{})
+	}
+}
+
+// PublishPost middleware. Top level handler of /posts/:PostID/publish POST requests.
+func (controller postController) PublishPost(c *gin.Context) {
+	controller.changePostStatus(c, controller.postService.PublishPost)
+}
+
+// UnpublishPost middleware. Top level handler of /posts/:PostID/unpublish POST requests.
+func (controller postController) UnpublishPost(c *gin.Context) {
+	controller.changePostStatus(c, controller.postService.UnpublishPost)
+}
+
+// ArchivePost middleware. Top level handler of /posts/:PostID/archive POST requests.
+func (controller postController) ArchivePost(c *gin.Context) {
+	controller.changePostStatus(c, controller.postService.ArchivePost)
+}
+
+// RenamePost middleware. Top level handler of /posts/:PostID/rename POST requests.
+// Changes the ID of the post, while the former ID keeps redirecting to the post.
+func (controller postController) RenamePost(c *gin.Context) {
+	postService := controller.postService
+	permissionService := controller.permissionService
+
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
+	var body types.PostRename
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	post, err := postService.RenamePost(postID, body.Id)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populatePost(post))
+	case errortypes.MissingUrlHandleError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errortypes.DuplicateElementError:
+		_ = c.AbortWithError(http.StatusConflict, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPostError{URLHandle: postID})
+	}
+}
+
+// GetTrash middleware. Top level handler of /trash GET requests.
+// Admins and editors see every trashed post, while authors only see their own.
+func (controller postController) GetTrash(c *gin.Context) {
+	postService := controller.postService
+	permissionService := controller.permissionService
+	page := c.Query("page")
+	pageId, err := strconv.Atoi(page)
+
+	var author string
+	if permissionService.CheckPostManagement(actor(c)) != nil {
+		author = c.GetString("UserID")
+	}
+
+	var posts []repository.Post
+	var pages int
+
 	// If no page query is provided, call the default service
 	if err != nil {
-		posts, pages, err = postService.GetPosts()
+		posts, pages, err = postService.GetTrash(author)
 	} else {
-		posts, pages, err = postService.GetPostsPage(pageId)
+		posts, pages, err = postService.GetTrashPage(author, pageId)
 	}
 
 	switch err.(type) {
@@ -164,33 +353,115 @@ func (controller postController) GetPosts(c *gin.Context) {
 	}
 }
 
+// RestorePost middleware. Top level handler of /trash/:PostID/restore POST requests.
+func (controller postController) RestorePost(c *gin.Context) {
+	controller.changePostStatus(c, controller.postService.RestorePost)
+}
+
+// changePostStatus applies a status transition to the post identified by the PostID parameter.
+func (controller postController) changePostStatus(c *gin.Context, transition func(id string) (repository.Post, error)) {
+	permissionService := controller.permissionService
+
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
+	post, err := transition(postID)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populatePost(post))
+	case errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPostError{URLHandle: postID})
+	}
+}
+
 // populatePost maps a repository.Post model to types.Post
 func populatePost(post repository.Post) types.Post {
 	p := types.Post{
-		Author:       post.Author.UserName,
-		CreationTime: post.CreatedAt,
-		Id:           post.URLHandle,
-		Summary:      post.Summary,
-		Body:         post.Body,
-		Title:        *post.Title,
+		Author:             post.Author.UserName,
+		CreationTime:       post.CreatedAt,
+		PublicationTime:    post.PublishedAt,
+		PublishAt:          post.PublishAt,
+		UnpublishAt:        post.UnpublishAt,
+		DeletionTime:       populateDeletionTime(post.DeletedAt),
+		Id:                 post.URLHandle,
+		Status:             types.PostStatus(post.Status),
+		Summary:            post.Summary,
+		Excerpt:            populateExcerpt(post),
+		WordCount:          post.WordCount,
+		ReadingTimeMinutes: post.ReadingTime,
+		Tags:               populateTagNames(post.Tags),
+		Category:           populateCategoryID(post.Category),
+		Meta:               populateMeta(post.Meta),
+		Body:               post.Body,
+		BodyFormat:         populateBodyFormat(post.BodyFormat),
+		BodyHtml:           post.BodyHTML,
+		Series:             populateSeriesNavigation(post.Series),
+		OlderPost:          populatePostLink(post.Older),
+		NewerPost:          populatePostLink(post.Newer),
+		TableOfContents:    populateTableOfContents(post.Contents),
+		Title:              *post.Title,
 	}
 
 	return p
 }
 
+// populateTableOfContents maps the headings of a post to table of contents entries
+func populateTableOfContents(headings []repository.Heading) *[]types.TableOfContentsEntry {
+	if len(headings) == 0 {
+		return nil
+	}
+
+	entries := make([]types.TableOfContentsEntry, 0, len(headings))
+
+	for _, heading := range headings {
+		entries = append(entries, types.TableOfContentsEntry{
+			Level:  heading.Level,
+			Text:   heading.Text,
+			Anchor: heading.Anchor,
+		})
+	}
+
+	return &entries
+}
+
 // populatePostMetadata maps a repository.Post model to types.PostMetadata
 func populatePostMetadata(post repository.Post) types.PostMetadata {
 	p := types.PostMetadata{
-		Author:       post.Author.UserName,
-		CreationTime: post.CreatedAt,
-		Id:           post.URLHandle,
-		Summary:      post.Summary,
-		Title:        *post.Title,
+		Author:             post.Author.UserName,
+		CreationTime:       post.CreatedAt,
+		PublicationTime:    post.PublishedAt,
+		PublishAt:          post.PublishAt,
+		UnpublishAt:        post.UnpublishAt,
+		DeletionTime:       populateDeletionTime(post.DeletedAt),
+		Id:                 post.URLHandle,
+		Status:             types.PostStatus(post.Status),
+		Summary:            post.Summary,
+		Excerpt:            populateExcerpt(post),
+		WordCount:          post.WordCount,
+		ReadingTimeMinutes: post.ReadingTime,
+		Tags:               populateTagNames(post.Tags),
+		Category:           populateCategoryID(post.Category),
+		Meta:               populateMeta(post.Meta),
+		Title:              *post.Title,
 	}
 
 	return p
 }
 
+// populateExcerpt returns the summary of the post, or if there is none, the excerpt generated from its body
+func populateExcerpt(post repository.Post) *string {
+	if post.Summary != nil && *post.Summary != "" {
+		return post.Summary
+	}
+
+	return post.Excerpt
+}
+
 // populatePostMetadataSlice maps a slice of repository.Post models to a types.PostMetadata slice
 func populatePostMetadataSlice(posts []repository.Post) []types.PostMetadata {
 	p := make([]types.PostMetadata, 0, len(posts))
@@ -201,3 +472,125 @@ func populatePostMetadataSlice(posts []repository.Post) []types.PostMetadata {
 
 	return p
 }
+
+// populateTagModels maps the tag names of a request to repository.Tag models.
+// If no tags were provided, nil is returned to leave the tags of the post unchanged.
+func populateTagModels(names *[]string) []repository.Tag {
+	if names == nil {
+		return nil
+	}
+
+	tags := make([]repository.Tag, 0, len(*names))
+
+	for _, name := range *names {
+		tags = append(tags, repository.Tag{Name: name})
+	}
+
+	return tags
+}
+
+// populateTagNames maps a slice of repository.Tag models to their names
+func populateTagNames(tags []repository.Tag) *[]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	return &names
+}
+
+// populatePostLink maps a repository.Post model to a types.PostLink or nil if there is no post
+func populatePostLink(post *repository.Post) *types.PostLink {
+	if post == nil {
+		return nil
+	}
+
+	link := types.PostLink{Id: post.URLHandle}
+	if post.Title != nil {
+		link.Title = *post.Title
+	}
+
+	return &link
+}
+
+// populateBodyFormatModel maps the optional body format of a request to the repository model.
+// A missing format is mapped to the empty format, which keeps the current format of the post.
+func populateBodyFormatModel(format *types.BodyFormat) repository.BodyFormat {
+	if format == nil {
+		return ""
+	}
+
+	return repository.BodyFormat(*format)
+}
+
+// populateBodyFormat maps the body format of a post to the API model or nil if the post has no body format
+func populateBodyFormat(format repository.BodyFormat) *types.BodyFormat {
+	if format == "" {
+		return nil
+	}
+
+	f := types.BodyFormat(format)
+	return &f
+}
+
+// populateDeletionTime returns the time the post was moved to the trash or nil if the post isn't trashed
+func populateDeletionTime(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+
+	return &deletedAt.Time
+}
+
+// populateCategoryID returns the URL handle of the category or nil if the post has no category
+func populateCategoryID(category *repository.Category) *string {
+	if category == nil {
+		return nil
+	}
+
+	return &category.URLHandle
+}
+
+// populateMetaModel maps the metadata of a request to the metadata of a repository.Post model.
+// If no metadata was provided, nil is returned to leave the metadata of the post unchanged.
+func populateMetaModel(meta *types.PostMeta) map[string]interface{} {
+	if meta == nil {
+		return nil
+	}
+
+	return *meta
+}
+
+// populateMeta maps the metadata of a repository.Post model to types.PostMeta
+func populateMeta(meta map[string]interface{}) *types.PostMeta {
+	if len(meta) == 0 {
+		return nil
+	}
+
+	m := types.PostMeta(meta)
+	return &m
+}
+
+// populateMetaFilter collects the metadata filter from the "meta.<field>" query parameters of the request.
+func populateMetaFilter(c *gin.Context) map[string]string {
+	var filter map[string]string
+
+	for key, values := range c.Request.URL.Query() {
+		field, found := strings.CutPrefix(key, "meta.")
+		if !found || len(values) == 0 {
+			continue
+		}
+
+		if filter == nil {
+			filter = map[string]string{}
+		}
+		filter[field] = values[0]
+	}
+
+	return filter
+}
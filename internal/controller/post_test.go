@@ -12,19 +12,24 @@ import (
 	"github.com/wlachs/blog/internal/logger"
 	"github.com/wlachs/blog/internal/mocks"
 	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
 	"github.com/wlachs/blog/internal/test"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 // postTestContext contains commonly used services, controllers and other objects relevant for testing the PostController.
 type postTestContext struct {
-	mockPostService *mocks.MockPostService
-	sut             controller.PostController
-	ctx             *gin.Context
-	rec             *httptest.ResponseRecorder
+	mockPostService       *mocks.MockPostService
+	mockPermissionService *mocks.MockPermissionService
+	sut                   controller.PostController
+	ctx                   *gin.Context
+	rec                   *httptest.ResponseRecorder
 }
 
 // createPostControllerContext creates the context for testing the PostController and reduces code duplication.
@@ -33,11 +38,12 @@ func createPostControllerContext(t *testing.T) *postTestContext {
 
 	mockCtrl := gomock.NewController(t)
 	mockPostService := mocks.NewMockPostService(mockCtrl)
-	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil)
-	sut := controller.CreatePostController(cont, mockPostService)
+	mockPermissionService := mocks.NewMockPermissionService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreatePostController(cont, mockPostService, mockPermissionService)
 	ctx, rec := test.CreateControllerContext()
 
-	return &postTestContext{mockPostService, sut, ctx, rec}
+	return &postTestContext{mockPostService, mockPermissionService, sut, ctx, rec}
 }
 
 // TestPostController_AddPost tests adding a new post to the system with valid input params.
@@ -74,8 +80,11 @@ func TestPostController_AddPost(t *testing.T) {
 	var output types.Post
 	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
 
+	expectedOutput := input
+	expectedOutput.Excerpt = &summary
+
 	assert.Nil(t, c.ctx.Errors, "should complete without error")
-	assert.Equal(t, input, output, "response body should match")
+	assert.Equal(t, expectedOutput, output, "response body should match")
 	assert.Equal(t, 201, c.rec.Code, "incorrect response status")
 }
 
@@ -199,8 +208,10 @@ func TestPostController_UpdatePost(t *testing.T) {
 
 	test.MockJsonPost(c.ctx, input)
 
+	c.ctx.Set("UserID", "testEditor")
 	c.ctx.AddParam("PostID", urlHandle)
-	c.mockPostService.EXPECT().UpdatePost(postModel).Return(postModel, nil)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().UpdatePost(postModel, "testEditor").Return(postModel, nil)
 
 	c.sut.UpdatePost(c.ctx)
 
@@ -217,6 +228,8 @@ func TestPostController_UpdatePost_Invalid_Input(t *testing.T) {
 	t.Parallel()
 	c := createPostControllerContext(t)
 
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+
 	c.sut.UpdatePost(c.ctx)
 
 	errors := c.ctx.Errors.Errors()
@@ -248,7 +261,8 @@ func TestPostController_UpdatePost_Incorrect_Url_Handle(t *testing.T) {
 
 	c.ctx.AddParam("PostID", postModel.URLHandle)
 	expectedError := errortypes.PostNotFoundError{URLHandle: postModel.URLHandle}
-	c.mockPostService.EXPECT().UpdatePost(postModel).Return(repository.Post{}, expectedError)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().UpdatePost(postModel, "").Return(repository.Post{}, expectedError)
 
 	c.sut.UpdatePost(c.ctx)
 
@@ -292,7 +306,8 @@ func TestPostController_UpdatePost_Unexpected_Error(t *testing.T) {
 
 	c.ctx.AddParam("PostID", postModel.URLHandle)
 	expectedError := errortypes.UnexpectedPostError{URLHandle: postModel.URLHandle}
-	c.mockPostService.EXPECT().UpdatePost(inputModel).Return(repository.Post{}, fmt.Errorf("unexpected internal error"))
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().UpdatePost(inputModel, "").Return(repository.Post{}, fmt.Errorf("unexpected internal error"))
 
 	c.sut.UpdatePost(c.ctx)
 
@@ -310,6 +325,7 @@ func TestPostController_DeletePost(t *testing.T) {
 	urlHandle := "testHandle"
 
 	c.ctx.AddParam("PostID", urlHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockPostService.EXPECT().DeletePost(urlHandle).Return(nil)
 
 	c.sut.DeletePost(c.ctx)
@@ -327,6 +343,7 @@ func TestPostController_DeletePost_Incorrect_Url_Handle(t *testing.T) {
 
 	c.ctx.AddParam("PostID", urlHandle)
 	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockPostService.EXPECT().DeletePost(urlHandle).Return(expectedError)
 
 	c.sut.DeletePost(c.ctx)
@@ -346,6 +363,7 @@ func TestPostController_DeletePost_Unexpected_Error(t *testing.T) {
 
 	c.ctx.AddParam("PostID", urlHandle)
 	expectedError := errortypes.UnexpectedPostError{URLHandle: urlHandle}
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockPostService.EXPECT().DeletePost(urlHandle).Return(fmt.Errorf("unexpected internal error"))
 
 	c.sut.DeletePost(c.ctx)
@@ -373,13 +391,16 @@ func TestPostController_GetPost(t *testing.T) {
 		Title:     &title,
 		Summary:   &summary,
 		Body:      &body,
+		Older:     &repository.Post{URLHandle: "older", Title: &title},
 	}
 	expectedOutput := types.Post{
-		Author:  postModel.Author.UserName,
-		Id:      postModel.URLHandle,
-		Title:   *postModel.Title,
-		Summary: postModel.Summary,
-		Body:    postModel.Body,
+		Author:    postModel.Author.UserName,
+		Id:        postModel.URLHandle,
+		Title:     *postModel.Title,
+		Summary:   postModel.Summary,
+		Excerpt:   postModel.Summary,
+		Body:      postModel.Body,
+		OlderPost: &types.PostLink{Id: "older", Title: title},
 	}
 
 	c.ctx.AddParam("PostID", postModel.URLHandle)
@@ -478,12 +499,13 @@ func TestPostController_GetPosts(t *testing.T) {
 				Title:   title,
 				Author:  userModel.UserName,
 				Summary: &summary,
+				Excerpt: &summary,
 			},
 		},
 		Pages: &pages,
 	}
 
-	c.mockPostService.EXPECT().GetPosts().Return(postModels, pages, nil)
+	c.mockPostService.EXPECT().GetPosts(repository.PostFilter{}).Return(postModels, pages, nil)
 
 	c.sut.GetPosts(c.ctx)
 
@@ -527,12 +549,13 @@ func TestPostController_GetPostsPage(t *testing.T) {
 				Title:   title,
 				Author:  userModel.UserName,
 				Summary: &summary,
+				Excerpt: &summary,
 			},
 		},
 		Pages: &pages,
 	}
 
-	c.mockPostService.EXPECT().GetPostsPage(2).Return(postModels, pages, nil)
+	c.mockPostService.EXPECT().GetPostsPage(repository.PostFilter{}, 2).Return(postModels, pages, nil)
 
 	c.sut.GetPosts(c.ctx)
 
@@ -576,12 +599,13 @@ func TestPostController_GetPosts_Invalid_Page(t *testing.T) {
 				Title:   title,
 				Author:  userModel.UserName,
 				Summary: &summary,
+				Excerpt: &summary,
 			},
 		},
 		Pages: &pages,
 	}
 
-	c.mockPostService.EXPECT().GetPosts().Return(postModels, pages, nil)
+	c.mockPostService.EXPECT().GetPosts(repository.PostFilter{}).Return(postModels, pages, nil)
 
 	c.sut.GetPosts(c.ctx)
 
@@ -602,7 +626,7 @@ func TestPostController_GetPosts_Negative_Page(t *testing.T) {
 
 	expectedError := errortypes.InvalidPostPageError{Page: -1}
 
-	c.mockPostService.EXPECT().GetPostsPage(-1).Return(nil, -1, expectedError)
+	c.mockPostService.EXPECT().GetPostsPage(repository.PostFilter{}, -1).Return(nil, -1, expectedError)
 
 	c.sut.GetPosts(c.ctx)
 
@@ -619,7 +643,7 @@ func TestPostController_GetPosts_Unexpected_Error(t *testing.T) {
 	c := createPostControllerContext(t)
 	expectedError := errortypes.UnexpectedPostError{}
 
-	c.mockPostService.EXPECT().GetPosts().Return(nil, -1, fmt.Errorf("unexpected error"))
+	c.mockPostService.EXPECT().GetPosts(repository.PostFilter{}).Return(nil, -1, fmt.Errorf("unexpected error"))
 
 	c.sut.GetPosts(c.ctx)
 
@@ -628,3 +652,797 @@ func TestPostController_GetPosts_Unexpected_Error(t *testing.T) {
 	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
 	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
 }
+
+// TestPostController_GetDraft tests retrieving a draft of the current user.
+func TestPostController_GetDraft(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	author := "testAuthor"
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Author:    repository.User{UserName: author},
+		Title:     &title,
+		Status:    repository.PostStatusDraft,
+	}
+	expectedOutput := types.Post{
+		Author: author,
+		Id:     postModel.URLHandle,
+		Title:  title,
+		Status: types.PostStatus(repository.PostStatusDraft),
+	}
+
+	c.ctx.Set("UserID", author)
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPostService.EXPECT().GetDraft(postModel.URLHandle, author).Return(postModel, nil)
+
+	c.sut.GetDraft(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedOutput, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetDraft_Not_Found tests retrieving a draft that doesn't belong to the current user.
+func TestPostController_GetDraft_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	urlHandle := "testUrlHandle"
+	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+
+	c.ctx.Set("UserID", "testAuthor")
+	c.ctx.AddParam("PostID", urlHandle)
+	c.mockPostService.EXPECT().GetDraft(urlHandle, "testAuthor").Return(repository.Post{}, expectedError)
+
+	c.sut.GetDraft(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetDrafts tests retrieving a page of drafts of the current user.
+func TestPostController_GetDrafts(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	author := "testAuthor"
+	posts := []repository.Post{
+		{URLHandle: "draft", Author: repository.User{UserName: author}, Title: &title, Status: repository.PostStatusDraft},
+	}
+	expectedPosts := []types.PostMetadata{
+		{Id: "draft", Author: author, Title: title, Status: types.PostStatus(repository.PostStatusDraft)},
+	}
+	expectedPages := 1
+
+	c.ctx.Set("UserID", author)
+	c.ctx.Request.URL, _ = url.Parse("?page=2")
+	c.mockPostService.EXPECT().GetDraftsPage(author, 2).Return(posts, expectedPages, nil)
+
+	c.sut.GetDrafts(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Posts{Posts: &expectedPosts, Pages: &expectedPages}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_PublishPost tests publishing a draft.
+func TestPostController_PublishPost(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Status:    repository.PostStatusPublished,
+	}
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().PublishPost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.PublishPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.PostStatus(repository.PostStatusPublished), output.Status, "incorrect post status")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_UnpublishPost_Not_Found tests unpublishing a non-existing post.
+func TestPostController_UnpublishPost_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	urlHandle := "testUrlHandle"
+	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+
+	c.ctx.AddParam("PostID", urlHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().UnpublishPost(urlHandle).Return(repository.Post{}, expectedError)
+
+	c.sut.UnpublishPost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_ArchivePost_Unexpected_Error tests handling an unexpected error while archiving a post.
+func TestPostController_ArchivePost_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	urlHandle := "testUrlHandle"
+	expectedError := errortypes.UnexpectedPostError{URLHandle: urlHandle}
+
+	c.ctx.AddParam("PostID", urlHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().ArchivePost(urlHandle).Return(repository.Post{}, fmt.Errorf("unexpected error"))
+
+	c.sut.ArchivePost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_AddPost_Invalid_Schedule tests adding a new post with an invalid publication schedule.
+func TestPostController_AddPost_Invalid_Schedule(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	publishAt := time.Now().Add(time.Hour).UTC()
+	unpublishAt := time.Now().UTC()
+	input := types.NewPost{
+		Title:       &title,
+		PublishAt:   &publishAt,
+		UnpublishAt: &unpublishAt,
+	}
+	expectedError := errortypes.InvalidPostScheduleError{URLHandle: "testUrlHandle"}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.Set("UserID", "testAuthor")
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockPostService.EXPECT().AddPost(gomock.Any(), "testAuthor").Return(repository.Post{}, expectedError)
+
+	c.sut.AddPost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_AddPost_Missing_Title tests adding a new post without a title.
+func TestPostController_AddPost_Missing_Title(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	summary := "testSummary"
+	input := types.NewPost{
+		Summary: &summary,
+	}
+	expectedError := errortypes.MissingTitleError{}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.Set("UserID", "testAuthor")
+	c.mockPostService.EXPECT().AddPost(gomock.Any(), "testAuthor").Return(repository.Post{}, expectedError)
+
+	c.sut.AddPost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPosts_Tag_Filter tests retrieving the posts with a given tag.
+func TestPostController_GetPosts_Tag_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	pages := 1
+	postModels := []repository.Post{
+		{URLHandle: "tagged", Title: &title, Tags: []repository.Tag{{Name: "go"}}},
+	}
+	expectedPosts := []types.PostMetadata{
+		{Id: "tagged", Title: title, Tags: &[]string{"go"}},
+	}
+
+	c.ctx.Request.URL, _ = url.Parse("?tag=go")
+	c.mockPostService.EXPECT().GetPosts(repository.PostFilter{Tag: "go"}).Return(postModels, pages, nil)
+
+	c.sut.GetPosts(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Posts{Posts: &expectedPosts, Pages: &pages}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPosts_Meta_Filter tests retrieving the posts with the given metadata values.
+func TestPostController_GetPosts_Meta_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	pages := 1
+	meta := map[string]interface{}{"license": "cc-by"}
+	postModels := []repository.Post{
+		{URLHandle: "licensed", Title: &title, Meta: meta},
+	}
+	expectedMeta := types.PostMeta(meta)
+	expectedPosts := []types.PostMetadata{
+		{Id: "licensed", Title: title, Meta: &expectedMeta},
+	}
+
+	c.ctx.Request.URL, _ = url.Parse("?meta.license=cc-by&page=1")
+	c.mockPostService.EXPECT().
+		GetPostsPage(repository.PostFilter{Meta: map[string]string{"license": "cc-by"}}, 1).
+		Return(postModels, pages, nil)
+
+	c.sut.GetPosts(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Posts{Posts: &expectedPosts, Pages: &pages}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_UpdatePost_Invalid_Meta tests updating a post with metadata that doesn't match the schema.
+func TestPostController_UpdatePost_Invalid_Meta(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	meta := types.PostMeta{"license": 42.0}
+	input := types.UpdatedPost{
+		Meta: &meta,
+	}
+	expectedPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Meta:      map[string]interface{}{"license": 42.0},
+	}
+	expectedError := errortypes.InvalidPostMetaError{Key: "license", Reason: "expected a string"}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().UpdatePost(expectedPost, "").Return(repository.Post{}, expectedError)
+
+	c.sut.UpdatePost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPosts_Category_Filter tests retrieving the posts of a category.
+func TestPostController_GetPosts_Category_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	category := "performance"
+	pages := 1
+	postModels := []repository.Post{
+		{URLHandle: "fast-go", Title: &title, Category: &repository.Category{URLHandle: category}},
+	}
+	expectedPosts := []types.PostMetadata{
+		{Id: "fast-go", Title: title, Category: &category},
+	}
+
+	c.ctx.Request.URL, _ = url.Parse("?category=engineering")
+	c.mockPostService.EXPECT().GetPosts(repository.PostFilter{Category: "engineering"}).Return(postModels, pages, nil)
+
+	c.sut.GetPosts(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Posts{Posts: &expectedPosts, Pages: &pages}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPost_Series tests retrieving a single post of a series.
+func TestPostController_GetPost_Series(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "Part 2"
+	previousTitle := "Part 1"
+	postModel := repository.Post{
+		URLHandle: "part-2",
+		Title:     &title,
+		Series: &repository.SeriesNavigation{
+			Series:   repository.Series{URLHandle: "go-tutorial", Title: "Go Tutorial"},
+			Position: 2,
+			Total:    2,
+			Previous: &repository.Post{URLHandle: "part-1", Title: &previousTitle},
+		},
+	}
+	expectedOutput := types.Post{
+		Id:    postModel.URLHandle,
+		Title: title,
+		Series: &types.SeriesNavigation{
+			Id:       "go-tutorial",
+			Title:    "Go Tutorial",
+			Position: 2,
+			Total:    2,
+			Previous: &types.PostLink{Id: "part-1", Title: previousTitle},
+		},
+	}
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPostService.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.GetPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedOutput, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetTrash tests retrieving a page of trashed posts.
+func TestPostController_GetTrash(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	author := "testAuthor"
+	deletedAt := time.Date(2023, 12, 24, 8, 0, 0, 0, time.UTC)
+	posts := []repository.Post{
+		{URLHandle: "trashed", Author: repository.User{UserName: author}, Title: &title, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+	}
+	expectedPosts := []types.PostMetadata{
+		{Id: "trashed", Author: author, Title: title, DeletionTime: &deletedAt},
+	}
+	expectedPages := 1
+
+	c.ctx.Request.URL, _ = url.Parse("?page=2")
+	c.ctx.Set("UserID", "testEditor")
+	c.ctx.Set("Role", string(repository.RoleEditor))
+	c.mockPermissionService.EXPECT().CheckPostManagement(gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().GetTrashPage("", 2).Return(posts, expectedPages, nil)
+
+	c.sut.GetTrash(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Posts{Posts: &expectedPosts, Pages: &expectedPages}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetTrash_Author tests that authors only retrieve their own trashed posts.
+func TestPostController_GetTrash_Author(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedPages := 0
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.mockPermissionService.EXPECT().CheckPostManagement(author).Return(errortypes.PermissionDeniedError{UserName: author.UserName})
+	c.mockPostService.EXPECT().GetTrash(author.UserName).Return([]repository.Post{}, expectedPages, nil)
+
+	c.sut.GetTrash(c.ctx)
+
+	var output types.Posts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Posts{Posts: &[]types.PostMetadata{}, Pages: &expectedPages}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetTrash_Invalid_Page tests retrieving trashed posts with an invalid page number.
+func TestPostController_GetTrash_Invalid_Page(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	expectedError := errortypes.InvalidPostPageError{Page: 0}
+
+	c.ctx.Request.URL, _ = url.Parse("?page=0")
+	c.mockPermissionService.EXPECT().CheckPostManagement(gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().GetTrashPage("", 0).Return(nil, -1, expectedError)
+
+	c.sut.GetTrash(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_RestorePost tests restoring a post from the trash.
+func TestPostController_RestorePost(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Status:    repository.PostStatusPublished,
+	}
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().RestorePost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.RestorePost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, postModel.URLHandle, output.Id, "incorrect post ID")
+	assert.Nil(t, output.DeletionTime, "restored post shouldn't have a deletion time")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_RestorePost_Not_Found tests restoring a post that isn't in the trash.
+func TestPostController_RestorePost_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	urlHandle := "testUrlHandle"
+	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+
+	c.ctx.AddParam("PostID", urlHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().RestorePost(urlHandle).Return(repository.Post{}, expectedError)
+
+	c.sut.RestorePost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPost_Moved tests retrieving a single post by the ID it had before it was renamed.
+func TestPostController_GetPost_Moved(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	expectedError := errortypes.PostMovedError{URLHandle: "oldUrlHandle", NewURLHandle: "newUrlHandle"}
+
+	c.ctx.AddParam("PostID", expectedError.URLHandle)
+	c.mockPostService.EXPECT().GetPost(expectedError.URLHandle).Return(repository.Post{}, expectedError)
+
+	c.sut.GetPost(c.ctx)
+
+	var output types.PostRedirect
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.PostRedirect{Id: expectedError.NewURLHandle}, output, "incorrect output body")
+	assert.Equal(t, "/api/v0/posts/newUrlHandle", c.rec.Header().Get("Location"), "incorrect redirect location")
+	assert.Equal(t, 301, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_RenamePost tests changing the ID of a post.
+func TestPostController_RenamePost(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	postModel := repository.Post{URLHandle: "newUrlHandle", Title: &title}
+
+	test.MockJsonPost(c.ctx, types.PostRename{Id: postModel.URLHandle})
+
+	c.ctx.AddParam("PostID", "oldUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().RenamePost("oldUrlHandle", postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.RenamePost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, postModel.URLHandle, output.Id, "incorrect post ID")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_RenamePost_Duplicate_Post tests renaming a post to the ID of another post.
+func TestPostController_RenamePost_Duplicate_Post(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	expectedError := errortypes.DuplicateElementError{Key: "newUrlHandle"}
+
+	test.MockJsonPost(c.ctx, types.PostRename{Id: expectedError.Key})
+
+	c.ctx.AddParam("PostID", "oldUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().RenamePost("oldUrlHandle", expectedError.Key).Return(repository.Post{}, expectedError)
+
+	c.sut.RenamePost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 409, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_AddPost_Generated_ID tests adding a new post without an ID in the path.
+func TestPostController_AddPost_Generated_ID(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	author := "testAuthor"
+	title := "Test Title"
+	input := types.NewPost{Title: &title}
+	newPost := repository.Post{Title: &title}
+	postModel := repository.Post{URLHandle: "test-title", Title: &title}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.Set("UserID", author)
+	c.mockPostService.EXPECT().AddPost(newPost, author).Return(postModel, nil)
+
+	c.sut.AddPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, postModel.URLHandle, output.Id, "response should contain the generated ID")
+	assert.Equal(t, "/api/v0/posts/test-title", c.rec.Header().Get("Location"), "incorrect location of the new post")
+	assert.Equal(t, 201, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPost_Body_HTML tests that the rendered body is returned along with the raw body.
+func TestPostController_GetPost_Body_HTML(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	body := "**testBody**"
+	bodyHTML := "<p><strong>testBody</strong></p>\n"
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Body:      &body,
+		BodyHTML:  &bodyHTML,
+	}
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPostService.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.GetPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, &body, output.Body, "incorrect raw body")
+	assert.Equal(t, &bodyHTML, output.BodyHtml, "incorrect rendered body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_UpdatePost_Invalid_Body_Format tests updating a post with an unsupported body format.
+func TestPostController_UpdatePost_Invalid_Body_Format(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	urlHandle := "testHandle"
+	bodyFormat := types.BodyFormat("rst")
+	input := types.UpdatedPost{BodyFormat: &bodyFormat}
+	updatedPost := repository.Post{URLHandle: urlHandle, BodyFormat: "rst"}
+	expectedError := errortypes.InvalidBodyFormatError{Format: "rst"}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.Set("UserID", "testEditor")
+	c.ctx.AddParam("PostID", urlHandle)
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockPostService.EXPECT().UpdatePost(updatedPost, "testEditor").Return(repository.Post{}, expectedError)
+
+	c.sut.UpdatePost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_AddPost_Invalid_Tag tests adding a post with a tag name exceeding the maximum length.
+func TestPostController_AddPost_Invalid_Tag(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	name := strings.Repeat("a", 65)
+	tags := []string{name}
+	input := types.NewPost{Tags: &tags}
+	expectedError := errortypes.InvalidTagError{Name: name, MaxLength: 64}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.Set("UserID", "testAuthor")
+	c.ctx.AddParam("PostID", "testHandle")
+	c.mockPostService.EXPECT().AddPost(gomock.Any(), "testAuthor").Return(repository.Post{}, expectedError)
+
+	c.sut.AddPost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPost_Body_Format tests that the body format is returned with the post.
+func TestPostController_GetPost_Body_Format(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	postModel := repository.Post{
+		URLHandle:  "testUrlHandle",
+		Title:      &title,
+		BodyFormat: repository.BodyFormatText,
+	}
+	expectedFormat := types.Text
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPostService.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.GetPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, &expectedFormat, output.BodyFormat, "incorrect body format")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPost_Excerpt tests that the generated excerpt and the body statistics are returned
+// if the post has no summary.
+func TestPostController_GetPost_Excerpt(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	excerpt := "The first sentence."
+	words := 420
+	minutes := 3
+	postModel := repository.Post{
+		URLHandle:   "testUrlHandle",
+		Title:       &title,
+		Excerpt:     &excerpt,
+		WordCount:   &words,
+		ReadingTime: &minutes,
+	}
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPostService.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.GetPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, &excerpt, output.Excerpt, "incorrect excerpt")
+	assert.Equal(t, &words, output.WordCount, "incorrect word count")
+	assert.Equal(t, &minutes, output.ReadingTimeMinutes, "incorrect reading time")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_GetPost_Table_Of_Contents tests that the table of contents is returned with the post.
+func TestPostController_GetPost_Table_Of_Contents(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	title := "testTitle"
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Contents: []repository.Heading{
+			{Level: 2, Text: "Getting Started", Anchor: "getting-started"},
+			{Level: 3, Text: "Setup", Anchor: "setup"},
+		},
+	}
+	expectedContents := []types.TableOfContentsEntry{
+		{Level: 2, Text: "Getting Started", Anchor: "getting-started"},
+		{Level: 3, Text: "Setup", Anchor: "setup"},
+	}
+
+	c.ctx.AddParam("PostID", postModel.URLHandle)
+	c.mockPostService.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	c.sut.GetPost(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, &expectedContents, output.TableOfContents, "incorrect table of contents")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_UpdatePost_Forbidden tests updating a post of another author.
+func TestPostController_UpdatePost_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	actor := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: actor.UserName}
+
+	c.ctx.Set("UserID", actor.UserName)
+	c.ctx.Set("Role", string(actor.Role))
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(actor, "testUrlHandle").Return(expectedError)
+
+	c.sut.UpdatePost(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_DeletePost_Forbidden tests deleting a post of another author.
+func TestPostController_DeletePost_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	expectedError := errortypes.PermissionDeniedError{UserName: "testAuthor"}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(expectedError)
+
+	c.sut.DeletePost(c.ctx)
+
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestPostController_PublishPost_Permission_Not_Found tests publishing a non-existing post as an author.
+func TestPostController_PublishPost_Permission_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostControllerContext(t)
+
+	expectedError := errortypes.PostNotFoundError{URLHandle: "testUrlHandle"}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(expectedError)
+
+	c.sut.PublishPost(c.ctx)
+
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
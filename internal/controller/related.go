@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strconv"
+)
+
+// RelatedPostController interface defining related post-related middleware methods to handle HTTP requests
+type RelatedPostController interface {
+	GetRelatedPosts(c *gin.Context)
+}
+
+// relatedPostController is a concrete implementation of the RelatedPostController interface
+type relatedPostController struct {
+	cont               container.Container
+	relatedPostService services.RelatedPostService
+}
+
+// CreateRelatedPostController instantiates a related post controller using the application container.
+func CreateRelatedPostController(cont container.Container, relatedPostService services.RelatedPostService) RelatedPostController {
+	return &relatedPostController{cont, relatedPostService}
+}
+
+// GetRelatedPosts middleware. Top level handler of /posts/:PostID/related GET requests.
+func (controller relatedPostController) GetRelatedPosts(c *gin.Context) {
+	relatedPostService := controller.relatedPostService
+
+	id, found := c.Params.Get("PostID")
+	if !found {
+		_ = c.AbortWithError(http.StatusBadRequest, errortypes.MissingUrlHandleError{})
+		return
+	}
+
+	// If no limit query is provided, use the default limit
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = services.DefaultRelatedPostLimit
+	}
+
+	posts, err := relatedPostService.GetRelatedPosts(id, limit)
+
+	switch err.(type) {
+	case nil:
+		p := populatePostMetadataSlice(posts)
+		c.IndentedJSON(http.StatusOK, types.RelatedPosts{Posts: &p})
+	case errortypes.InvalidRelatedPostLimitError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedPostError{URLHandle: id})
+	}
+}
@@ -0,0 +1,137 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// relatedPostTestContext contains commonly used services, controllers and other objects relevant for testing the RelatedPostController.
+type relatedPostTestContext struct {
+	mockRelatedPostService *mocks.MockRelatedPostService
+	sut                    controller.RelatedPostController
+	ctx                    *gin.Context
+	rec                    *httptest.ResponseRecorder
+}
+
+// createRelatedPostControllerContext creates the context for testing the RelatedPostController and reduces code duplication.
+func createRelatedPostControllerContext(t *testing.T) *relatedPostTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockRelatedPostService := mocks.NewMockRelatedPostService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateRelatedPostController(cont, mockRelatedPostService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &relatedPostTestContext{mockRelatedPostService, sut, ctx, rec}
+}
+
+// TestRelatedPostController_GetRelatedPosts tests getting the related posts of a post with the default limit.
+func TestRelatedPostController_GetRelatedPosts(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostControllerContext(t)
+
+	title := "testTitle"
+	posts := []repository.Post{
+		{URLHandle: "related", Title: &title, Author: repository.User{UserName: "testAuthor"}},
+	}
+	expectedOutput := types.RelatedPosts{
+		Posts: &[]types.PostMetadata{
+			{Id: "related", Title: title, Author: "testAuthor"},
+		},
+	}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockRelatedPostService.EXPECT().GetRelatedPosts("testUrlHandle", 5).Return(posts, nil)
+
+	c.sut.GetRelatedPosts(c.ctx)
+
+	var output types.RelatedPosts
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedOutput, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestRelatedPostController_GetRelatedPosts_Limit tests getting a limited number of related posts.
+func TestRelatedPostController_GetRelatedPosts_Limit(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostControllerContext(t)
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.Request.URL, _ = url.Parse("?limit=2")
+	c.mockRelatedPostService.EXPECT().GetRelatedPosts("testUrlHandle", 2).Return([]repository.Post{}, nil)
+
+	c.sut.GetRelatedPosts(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestRelatedPostController_GetRelatedPosts_Invalid_Limit tests getting related posts with a limit out of range.
+func TestRelatedPostController_GetRelatedPosts_Invalid_Limit(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostControllerContext(t)
+
+	expectedError := errortypes.InvalidRelatedPostLimitError{Limit: 50}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.Request.URL, _ = url.Parse("?limit=50")
+	c.mockRelatedPostService.EXPECT().GetRelatedPosts("testUrlHandle", 50).Return(nil, expectedError)
+
+	c.sut.GetRelatedPosts(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestRelatedPostController_GetRelatedPosts_Missing_Post tests getting the related posts of a missing post.
+func TestRelatedPostController_GetRelatedPosts_Missing_Post(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostControllerContext(t)
+
+	expectedError := errortypes.PostNotFoundError{URLHandle: "testUrlHandle"}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockRelatedPostService.EXPECT().GetRelatedPosts("testUrlHandle", 5).Return(nil, expectedError)
+
+	c.sut.GetRelatedPosts(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestRelatedPostController_GetRelatedPosts_Unexpected_Error tests handling unexpected errors of related posts.
+func TestRelatedPostController_GetRelatedPosts_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostControllerContext(t)
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockRelatedPostService.EXPECT().GetRelatedPosts("testUrlHandle", 5).Return(nil, fmt.Errorf("error"))
+
+	c.sut.GetRelatedPosts(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, errortypes.UnexpectedPostError{URLHandle: "testUrlHandle"}.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/diff"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strconv"
+)
+
+// RevisionController interface defining revision-related middleware methods to handle HTTP requests
+type RevisionController interface {
+	GetRevisions(c *gin.Context)
+	GetRevision(c *gin.Context)
+	GetRevisionDiff(c *gin.Context)
+	RestoreRevision(c *gin.Context)
+}
+
+// revisionController is a concrete implementation of the RevisionController interface
+type revisionController struct {
+	cont              container.Container
+	revisionService   services.RevisionService
+	permissionService services.PermissionService
+}
+
+// CreateRevisionController instantiates a revision controller using the application container.
+func CreateRevisionController(cont container.Container, revisionService services.RevisionService,
+	permissionService services.PermissionService) RevisionController {
+	return &revisionController{cont, revisionService, permissionService}
+}
+
+// GetRevisions middleware. Top level handler of /posts/:PostID/revisions GET requests.
+// Revisions contain unpublished content, so only users allowed to modify the post may read them.
+func (controller revisionController) GetRevisions(c *gin.Context) {
+	revisionService := controller.revisionService
+	permissionService := controller.permissionService
+
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
+	revisions, err := revisionService.GetRevisions(postID)
+
+	switch err.(type) {
+	case nil:
+		r := populateRevisionMetadataSlice(revisions)
+		c.IndentedJSON(http.StatusOK, types.Revisions{Revisions: &r})
+	case errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedRevisionError{URLHandle: postID})
+	}
+}
+
+// GetRevision middleware. Top level handler of /posts/:PostID/revisions/:RevisionID GET requests.
+// Only users allowed to modify the post may read its revisions.
+func (controller revisionController) GetRevision(c *gin.Context) {
+	revisionService := controller.revisionService
+	permissionService := controller.permissionService
+
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
+	number, err := revisionNumber(c)
+
+	var revision repository.Revision
+	if err == nil {
+		revision, err = revisionService.GetRevision(postID, number)
+	}
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateRevision(revision))
+	case errortypes.InvalidRevisionError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.PostNotFoundError, errortypes.RevisionNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedRevisionError{URLHandle: postID})
+	}
+}
+
+// GetRevisionDiff middleware. Top level handler of /posts/:PostID/revisions/:RevisionID/diff GET requests.
+// The revision is compared with the revision given in the "from" query or with the one preceding it.
+// Only users allowed to modify the post may compare its revisions.
+func (controller revisionController) GetRevisionDiff(c *gin.Context) {
+	revisionService := controller.revisionService
+	permissionService := controller.permissionService
+
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
+	number, err := revisionNumber(c)
+
+	var from *int
+	if query, found := c.GetQuery("from"); found && err == nil {
+		if f, convErr := strconv.Atoi(query); convErr == nil {
+			from = &f
+		} else {
+			err = errortypes.InvalidRevisionError{Revision: query}
+		}
+	}
+
+	var revisionDiff services.RevisionDiff
+	if err == nil {
+		revisionDiff, err = revisionService.DiffRevisions(postID, from, number)
+	}
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateRevisionDiff(revisionDiff))
+	case errortypes.InvalidRevisionError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.PostNotFoundError, errortypes.RevisionNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errortypes.RevisionDiffTooLargeError:
+		_ = c.AbortWithError(http.StatusUnprocessableEntity, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedRevisionError{URLHandle: postID})
+	}
+}
+
+// RestoreRevision middleware. Top level handler of /posts/:PostID/revisions/:RevisionID/restore POST requests.
+func (controller revisionController) RestoreRevision(c *gin.Context) {
+	revisionService := controller.revisionService
+	permissionService := controller.permissionService
+
+	editor := c.GetString("UserID")
+	postID, _ := c.Params.Get("PostID")
+	if !authorized(c, permissionService.CheckPostModification(actor(c), postID)) {
+		return
+	}
+
+	number, err := revisionNumber(c)
+
+	var post repository.Post
+	if err == nil {
+		post, err = revisionService.RestoreRevision(postID, number, editor)
+	}
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populatePost(post))
+	case errortypes.InvalidRevisionError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.PostNotFoundError, errortypes.RevisionNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedRevisionError{URLHandle: postID})
+	}
+}
+
+// revisionNumber parses the RevisionID parameter of the request.
+func revisionNumber(c *gin.Context) (int, error) {
+	revisionID, _ := c.Params.Get("RevisionID")
+
+	number, err := strconv.Atoi(revisionID)
+	if err != nil || number < 1 {
+		return 0, errortypes.InvalidRevisionError{Revision: revisionID}
+	}
+
+	return number, nil
+}
+
+// populateRevisionMetadata maps a repository.Revision model to types.RevisionMetadata
+func populateRevisionMetadata(revision repository.Revision) types.RevisionMetadata {
+	r := types.RevisionMetadata{
+		Id:           revision.Number,
+		Editor:       populateEditorName(revision.Editor),
+		CreationTime: revision.CreatedAt,
+	}
+
+	if revision.Title != nil {
+		r.Title = *revision.Title
+	}
+
+	return r
+}
+
+// populateRevisionMetadataSlice maps a slice of repository.Revision models to a types.RevisionMetadata slice
+func populateRevisionMetadataSlice(revisions []repository.Revision) []types.RevisionMetadata {
+	r := make([]types.RevisionMetadata, 0, len(revisions))
+
+	for _, revision := range revisions {
+		r = append(r, populateRevisionMetadata(revision))
+	}
+
+	return r
+}
+
+// populateRevision maps a repository.Revision model to types.Revision
+func populateRevision(revision repository.Revision) types.Revision {
+	r := types.Revision{
+		Id:           revision.Number,
+		Editor:       populateEditorName(revision.Editor),
+		CreationTime: revision.CreatedAt,
+		Summary:      revision.Summary,
+		Body:         revision.Body,
+		BodyFormat:   populateBodyFormat(revision.BodyFormat),
+	}
+
+	if revision.Title != nil {
+		r.Title = *revision.Title
+	}
+
+	return r
+}
+
+// populateEditorName returns the name of the editor or nil if the editor no longer exists
+func populateEditorName(editor *repository.User) *string {
+	if editor == nil {
+		return nil
+	}
+
+	return &editor.UserName
+}
+
+// populateRevisionDiff maps a services.RevisionDiff to types.RevisionDiff
+func populateRevisionDiff(revisionDiff services.RevisionDiff) types.RevisionDiff {
+	lines := make([]types.DiffLine, 0, len(revisionDiff.Lines))
+
+	for _, line := range revisionDiff.Lines {
+		lines = append(lines, populateDiffLine(line))
+	}
+
+	return types.RevisionDiff{
+		From:  revisionDiff.From,
+		To:    revisionDiff.To,
+		Lines: lines,
+	}
+}
+
+// populateDiffLine maps a diff.Line to types.DiffLine
+func populateDiffLine(line diff.Line) types.DiffLine {
+	return types.DiffLine{
+		Operation: types.DiffLineOperation(line.Operation),
+		Text:      line.Text,
+	}
+}
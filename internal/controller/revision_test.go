@@ -0,0 +1,265 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/diff"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// revisionTestContext contains commonly used services, controllers and other objects relevant for testing the RevisionController.
+type revisionTestContext struct {
+	mockRevisionService   *mocks.MockRevisionService
+	mockPermissionService *mocks.MockPermissionService
+	sut                   controller.RevisionController
+	ctx                   *gin.Context
+	rec                   *httptest.ResponseRecorder
+}
+
+// createRevisionControllerContext creates the context for testing the RevisionController and reduces code duplication.
+func createRevisionControllerContext(t *testing.T) *revisionTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockRevisionService := mocks.NewMockRevisionService(mockCtrl)
+	mockPermissionService := mocks.NewMockPermissionService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateRevisionController(cont, mockRevisionService, mockPermissionService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &revisionTestContext{mockRevisionService, mockPermissionService, sut, ctx, rec}
+}
+
+// TestRevisionController_GetRevisions tests listing the revisions of a post.
+func TestRevisionController_GetRevisions(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	title := "testTitle"
+	creationTime := time.Date(2023, 11, 21, 22, 55, 30, 0, time.UTC)
+	revisions := []repository.Revision{
+		{Number: 2, Title: &title, Editor: &repository.User{UserName: "testEditor"}, CreatedAt: creationTime},
+		{Number: 1, Title: &title, CreatedAt: creationTime},
+	}
+	editor := "testEditor"
+	expectedRevisions := []types.RevisionMetadata{
+		{Id: 2, Title: title, Editor: &editor, CreationTime: creationTime},
+		{Id: 1, Title: title, CreationTime: creationTime},
+	}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(nil)
+	c.mockRevisionService.EXPECT().GetRevisions("testUrlHandle").Return(revisions, nil)
+
+	c.sut.GetRevisions(c.ctx)
+
+	var output types.Revisions
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, expectedRevisions, *output.Revisions, "response body should match")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevisions_Forbidden tests that authors cannot list the revisions of another author's draft.
+func TestRevisionController_GetRevisions_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("PostID", "otherDraft")
+	c.mockPermissionService.EXPECT().CheckPostModification(author, "otherDraft").Return(expectedError)
+
+	c.sut.GetRevisions(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevision_Forbidden tests that authors cannot read a revision of another author's draft.
+func TestRevisionController_GetRevision_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("PostID", "otherDraft")
+	c.ctx.AddParam("RevisionID", "1")
+	c.mockPermissionService.EXPECT().CheckPostModification(author, "otherDraft").Return(expectedError)
+
+	c.sut.GetRevision(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevisionDiff_Forbidden tests that authors cannot compare revisions of another author's draft.
+func TestRevisionController_GetRevisionDiff_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("PostID", "otherDraft")
+	c.ctx.AddParam("RevisionID", "2")
+	c.mockPermissionService.EXPECT().CheckPostModification(author, "otherDraft").Return(expectedError)
+
+	c.sut.GetRevisionDiff(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevision_Invalid_Revision tests retrieving a revision with a malformed revision ID.
+func TestRevisionController_GetRevision_Invalid_Revision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.AddParam("RevisionID", "latest")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(nil)
+
+	c.sut.GetRevision(c.ctx)
+
+	assert.Equal(t, errortypes.InvalidRevisionError{Revision: "latest"}, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevision_Not_Found tests retrieving a non-existing revision.
+func TestRevisionController_GetRevision_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	expectedError := errortypes.RevisionNotFoundError{URLHandle: "testUrlHandle", Number: 3}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.AddParam("RevisionID", "3")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(nil)
+	c.mockRevisionService.EXPECT().GetRevision("testUrlHandle", 3).Return(repository.Revision{}, expectedError)
+
+	c.sut.GetRevision(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevisionDiff tests comparing two revisions of a post.
+func TestRevisionController_GetRevisionDiff(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	from := 1
+	revisionDiff := services.RevisionDiff{
+		From: 1,
+		To:   3,
+		Lines: []diff.Line{
+			{Operation: diff.Equal, Text: "first"},
+			{Operation: diff.Insert, Text: "second"},
+		},
+	}
+	expectedDiff := types.RevisionDiff{
+		From: 1,
+		To:   3,
+		Lines: []types.DiffLine{
+			{Operation: types.Equal, Text: "first"},
+			{Operation: types.Insert, Text: "second"},
+		},
+	}
+
+	c.ctx.Request.URL, _ = url.Parse("?from=1")
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.AddParam("RevisionID", "3")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(nil)
+	c.mockRevisionService.EXPECT().DiffRevisions("testUrlHandle", &from, 3).Return(revisionDiff, nil)
+
+	c.sut.GetRevisionDiff(c.ctx)
+
+	var output types.RevisionDiff
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, expectedDiff, output, "response body should match")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_GetRevisionDiff_Too_Large tests comparing revisions differing in too many lines.
+func TestRevisionController_GetRevisionDiff_Too_Large(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	expectedError := errortypes.RevisionDiffTooLargeError{URLHandle: "testUrlHandle", From: 2, To: 3}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.AddParam("RevisionID", "3")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(nil)
+	c.mockRevisionService.EXPECT().DiffRevisions("testUrlHandle", nil, 3).Return(services.RevisionDiff{}, expectedError)
+
+	c.sut.GetRevisionDiff(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 422, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_RestoreRevision tests restoring a revision as the current editor.
+func TestRevisionController_RestoreRevision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	title := "Old Title"
+	restoredPost := repository.Post{URLHandle: "testUrlHandle", Title: &title}
+
+	c.ctx.Set("UserID", "testEditor")
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.AddParam("RevisionID", "1")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), gomock.Any()).Return(nil)
+	c.mockRevisionService.EXPECT().RestoreRevision("testUrlHandle", 1, "testEditor").Return(restoredPost, nil)
+
+	c.sut.RestoreRevision(c.ctx)
+
+	var output types.Post
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, title, output.Title, "response body should match")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestRevisionController_RestoreRevision_Forbidden tests restoring a revision of a post of another author.
+func TestRevisionController_RestoreRevision_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createRevisionControllerContext(t)
+
+	expectedError := errortypes.PermissionDeniedError{UserName: "testEditor"}
+
+	c.ctx.AddParam("PostID", "testUrlHandle")
+	c.ctx.AddParam("RevisionID", "1")
+	c.mockPermissionService.EXPECT().CheckPostModification(gomock.Any(), "testUrlHandle").Return(expectedError)
+
+	c.sut.RestoreRevision(c.ctx)
+
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
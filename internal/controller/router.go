@@ -3,6 +3,7 @@ package controller
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/repository"
 	"github.com/wlachs/blog/internal/services"
 	"os"
 )
@@ -15,26 +16,124 @@ func CreateRoutes(cont container.Container) {
 	// Services
 	postService := services.CreatePostService(cont)
 	userService := services.CreateUserService(cont)
+	tagService := services.CreateTagService(cont)
+	categoryService := services.CreateCategoryService(cont)
+	seriesService := services.CreateSeriesService(cont)
+	revisionService := services.CreateRevisionService(cont)
+	searchService := services.CreateSearchService(cont)
+	relatedPostService := services.CreateRelatedPostService(cont)
+	archiveService := services.CreateArchiveService(cont)
+	feedService := services.CreateFeedService(cont)
+	sitemapService := services.CreateSitemapService(cont)
+	permissionService := services.CreatePermissionService(cont)
+	tokenService := services.CreateTokenService(cont)
+	apiTokenService := services.CreateAPITokenService(cont)
+	twoFactorService := services.CreateTwoFactorService(cont)
 
 	// Controllers
-	authCtrl := CreateAuthController(cont, userService)
-	postCtrl := CreatePostController(cont, postService)
-	userCtrl := CreateUserController(cont, userService)
+	authCtrl := CreateAuthController(cont, userService, tokenService, apiTokenService, twoFactorService)
+	apiTokenCtrl := CreateAPITokenController(cont, apiTokenService)
+	twoFactorCtrl := CreateTwoFactorController(cont, twoFactorService, permissionService)
+	postCtrl := CreatePostController(cont, postService, permissionService)
+	userCtrl := CreateUserController(cont, userService, permissionService)
+	tagCtrl := CreateTagController(cont, tagService)
+	categoryCtrl := CreateCategoryController(cont, categoryService, permissionService)
+	seriesCtrl := CreateSeriesController(cont, seriesService, permissionService)
+	revisionCtrl := CreateRevisionController(cont, revisionService, permissionService)
+	searchCtrl := CreateSearchController(cont, searchService)
+	relatedPostCtrl := CreateRelatedPostController(cont, relatedPostService)
+	archiveCtrl := CreateArchiveController(cont, archiveService)
+	feedCtrl := CreateFeedController(cont, feedService)
+	sitemapCtrl := CreateSitemapController(cont, sitemapService)
+
+	// Scopes of personal API tokens
+	postsRead := authCtrl.RequireScope(repository.ScopePostsRead)
+	postsWrite := authCtrl.RequireScope(repository.ScopePostsWrite)
+	categoriesWrite := authCtrl.RequireScope(repository.ScopeCategoriesWrite)
+	seriesWrite := authCtrl.RequireScope(repository.ScopeSeriesWrite)
+	usersWrite := authCtrl.RequireScope(repository.ScopeUsersWrite)
 
 	// Posts
 	router.GET("/api/v0/posts", postCtrl.GetPosts)
 	router.GET("/api/v0/posts/:PostID", postCtrl.GetPost)
-	router.POST("/api/v0/posts/:PostID", authCtrl.Protect, postCtrl.AddPost)
-	router.PUT("/api/v0/posts/:PostID", authCtrl.Protect, postCtrl.UpdatePost)
-	router.DELETE("/api/v0/posts/:PostID", authCtrl.Protect, postCtrl.DeletePost)
+	router.POST("/api/v0/posts", authCtrl.Protect, postsWrite, postCtrl.AddPost)
+	router.POST("/api/v0/posts/:PostID", authCtrl.Protect, postsWrite, postCtrl.AddPost)
+	router.PUT("/api/v0/posts/:PostID", authCtrl.Protect, postsWrite, postCtrl.UpdatePost)
+	router.DELETE("/api/v0/posts/:PostID", authCtrl.Protect, postsWrite, postCtrl.DeletePost)
+	router.POST("/api/v0/posts/:PostID/publish", authCtrl.Protect, postsWrite, postCtrl.PublishPost)
+	router.POST("/api/v0/posts/:PostID/unpublish", authCtrl.Protect, postsWrite, postCtrl.UnpublishPost)
+	router.POST("/api/v0/posts/:PostID/archive", authCtrl.Protect, postsWrite, postCtrl.ArchivePost)
+	router.POST("/api/v0/posts/:PostID/rename", authCtrl.Protect, postsWrite, postCtrl.RenamePost)
+	router.GET("/api/v0/posts/:PostID/related", relatedPostCtrl.GetRelatedPosts)
+
+	// Revisions
+	router.GET("/api/v0/posts/:PostID/revisions", authCtrl.Protect, postsRead, revisionCtrl.GetRevisions)
+	router.GET("/api/v0/posts/:PostID/revisions/:RevisionID", authCtrl.Protect, postsRead, revisionCtrl.GetRevision)
+	router.GET("/api/v0/posts/:PostID/revisions/:RevisionID/diff", authCtrl.Protect, postsRead, revisionCtrl.GetRevisionDiff)
+	router.POST("/api/v0/posts/:PostID/revisions/:RevisionID/restore", authCtrl.Protect, postsWrite, revisionCtrl.RestoreRevision)
+
+	// Drafts
+	router.GET("/api/v0/drafts", authCtrl.Protect, postsRead, postCtrl.GetDrafts)
+	router.GET("/api/v0/drafts/:PostID", authCtrl.Protect, postsRead, postCtrl.GetDraft)
+
+	// Trash
+	router.GET("/api/v0/trash", authCtrl.Protect, postsRead, postCtrl.GetTrash)
+	router.POST("/api/v0/trash/:PostID/restore", authCtrl.Protect, postsWrite, postCtrl.RestorePost)
+
+	// Archive
+	router.GET("/api/v0/archive", archiveCtrl.GetArchive)
+	router.GET("/api/v0/archive/:Year", archiveCtrl.GetArchivePosts)
+	router.GET("/api/v0/archive/:Year/:Month", archiveCtrl.GetArchivePosts)
+
+	// Feeds
+	router.GET("/feed.xml", feedCtrl.GetRSS)
+	router.GET("/atom.xml", feedCtrl.GetAtom)
+	router.GET("/feed.json", feedCtrl.GetJSON)
+
+	// Sitemap
+	router.GET("/sitemap.xml", sitemapCtrl.GetSitemap)
+	router.GET("/sitemaps/:Page", sitemapCtrl.GetSitemapPage)
+	router.GET("/robots.txt", sitemapCtrl.GetRobots)
+
+	// Search
+	router.GET("/api/v0/search", searchCtrl.Search)
+
+	// Tags
+	router.GET("/api/v0/tags", tagCtrl.GetTags)
+
+	// Categories
+	router.GET("/api/v0/categories", categoryCtrl.GetCategories)
+	router.GET("/api/v0/categories/:CategoryID", categoryCtrl.GetCategory)
+	router.POST("/api/v0/categories/:CategoryID", authCtrl.Protect, categoriesWrite, categoryCtrl.AddCategory)
+	router.PUT("/api/v0/categories/:CategoryID", authCtrl.Protect, categoriesWrite, categoryCtrl.UpdateCategory)
+	router.DELETE("/api/v0/categories/:CategoryID", authCtrl.Protect, categoriesWrite, categoryCtrl.DeleteCategory)
+
+	// Series
+	router.GET("/api/v0/series", seriesCtrl.GetAllSeries)
+	router.GET("/api/v0/series/:SeriesID", seriesCtrl.GetSeries)
+	router.POST("/api/v0/series/:SeriesID", authCtrl.Protect, seriesWrite, seriesCtrl.AddSeries)
+	router.PUT("/api/v0/series/:SeriesID", authCtrl.Protect, seriesWrite, seriesCtrl.UpdateSeries)
+	router.DELETE("/api/v0/series/:SeriesID", authCtrl.Protect, seriesWrite, seriesCtrl.DeleteSeries)
 
 	// Users
 	router.GET("/api/v0/users", userCtrl.GetUsers)
 	router.GET("/api/v0/users/:UserID", userCtrl.GetUser)
-	router.POST("/api/v0/users/:UserID", authCtrl.Protect, userCtrl.AddUser)
-	router.PUT("/api/v0/users/:UserID", authCtrl.Protect, userCtrl.UpdateUser)
-	router.DELETE("/api/v0/users/:UserID", authCtrl.Protect, userCtrl.DeleteUser)
+	router.POST("/api/v0/users/:UserID", authCtrl.Protect, usersWrite, userCtrl.AddUser)
+	router.PUT("/api/v0/users/:UserID", authCtrl.Protect, usersWrite, userCtrl.UpdateUser)
+	router.PUT("/api/v0/users/:UserID/role", authCtrl.Protect, usersWrite, userCtrl.UpdateUserRole)
+	router.DELETE("/api/v0/users/:UserID", authCtrl.Protect, usersWrite, userCtrl.DeleteUser)
+	router.POST("/api/v0/users/:UserID/2fa", authCtrl.Protect, authCtrl.RequireSession, twoFactorCtrl.EnrolTwoFactor)
+	router.POST("/api/v0/users/:UserID/2fa/confirm", authCtrl.Protect, authCtrl.RequireSession, twoFactorCtrl.ConfirmTwoFactor)
+	router.DELETE("/api/v0/users/:UserID/2fa", authCtrl.Protect, authCtrl.RequireSession, twoFactorCtrl.DisableTwoFactor)
 	router.POST("/api/v0/login", authCtrl.Login)
+	router.POST("/api/v0/token/refresh", authCtrl.Refresh)
+	router.POST("/api/v0/logout", authCtrl.Protect, authCtrl.RequireSession, authCtrl.Logout)
+	router.GET("/.well-known/jwks.json", authCtrl.GetJWKS)
+
+	// Personal API tokens
+	router.GET("/api/v0/tokens", authCtrl.Protect, authCtrl.RequireSession, apiTokenCtrl.GetAPITokens)
+	router.POST("/api/v0/tokens", authCtrl.Protect, authCtrl.RequireSession, apiTokenCtrl.AddAPIToken)
+	router.DELETE("/api/v0/tokens/:TokenID", authCtrl.Protect, authCtrl.RequireSession, apiTokenCtrl.DeleteAPIToken)
 
 	port := os.Getenv("PORT")
 	err := router.Run(":" + port)
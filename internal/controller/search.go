@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strconv"
+)
+
+// SearchController interface defining search-related middleware methods to handle HTTP requests
+type SearchController interface {
+	Search(c *gin.Context)
+}
+
+// searchController is a concrete implementation of the SearchController interface
+type searchController struct {
+	cont          container.Container
+	searchService services.SearchService
+}
+
+// CreateSearchController instantiates a search controller using the application container.
+func CreateSearchController(cont container.Container, searchService services.SearchService) SearchController {
+	return &searchController{cont, searchService}
+}
+
+// Search middleware. Top level handler of /search GET requests.
+func (controller searchController) Search(c *gin.Context) {
+	searchService := controller.searchService
+	query := c.Query("q")
+	page := c.Query("page")
+	pageId, err := strconv.Atoi(page)
+
+	var results []services.SearchResult
+	var pages int
+
+	// If no page query is provided, call the default service
+	if err != nil {
+		results, pages, err = searchService.Search(query)
+	} else {
+		results, pages, err = searchService.SearchPage(query, pageId)
+	}
+
+	switch err.(type) {
+	case nil:
+		r := populateSearchResults(results)
+		c.IndentedJSON(http.StatusOK, types.SearchResults{Posts: &r, Pages: &pages})
+	case errortypes.MissingSearchQueryError, errortypes.InvalidPostPageError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSearchError{})
+	}
+}
+
+// populateSearchResults maps a slice of services.SearchResult models to a types.SearchResult slice
+func populateSearchResults(results []services.SearchResult) []types.SearchResult {
+	r := make([]types.SearchResult, 0, len(results))
+
+	for _, result := range results {
+		metadata := populatePostMetadata(result.Post)
+		r = append(r, types.SearchResult{
+			Id:                 metadata.Id,
+			Title:              metadata.Title,
+			Author:             metadata.Author,
+			Summary:            metadata.Summary,
+			Excerpt:            metadata.Excerpt,
+			WordCount:          metadata.WordCount,
+			ReadingTimeMinutes: metadata.ReadingTimeMinutes,
+			Tags:               metadata.Tags,
+			Category:           metadata.Category,
+			Meta:               metadata.Meta,
+			Status:             metadata.Status,
+			CreationTime:       metadata.CreationTime,
+			PublicationTime:    metadata.PublicationTime,
+			PublishAt:          metadata.PublishAt,
+			UnpublishAt:        metadata.UnpublishAt,
+			DeletionTime:       metadata.DeletionTime,
+			Snippet:            result.Snippet,
+		})
+	}
+
+	return r
+}
@@ -0,0 +1,123 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// searchTestContext contains commonly used services, controllers and other objects relevant for testing the SearchController.
+type searchTestContext struct {
+	mockSearchService *mocks.MockSearchService
+	sut               controller.SearchController
+	ctx               *gin.Context
+	rec               *httptest.ResponseRecorder
+}
+
+// createSearchControllerContext creates the context for testing the SearchController and reduces code duplication.
+func createSearchControllerContext(t *testing.T) *searchTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockSearchService := mocks.NewMockSearchService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateSearchController(cont, mockSearchService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &searchTestContext{mockSearchService, sut, ctx, rec}
+}
+
+// TestSearchController_Search tests searching posts.
+func TestSearchController_Search(t *testing.T) {
+	t.Parallel()
+	c := createSearchControllerContext(t)
+
+	title := "testTitle"
+	results := []services.SearchResult{
+		{
+			Post:    repository.Post{URLHandle: "testUrlHandle", Title: &title, Author: repository.User{UserName: "testAuthor"}},
+			Snippet: "<mark>go</mark> test",
+		},
+	}
+	pages := 1
+	expectedOutput := types.SearchResults{
+		Posts: &[]types.SearchResult{
+			{Id: "testUrlHandle", Title: title, Author: "testAuthor", Snippet: "<mark>go</mark> test"},
+		},
+		Pages: &pages,
+	}
+
+	c.ctx.Request.URL, _ = url.Parse("?q=go")
+	c.mockSearchService.EXPECT().Search("go").Return(results, pages, nil)
+
+	c.sut.Search(c.ctx)
+
+	var output types.SearchResults
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedOutput, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestSearchController_Search_Page tests searching a specific page of posts.
+func TestSearchController_Search_Page(t *testing.T) {
+	t.Parallel()
+	c := createSearchControllerContext(t)
+
+	c.ctx.Request.URL, _ = url.Parse("?q=go&page=2")
+	c.mockSearchService.EXPECT().SearchPage("go", 2).Return([]services.SearchResult{}, 1, nil)
+
+	c.sut.Search(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestSearchController_Search_Missing_Query tests searching without a query.
+func TestSearchController_Search_Missing_Query(t *testing.T) {
+	t.Parallel()
+	c := createSearchControllerContext(t)
+
+	expectedError := errortypes.MissingSearchQueryError{}
+
+	c.ctx.Request.URL, _ = url.Parse("?q=")
+	c.mockSearchService.EXPECT().Search("").Return(nil, -1, expectedError)
+
+	c.sut.Search(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestSearchController_Search_Unexpected_Error tests handling unexpected errors of the search.
+func TestSearchController_Search_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createSearchControllerContext(t)
+
+	c.ctx.Request.URL, _ = url.Parse("?q=go")
+	c.mockSearchService.EXPECT().Search("go").Return(nil, -1, fmt.Errorf("error"))
+
+	c.sut.Search(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, errortypes.UnexpectedSearchError{}.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
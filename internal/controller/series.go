@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+)
+
+// SeriesController interface defining series-related middleware methods to handle HTTP requests
+type SeriesController interface {
+	AddSeries(c *gin.Context)
+	UpdateSeries(c *gin.Context)
+	DeleteSeries(c *gin.Context)
+	GetSeries(c *gin.Context)
+	GetAllSeries(c *gin.Context)
+}
+
+// seriesController is a concrete implementation of the SeriesController interface
+type seriesController struct {
+	cont              container.Container
+	seriesService     services.SeriesService
+	permissionService services.PermissionService
+}
+
+// CreateSeriesController instantiates a series controller using the application container.
+func CreateSeriesController(cont container.Container, seriesService services.SeriesService,
+	permissionService services.PermissionService) SeriesController {
+	return &seriesController{cont, seriesService, permissionService}
+}
+
+// AddSeries middleware. Top level handler of /series/:SeriesID POST requests.
+// Only admins and editors may add series.
+func (controller seriesController) AddSeries(c *gin.Context) {
+	seriesService := controller.seriesService
+	permissionService := controller.permissionService
+
+	if !authorized(c, permissionService.CheckTaxonomyManagement(actor(c))) {
+		return
+	}
+
+	var body types.NewSeries
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	seriesID, _ := c.Params.Get("SeriesID")
+
+	newSeries := repository.Series{
+		URLHandle:   seriesID,
+		Description: body.Description,
+		Entries:     populateSeriesEntryModels(body.Posts),
+	}
+
+	if body.Title != nil {
+		newSeries.Title = *body.Title
+	}
+
+	series, err := seriesService.AddSeries(newSeries)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusCreated, populateSeries(series))
+	case errortypes.MissingSeriesTitleError, errortypes.DuplicateSeriesPostError, errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.DuplicateElementError, errortypes.PostInOtherSeriesError:
+		_ = c.AbortWithError(http.StatusConflict, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSeriesError{URLHandle: seriesID})
+	}
+}
+
+// UpdateSeries middleware. Top level handler of /series/:SeriesID PUT requests.
+// Updates the metadata of the series or replaces and reorders its posts. Only admins and editors may update series.
+func (controller seriesController) UpdateSeries(c *gin.Context) {
+	seriesService := controller.seriesService
+	permissionService := controller.permissionService
+
+	if !authorized(c, permissionService.CheckTaxonomyManagement(actor(c))) {
+		return
+	}
+
+	var body types.UpdatedSeries
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	seriesID, _ := c.Params.Get("SeriesID")
+
+	updatedSeries := repository.Series{
+		URLHandle:   seriesID,
+		Description: body.Description,
+		Entries:     populateSeriesEntryModels(body.Posts),
+	}
+
+	if body.Title != nil {
+		updatedSeries.Title = *body.Title
+	}
+
+	series, err := seriesService.UpdateSeries(updatedSeries)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateSeries(series))
+	case errortypes.DuplicateSeriesPostError, errortypes.PostNotFoundError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.SeriesNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	case errortypes.PostInOtherSeriesError:
+		_ = c.AbortWithError(http.StatusConflict, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSeriesError{URLHandle: seriesID})
+	}
+}
+
+// DeleteSeries middleware. Top level handler of /series/:SeriesID DELETE requests.
+// Only admins and editors may delete series.
+func (controller seriesController) DeleteSeries(c *gin.Context) {
+	seriesService := controller.seriesService
+	permissionService := controller.permissionService
+
+	if !authorized(c, permissionService.CheckTaxonomyManagement(actor(c))) {
+		return
+	}
+
+	seriesID, _ := c.Params.Get("SeriesID")
+	err := seriesService.DeleteSeries(seriesID)
+
+	switch err.(type) {
+	case nil:
+		c.Status(http.StatusOK)
+	case errortypes.SeriesNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSeriesError{URLHandle: seriesID})
+	}
+}
+
+// GetSeries middleware. Top level handler of /series/:SeriesID GET requests.
+func (controller seriesController) GetSeries(c *gin.Context) {
+	seriesService := controller.seriesService
+
+	seriesID, _ := c.Params.Get("SeriesID")
+	series, err := seriesService.GetSeries(seriesID)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateSeries(series))
+	case errortypes.SeriesNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSeriesError{URLHandle: seriesID})
+	}
+}
+
+// GetAllSeries middleware. Top level handler of /series GET requests.
+func (controller seriesController) GetAllSeries(c *gin.Context) {
+	seriesService := controller.seriesService
+
+	series, err := seriesService.GetAllSeries()
+
+	switch err.(type) {
+	case nil:
+		s := populateSeriesMetadataSlice(series)
+		c.IndentedJSON(http.StatusOK, types.SeriesList{Series: &s})
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSeriesError{})
+	}
+}
+
+// populateSeriesEntryModels maps the post IDs of a request to repository.SeriesEntry models.
+// If no posts were provided, nil is returned to leave the posts of the series unchanged.
+func populateSeriesEntryModels(postIDs *[]string) []repository.SeriesEntry {
+	if postIDs == nil {
+		return nil
+	}
+
+	entries := make([]repository.SeriesEntry, 0, len(*postIDs))
+
+	for _, postID := range *postIDs {
+		entries = append(entries, repository.SeriesEntry{Post: repository.Post{URLHandle: postID}})
+	}
+
+	return entries
+}
+
+// populateSeries maps a repository.Series model to types.Series
+func populateSeries(series repository.Series) types.Series {
+	posts := make([]types.PostMetadata, 0, len(series.Entries))
+
+	for _, entry := range series.Entries {
+		posts = append(posts, populatePostMetadata(entry.Post))
+	}
+
+	return types.Series{
+		Id:          series.URLHandle,
+		Title:       series.Title,
+		Description: series.Description,
+		Posts:       &posts,
+	}
+}
+
+// populateSeriesMetadata maps a repository.Series model to types.SeriesMetadata
+func populateSeriesMetadata(series repository.Series) types.SeriesMetadata {
+	return types.SeriesMetadata{
+		Id:          series.URLHandle,
+		Title:       series.Title,
+		Description: series.Description,
+	}
+}
+
+// populateSeriesMetadataSlice maps a slice of repository.Series models to a types.SeriesMetadata slice
+func populateSeriesMetadataSlice(series []repository.Series) []types.SeriesMetadata {
+	s := make([]types.SeriesMetadata, 0, len(series))
+
+	for _, item := range series {
+		s = append(s, populateSeriesMetadata(item))
+	}
+
+	return s
+}
+
+// populateSeriesNavigation maps a repository.SeriesNavigation to types.SeriesNavigation
+func populateSeriesNavigation(navigation *repository.SeriesNavigation) *types.SeriesNavigation {
+	if navigation == nil {
+		return nil
+	}
+
+	return &types.SeriesNavigation{
+		Id:       navigation.Series.URLHandle,
+		Title:    navigation.Series.Title,
+		Position: navigation.Position,
+		Total:    navigation.Total,
+		Previous: populatePostLink(navigation.Previous),
+		Next:     populatePostLink(navigation.Next),
+	}
+}
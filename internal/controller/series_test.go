@@ -0,0 +1,278 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"testing"
+)
+
+// seriesTestContext contains commonly used services, controllers and other objects relevant for testing the SeriesController.
+type seriesTestContext struct {
+	mockSeriesService     *mocks.MockSeriesService
+	mockPermissionService *mocks.MockPermissionService
+	sut                   controller.SeriesController
+	ctx                   *gin.Context
+	rec                   *httptest.ResponseRecorder
+}
+
+// createSeriesControllerContext creates the context for testing the SeriesController and reduces code duplication.
+func createSeriesControllerContext(t *testing.T) *seriesTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockSeriesService := mocks.NewMockSeriesService(mockCtrl)
+	mockPermissionService := mocks.NewMockPermissionService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateSeriesController(cont, mockSeriesService, mockPermissionService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &seriesTestContext{mockSeriesService, mockPermissionService, sut, ctx, rec}
+}
+
+// TestSeriesController_AddSeries tests adding a new series with its posts.
+func TestSeriesController_AddSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	title := "Go Tutorial"
+	postTitle := "Part 1"
+	posts := []string{"part-1"}
+	input := types.NewSeries{
+		Title: &title,
+		Posts: &posts,
+	}
+	newSeries := repository.Series{
+		URLHandle: "go-tutorial",
+		Title:     title,
+		Entries:   []repository.SeriesEntry{{Post: repository.Post{URLHandle: "part-1"}}},
+	}
+	seriesModel := repository.Series{
+		URLHandle: "go-tutorial",
+		Title:     title,
+		Entries:   []repository.SeriesEntry{{PostID: 4, Position: 1, Post: repository.Post{URLHandle: "part-1", Title: &postTitle}}},
+	}
+	expectedPosts := []types.PostMetadata{{Id: "part-1", Title: postTitle}}
+	expectedSeries := types.Series{
+		Id:    "go-tutorial",
+		Title: title,
+		Posts: &expectedPosts,
+	}
+
+	test.MockJsonPost(c.ctx, input)
+
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockSeriesService.EXPECT().AddSeries(newSeries).Return(seriesModel, nil)
+
+	c.sut.AddSeries(c.ctx)
+
+	var output types.Series
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, expectedSeries, output, "incorrect output body")
+	assert.Equal(t, 201, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_AddSeries_Post_In_Other_Series tests adding a series with a post of another series.
+func TestSeriesController_AddSeries_Post_In_Other_Series(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	title := "Go Tutorial"
+	expectedError := errortypes.PostInOtherSeriesError{URLHandle: "part-1", Series: "rust-tutorial"}
+
+	test.MockJsonPost(c.ctx, types.NewSeries{Title: &title})
+
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockSeriesService.EXPECT().AddSeries(gomock.Any()).Return(repository.Series{}, expectedError)
+
+	c.sut.AddSeries(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 409, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_UpdateSeries_Missing_Post tests reordering a series with an unknown post.
+func TestSeriesController_UpdateSeries_Missing_Post(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	posts := []string{"part-2", "part-9"}
+	expectedError := errortypes.PostNotFoundError{URLHandle: "part-9"}
+
+	test.MockJsonPost(c.ctx, types.UpdatedSeries{Posts: &posts})
+
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockSeriesService.EXPECT().UpdateSeries(gomock.Any()).Return(repository.Series{}, expectedError)
+
+	c.sut.UpdateSeries(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_UpdateSeries_Missing_Series tests updating a non-existing series.
+func TestSeriesController_UpdateSeries_Missing_Series(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	expectedError := errortypes.SeriesNotFoundError{URLHandle: "go-tutorial"}
+
+	test.MockJsonPost(c.ctx, types.UpdatedSeries{})
+
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockSeriesService.EXPECT().UpdateSeries(repository.Series{URLHandle: "go-tutorial"}).Return(repository.Series{}, expectedError)
+
+	c.sut.UpdateSeries(c.ctx)
+
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_DeleteSeries tests deleting a series.
+func TestSeriesController_DeleteSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(gomock.Any()).Return(nil)
+	c.mockSeriesService.EXPECT().DeleteSeries("go-tutorial").Return(nil)
+
+	c.sut.DeleteSeries(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_AddSeries_Forbidden tests that authors cannot add series.
+func TestSeriesController_AddSeries_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(author).Return(expectedError)
+
+	c.sut.AddSeries(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_UpdateSeries_Forbidden tests that authors cannot update series.
+func TestSeriesController_UpdateSeries_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(author).Return(expectedError)
+
+	c.sut.UpdateSeries(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_DeleteSeries_Forbidden tests that authors cannot delete series.
+func TestSeriesController_DeleteSeries_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	author := services.Actor{UserName: "testAuthor", Role: repository.RoleAuthor}
+	expectedError := errortypes.PermissionDeniedError{UserName: author.UserName}
+
+	c.ctx.Set("UserID", author.UserName)
+	c.ctx.Set("Role", string(author.Role))
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockPermissionService.EXPECT().CheckTaxonomyManagement(author).Return(expectedError)
+
+	c.sut.DeleteSeries(c.ctx)
+
+	assert.Equal(t, expectedError, c.ctx.Errors.Last().Err, "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_GetSeries_Missing_Series tests retrieving a non-existing series.
+func TestSeriesController_GetSeries_Missing_Series(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	expectedError := errortypes.SeriesNotFoundError{URLHandle: "go-tutorial"}
+
+	c.ctx.AddParam("SeriesID", "go-tutorial")
+	c.mockSeriesService.EXPECT().GetSeries("go-tutorial").Return(repository.Series{}, expectedError)
+
+	c.sut.GetSeries(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_GetAllSeries tests retrieving every series.
+func TestSeriesController_GetAllSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	description := "Learn Go step by step"
+	seriesModels := []repository.Series{{URLHandle: "go-tutorial", Title: "Go Tutorial", Description: &description}}
+	expectedSeries := []types.SeriesMetadata{{Id: "go-tutorial", Title: "Go Tutorial", Description: &description}}
+
+	c.mockSeriesService.EXPECT().GetAllSeries().Return(seriesModels, nil)
+
+	c.sut.GetAllSeries(c.ctx)
+
+	var output types.SeriesList
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.SeriesList{Series: &expectedSeries}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestSeriesController_GetAllSeries_Unexpected_Error tests handling an unexpected error while retrieving the series.
+func TestSeriesController_GetAllSeries_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createSeriesControllerContext(t)
+
+	expectedError := errortypes.UnexpectedSeriesError{}
+
+	c.mockSeriesService.EXPECT().GetAllSeries().Return(nil, fmt.Errorf("unexpected error"))
+
+	c.sut.GetAllSeries(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
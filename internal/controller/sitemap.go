@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"encoding/xml"
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SitemapController interface defining sitemap-related middleware methods to handle HTTP requests
+type SitemapController interface {
+	GetSitemap(c *gin.Context)
+	GetSitemapPage(c *gin.Context)
+	GetRobots(c *gin.Context)
+}
+
+// sitemapController is a concrete implementation of the SitemapController interface
+type sitemapController struct {
+	cont           container.Container
+	sitemapService services.SitemapService
+}
+
+// sitemapNamespace is the XML namespace of the sitemap protocol
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapURLSet is the XML representation of a sitemap listing pages
+type sitemapURLSet struct {
+	XMLName xml.Name            `xml:"urlset"`
+	XMLNS   string              `xml:"xmlns,attr"`
+	URLs    []sitemapURLElement `xml:"url"`
+}
+
+// sitemapIndex is the XML representation of a sitemap listing other sitemaps
+type sitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	XMLNS    string              `xml:"xmlns,attr"`
+	Sitemaps []sitemapURLElement `xml:"sitemap"`
+}
+
+// sitemapURLElement is the XML representation of an entry of a sitemap
+type sitemapURLElement struct {
+	Location     string `xml:"loc"`
+	LastModified string `xml:"lastmod,omitempty"`
+}
+
+// CreateSitemapController instantiates a sitemap controller using the application container.
+func CreateSitemapController(cont container.Container, sitemapService services.SitemapService) SitemapController {
+	return &sitemapController{cont, sitemapService}
+}
+
+// GetSitemap middleware. Top level handler of /sitemap.xml GET requests.
+func (controller sitemapController) GetSitemap(c *gin.Context) {
+	sitemapService := controller.sitemapService
+	sitemap, err := sitemapService.GetSitemap()
+	serveSitemap(c, sitemap, err)
+}
+
+// GetSitemapPage middleware. Top level handler of /sitemaps/:Page GET requests, where the page looks like "2.xml".
+func (controller sitemapController) GetSitemapPage(c *gin.Context) {
+	sitemapService := controller.sitemapService
+
+	page := c.Param("Page")
+	pageId, err := strconv.Atoi(strings.TrimSuffix(page, ".xml"))
+	if err != nil || !strings.HasSuffix(page, ".xml") {
+		_ = c.AbortWithError(http.StatusNotFound, errortypes.SitemapNotFoundError{Page: page})
+		return
+	}
+
+	sitemap, err := sitemapService.GetSitemapPage(pageId)
+	serveSitemap(c, sitemap, err)
+}
+
+// GetRobots middleware. Top level handler of /robots.txt GET requests.
+func (controller sitemapController) GetRobots(c *gin.Context) {
+	sitemapService := controller.sitemapService
+	c.String(http.StatusOK, sitemapService.GetRobots())
+}
+
+// serveSitemap writes the sitemap as XML. Clients that already have the current version of the sitemap only receive
+// a 304 Not Modified response.
+func serveSitemap(c *gin.Context, sitemap services.Sitemap, err error) {
+	switch err.(type) {
+	case nil:
+	case errortypes.SitemapNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+		return
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSitemapError{})
+		return
+	}
+
+	var document interface{}
+	if sitemap.Sitemaps != nil {
+		document = sitemapIndex{XMLNS: sitemapNamespace, Sitemaps: populateSitemapURLs(sitemap.Sitemaps)}
+	} else {
+		document = sitemapURLSet{XMLNS: sitemapNamespace, URLs: populateSitemapURLs(sitemap.URLs)}
+	}
+
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedSitemapError{})
+		return
+	}
+	body = append([]byte(xml.Header), body...)
+
+	if notModified(c, body, lastModified(sitemap.URLs)) {
+		c.AbortWithStatus(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// populateSitemapURLs maps a slice of services.SitemapURL models to their XML representation
+func populateSitemapURLs(urls []services.SitemapURL) []sitemapURLElement {
+	elements := make([]sitemapURLElement, 0, len(urls))
+
+	for _, u := range urls {
+		element := sitemapURLElement{Location: u.Location}
+		if !u.LastModified.IsZero() {
+			element.LastModified = u.LastModified.UTC().Format(time.RFC3339)
+		}
+		elements = append(elements, element)
+	}
+
+	return elements
+}
+
+// lastModified returns the latest modification time of the sitemap URLs
+func lastModified(urls []services.SitemapURL) time.Time {
+	var latest time.Time
+
+	for _, u := range urls {
+		if u.LastModified.After(latest) {
+			latest = u.LastModified
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,190 @@
+package controller_test
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// sitemapTestContext contains commonly used services, controllers and other objects relevant for testing the SitemapController.
+type sitemapTestContext struct {
+	mockSitemapService *mocks.MockSitemapService
+	sut                controller.SitemapController
+	ctx                *gin.Context
+	rec                *httptest.ResponseRecorder
+}
+
+// createSitemapControllerContext creates the context for testing the SitemapController and reduces code duplication.
+func createSitemapControllerContext(t *testing.T) *sitemapTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockSitemapService := mocks.NewMockSitemapService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateSitemapController(cont, mockSitemapService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &sitemapTestContext{mockSitemapService, sut, ctx, rec}
+}
+
+// TestSitemapController_GetSitemap tests getting a sitemap listing posts.
+func TestSitemapController_GetSitemap(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	sitemap := services.Sitemap{
+		URLs: []services.SitemapURL{
+			{Location: "http://localhost:8080/posts/first", LastModified: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+	expectedBody := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>http://localhost:8080/posts/first</loc>
+    <lastmod>2024-03-05T12:00:00Z</lastmod>
+  </url>
+</urlset>`
+
+	c.mockSitemapService.EXPECT().GetSitemap().Return(sitemap, nil)
+
+	c.sut.GetSitemap(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, expectedBody, c.rec.Body.String(), "incorrect output body")
+	assert.Equal(t, "Tue, 05 Mar 2024 12:00:00 GMT", c.rec.Header().Get("Last-Modified"), "incorrect last modification")
+}
+
+// TestSitemapController_GetSitemap_Index tests getting a sitemap index.
+func TestSitemapController_GetSitemap_Index(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	sitemap := services.Sitemap{
+		Sitemaps: []services.SitemapURL{{Location: "http://localhost:8080/sitemaps/1.xml"}},
+	}
+	expectedBody := `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>http://localhost:8080/sitemaps/1.xml</loc>
+  </sitemap>
+</sitemapindex>`
+
+	c.mockSitemapService.EXPECT().GetSitemap().Return(sitemap, nil)
+
+	c.sut.GetSitemap(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, expectedBody, c.rec.Body.String(), "incorrect output body")
+}
+
+// TestSitemapController_GetSitemap_Not_Modified tests that an unchanged sitemap isn't sent again.
+func TestSitemapController_GetSitemap_Not_Modified(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	sitemap := services.Sitemap{
+		URLs: []services.SitemapURL{
+			{Location: "http://localhost:8080/posts/first", LastModified: time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	c.ctx.Request.Header.Set("If-Modified-Since", "Tue, 05 Mar 2024 12:00:00 GMT")
+	c.mockSitemapService.EXPECT().GetSitemap().Return(sitemap, nil)
+
+	c.sut.GetSitemap(c.ctx)
+
+	assert.Equal(t, 304, c.rec.Code, "incorrect response status")
+}
+
+// TestSitemapController_GetSitemap_Unexpected_Error tests handling unexpected errors of the sitemap.
+func TestSitemapController_GetSitemap_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	c.mockSitemapService.EXPECT().GetSitemap().Return(services.Sitemap{}, fmt.Errorf("error"))
+
+	c.sut.GetSitemap(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, errortypes.UnexpectedSitemapError{}.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
+
+// TestSitemapController_GetSitemapPage tests getting a page of a split sitemap.
+func TestSitemapController_GetSitemapPage(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	c.ctx.AddParam("Page", "2.xml")
+	c.mockSitemapService.EXPECT().GetSitemapPage(2).Return(services.Sitemap{URLs: []services.SitemapURL{}}, nil)
+
+	c.sut.GetSitemapPage(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestSitemapController_GetSitemapPage_Invalid_Page tests getting a sitemap page with a malformed name.
+func TestSitemapController_GetSitemapPage_Invalid_Page(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	expectedError := errortypes.SitemapNotFoundError{Page: "2.txt"}
+
+	c.ctx.AddParam("Page", "2.txt")
+
+	c.sut.GetSitemapPage(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestSitemapController_GetSitemapPage_Not_Found tests getting a sitemap page that doesn't exist.
+func TestSitemapController_GetSitemapPage_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	expectedError := errortypes.SitemapNotFoundError{Page: "9"}
+
+	c.ctx.AddParam("Page", "9.xml")
+	c.mockSitemapService.EXPECT().GetSitemapPage(9).Return(services.Sitemap{}, expectedError)
+
+	c.sut.GetSitemapPage(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestSitemapController_GetRobots tests getting the robots.txt file.
+func TestSitemapController_GetRobots(t *testing.T) {
+	t.Parallel()
+	c := createSitemapControllerContext(t)
+
+	robots := "User-agent: *\nDisallow:\n"
+
+	c.mockSitemapService.EXPECT().GetRobots().Return(robots)
+
+	c.sut.GetRobots(c.ctx)
+
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+	assert.Equal(t, robots, c.rec.Body.String(), "incorrect output body")
+	assert.Equal(t, "text/plain; charset=utf-8", c.rec.Header().Get("Content-Type"), "incorrect content type")
+}
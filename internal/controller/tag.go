@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+)
+
+// TagController interface defining tag-related middleware methods to handle HTTP requests
+type TagController interface {
+	GetTags(c *gin.Context)
+}
+
+// tagController is a concrete implementation of the TagController interface
+type tagController struct {
+	cont       container.Container
+	tagService services.TagService
+}
+
+// CreateTagController instantiates a tag controller using the application container.
+func CreateTagController(cont container.Container, tagService services.TagService) TagController {
+	return &tagController{cont, tagService}
+}
+
+// GetTags middleware. Top level handler of /tags GET requests.
+func (controller tagController) GetTags(c *gin.Context) {
+	tagService := controller.tagService
+
+	tags, err := tagService.GetTags()
+
+	switch err.(type) {
+	case nil:
+		t := populateTags(tags)
+		c.IndentedJSON(http.StatusOK, types.Tags{Tags: &t})
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTagError{})
+	}
+}
+
+// populateTags maps a slice of repository.TagCount models to a types.Tag slice
+func populateTags(tags []repository.TagCount) []types.Tag {
+	t := make([]types.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		t = append(t, types.Tag{Name: tag.Name, PostCount: tag.PostCount})
+	}
+
+	return t
+}
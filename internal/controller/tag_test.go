@@ -0,0 +1,83 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"testing"
+)
+
+// tagTestContext contains commonly used services, controllers and other objects relevant for testing the TagController.
+type tagTestContext struct {
+	mockTagService *mocks.MockTagService
+	sut            controller.TagController
+	ctx            *gin.Context
+	rec            *httptest.ResponseRecorder
+}
+
+// createTagControllerContext creates the context for testing the TagController and reduces code duplication.
+func createTagControllerContext(t *testing.T) *tagTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockTagService := mocks.NewMockTagService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateTagController(cont, mockTagService)
+	ctx, rec := test.CreateControllerContext()
+
+	return &tagTestContext{mockTagService, sut, ctx, rec}
+}
+
+// TestTagController_GetTags tests retrieving every tag of the blog.
+func TestTagController_GetTags(t *testing.T) {
+	t.Parallel()
+	c := createTagControllerContext(t)
+
+	tagModels := []repository.TagCount{
+		{Name: "go", PostCount: 2},
+		{Name: "backend", PostCount: 1},
+	}
+	expectedTags := []types.Tag{
+		{Name: "go", PostCount: 2},
+		{Name: "backend", PostCount: 1},
+	}
+
+	c.mockTagService.EXPECT().GetTags().Return(tagModels, nil)
+
+	c.sut.GetTags(c.ctx)
+
+	var output types.Tags
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "expected no errors")
+	assert.Equal(t, types.Tags{Tags: &expectedTags}, output, "incorrect output body")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestTagController_GetTags_Unexpected_Error tests handling an unexpected error while retrieving the tags.
+func TestTagController_GetTags_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createTagControllerContext(t)
+
+	expectedError := errortypes.UnexpectedTagError{}
+
+	c.mockTagService.EXPECT().GetTags().Return(nil, fmt.Errorf("unexpected error"))
+
+	c.sut.GetTags(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/services"
+	"net/http"
+)
+
+// TwoFactorController interface defining two-factor authentication-related middleware methods to handle HTTP requests.
+type TwoFactorController interface {
+	EnrolTwoFactor(c *gin.Context)
+	ConfirmTwoFactor(c *gin.Context)
+	DisableTwoFactor(c *gin.Context)
+}
+
+// twoFactorController is a concrete implementation of the TwoFactorController interface.
+type twoFactorController struct {
+	cont              container.Container
+	twoFactorService  services.TwoFactorService
+	permissionService services.PermissionService
+}
+
+// CreateTwoFactorController instantiates the two-factor authentication controller using the application container.
+func CreateTwoFactorController(cont container.Container, twoFactorService services.TwoFactorService,
+	permissionService services.PermissionService) TwoFactorController {
+	return &twoFactorController{cont, twoFactorService, permissionService}
+}
+
+// EnrolTwoFactor middleware. Top level handler of /users/:UserID/2fa POST requests.
+// Starts the enrolment of the logged-in user, or replaces their TOTP secret once confirmed.
+// The user has to re-authenticate first.
+func (controller twoFactorController) EnrolTwoFactor(c *gin.Context) {
+	twoFactorService := controller.twoFactorService
+	permissionService := controller.permissionService
+
+	userID, _ := c.Params.Get("UserID")
+	if !authorized(c, permissionService.CheckTwoFactorEnrolment(actor(c), userID)) {
+		return
+	}
+
+	if !controller.reauthenticate(c) {
+		return
+	}
+
+	enrolment, err := twoFactorService.BeginEnrolment(userID)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, types.TOTPEnrolment{Secret: enrolment.Secret, Uri: enrolment.URI})
+	case errortypes.UserNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTwoFactorError{})
+	}
+}
+
+// ConfirmTwoFactor middleware. Top level handler of /users/:UserID/2fa/confirm POST requests.
+// Enables two-factor authentication for the logged-in user and returns their new recovery codes once.
+func (controller twoFactorController) ConfirmTwoFactor(c *gin.Context) {
+	twoFactorService := controller.twoFactorService
+	permissionService := controller.permissionService
+
+	userID, _ := c.Params.Get("UserID")
+	if !authorized(c, permissionService.CheckTwoFactorEnrolment(actor(c), userID)) {
+		return
+	}
+
+	var body types.ConfirmTwoFactorJSONBody
+	if err := c.BindJSON(&body); err != nil {
+		return
+	}
+
+	codes, err := twoFactorService.ConfirmEnrolment(userID, body.Code)
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, types.RecoveryCodes{RecoveryCodes: codes})
+	case errortypes.TwoFactorNotPendingError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.InvalidTwoFactorCodeError:
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+	case errortypes.UserNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTwoFactorError{})
+	}
+}
+
+// DisableTwoFactor middleware. Top level handler of /users/:UserID/2fa DELETE requests.
+// Users may only disable two-factor authentication for themselves, unless they are admins.
+// The logged-in user has to re-authenticate first.
+func (controller twoFactorController) DisableTwoFactor(c *gin.Context) {
+	twoFactorService := controller.twoFactorService
+	permissionService := controller.permissionService
+
+	userID, _ := c.Params.Get("UserID")
+	if !authorized(c, permissionService.CheckUserUpdate(actor(c), userID)) {
+		return
+	}
+
+	if !controller.reauthenticate(c) {
+		return
+	}
+
+	err := twoFactorService.Disable(userID)
+
+	switch err.(type) {
+	case nil:
+		c.Status(http.StatusOK)
+	case errortypes.UserNotFoundError, errortypes.TwoFactorNotEnabledError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTwoFactorError{})
+	}
+}
+
+// reauthenticate checks the password and the two-factor authentication code of the logged-in user in the request body.
+// If they don't match, the request is aborted with a status matching the error.
+func (controller twoFactorController) reauthenticate(c *gin.Context) bool {
+	twoFactorService := controller.twoFactorService
+
+	var body types.Reauthentication
+	if err := c.BindJSON(&body); err != nil {
+		return false
+	}
+
+	var code string
+	if body.Code != nil {
+		code = *body.Code
+	}
+
+	err := twoFactorService.Reauthenticate(c.GetString("UserID"), body.Password, code)
+
+	switch err.(type) {
+	case nil:
+		return true
+	case errortypes.TwoFactorRequiredError:
+		c.Header("X-Two-Factor-Required", "true")
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+	case errortypes.IncorrectUsernameOrPasswordError, errortypes.InvalidTwoFactorCodeError:
+		_ = c.AbortWithError(http.StatusUnauthorized, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedTwoFactorError{})
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/api/types"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/controller"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"github.com/wlachs/blog/internal/test"
+	"go.uber.org/mock/gomock"
+	"net/http/httptest"
+	"testing"
+)
+
+// twoFactorTestContext contains commonly used services, controllers and other objects relevant for testing the TwoFactorController.
+type twoFactorTestContext struct {
+	mockTwoFactorService  *mocks.MockTwoFactorService
+	mockPermissionService *mocks.MockPermissionService
+	sut                   controller.TwoFactorController
+	ctx                   *gin.Context
+	rec                   *httptest.ResponseRecorder
+}
+
+// createTwoFactorControllerContext creates the context for testing the TwoFactorController and reduces code duplication.
+func createTwoFactorControllerContext(t *testing.T) *twoFactorTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockTwoFactorService := mocks.NewMockTwoFactorService(mockCtrl)
+	mockPermissionService := mocks.NewMockPermissionService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateTwoFactorController(cont, mockTwoFactorService, mockPermissionService)
+	ctx, rec := test.CreateControllerContext()
+	ctx.Set("UserID", "testUser")
+	ctx.Set("Role", string(repository.RoleAuthor))
+	ctx.AddParam("UserID", "testUser")
+
+	return &twoFactorTestContext{mockTwoFactorService, mockPermissionService, sut, ctx, rec}
+}
+
+// twoFactorActor is the logged-in user of the TwoFactorController tests
+var twoFactorActor = services.Actor{UserName: "testUser", Role: repository.RoleAuthor}
+
+// TestTwoFactorController_EnrolTwoFactor tests starting the enrolment of the logged-in user.
+func TestTwoFactorController_EnrolTwoFactor(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"password": "testPassword"})
+
+	enrolment := services.TOTPEnrolment{Secret: "SECRET", URI: "otpauth://totp/Blog:testUser?secret=SECRET"}
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().Reauthenticate("testUser", "testPassword", "").Return(nil)
+	c.mockTwoFactorService.EXPECT().BeginEnrolment("testUser").Return(enrolment, nil)
+
+	c.sut.EnrolTwoFactor(c.ctx)
+
+	var output types.TOTPEnrolment
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, types.TOTPEnrolment{Secret: enrolment.Secret, Uri: enrolment.URI}, output, "response body should match")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_EnrolTwoFactor_Forbidden tests enrolling another user.
+func TestTwoFactorController_EnrolTwoFactor_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	expectedError := errortypes.PermissionDeniedError{UserName: "testUser"}
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(expectedError)
+
+	c.sut.EnrolTwoFactor(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_EnrolTwoFactor_Code_Required tests replacing an enabled TOTP secret without the current code.
+func TestTwoFactorController_EnrolTwoFactor_Code_Required(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"password": "testPassword"})
+
+	expectedError := errortypes.TwoFactorRequiredError{}
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().Reauthenticate("testUser", "testPassword", "").Return(expectedError)
+
+	c.sut.EnrolTwoFactor(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, "true", c.rec.Header().Get("X-Two-Factor-Required"), "client should be asked for a code")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_EnrolTwoFactor_Missing_Password tests enrolling without re-authentication.
+func TestTwoFactorController_EnrolTwoFactor_Missing_Password(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(nil)
+
+	c.sut.EnrolTwoFactor(c.ctx)
+
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_ConfirmTwoFactor tests enabling two-factor authentication.
+func TestTwoFactorController_ConfirmTwoFactor(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"code": "123456"})
+
+	codes := []string{"abcd-2345", "efgh-6723"}
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().ConfirmEnrolment("testUser", "123456").Return(codes, nil)
+
+	c.sut.ConfirmTwoFactor(c.ctx)
+
+	var output types.RecoveryCodes
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, codes, output.RecoveryCodes, "recovery codes should be returned")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_ConfirmTwoFactor_Invalid_Code tests enabling two-factor authentication with an incorrect code.
+func TestTwoFactorController_ConfirmTwoFactor_Invalid_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"code": "000000"})
+
+	expectedError := errortypes.InvalidTwoFactorCodeError{}
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().ConfirmEnrolment("testUser", "000000").Return(nil, expectedError)
+
+	c.sut.ConfirmTwoFactor(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_ConfirmTwoFactor_Not_Pending tests enabling two-factor authentication without enrolment.
+func TestTwoFactorController_ConfirmTwoFactor_Not_Pending(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"code": "123456"})
+
+	expectedError := errortypes.TwoFactorNotPendingError{}
+	c.mockPermissionService.EXPECT().CheckTwoFactorEnrolment(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().ConfirmEnrolment("testUser", "123456").Return(nil, expectedError)
+
+	c.sut.ConfirmTwoFactor(c.ctx)
+
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_DisableTwoFactor tests disabling two-factor authentication.
+func TestTwoFactorController_DisableTwoFactor(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"password": "testPassword", "code": "123456"})
+
+	c.mockPermissionService.EXPECT().CheckUserUpdate(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().Reauthenticate("testUser", "testPassword", "123456").Return(nil)
+	c.mockTwoFactorService.EXPECT().Disable("testUser").Return(nil)
+
+	c.sut.DisableTwoFactor(c.ctx)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_DisableTwoFactor_Not_Enabled tests disabling two-factor authentication of a user without it.
+func TestTwoFactorController_DisableTwoFactor_Not_Enabled(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"password": "testPassword"})
+
+	expectedError := errortypes.TwoFactorNotEnabledError{}
+	c.mockPermissionService.EXPECT().CheckUserUpdate(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().Reauthenticate("testUser", "testPassword", "").Return(nil)
+	c.mockTwoFactorService.EXPECT().Disable("testUser").Return(expectedError)
+
+	c.sut.DisableTwoFactor(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 404, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_DisableTwoFactor_Invalid_Code tests disabling two-factor authentication with an incorrect code.
+func TestTwoFactorController_DisableTwoFactor_Invalid_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	test.MockJsonPost(c.ctx, map[string]interface{}{"password": "testPassword", "code": "000000"})
+
+	expectedError := errortypes.InvalidTwoFactorCodeError{}
+	c.mockPermissionService.EXPECT().CheckUserUpdate(twoFactorActor, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().Reauthenticate("testUser", "testPassword", "000000").Return(expectedError)
+
+	c.sut.DisableTwoFactor(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
+
+// TestTwoFactorController_DisableTwoFactor_Incorrect_Password tests an admin disabling two-factor authentication of
+// another user with an incorrect password of their own.
+func TestTwoFactorController_DisableTwoFactor_Incorrect_Password(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorControllerContext(t)
+
+	admin := services.Actor{UserName: "testAdmin", Role: repository.RoleAdmin}
+	c.ctx.Set("UserID", admin.UserName)
+	c.ctx.Set("Role", string(admin.Role))
+	test.MockJsonPost(c.ctx, map[string]interface{}{"password": "wrong"})
+
+	expectedError := errortypes.IncorrectUsernameOrPasswordError{}
+	c.mockPermissionService.EXPECT().CheckUserUpdate(admin, "testUser").Return(nil)
+	c.mockTwoFactorService.EXPECT().Reauthenticate("testAdmin", "wrong", "").Return(expectedError)
+
+	c.sut.DisableTwoFactor(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 401, c.rec.Code, "incorrect response status")
+}
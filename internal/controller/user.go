@@ -15,6 +15,7 @@ import (
 type UserController interface {
 	AddUser(c *gin.Context)
 	UpdateUser(c *gin.Context)
+	UpdateUserRole(c *gin.Context)
 	DeleteUser(c *gin.Context)
 	GetUser(c *gin.Context)
 	GetUsers(c *gin.Context)
@@ -22,32 +23,44 @@ type UserController interface {
 
 // userController is a concrete implementation of the UserController interface.
 type userController struct {
-	cont        container.Container
-	userService services.UserService
+	cont              container.Container
+	userService       services.UserService
+	permissionService services.PermissionService
 }
 
 // CreateUserController instantiates a user controller user the application container.
-func CreateUserController(cont container.Container, userService services.UserService) UserController {
-	return &userController{cont, userService}
+func CreateUserController(cont container.Container, userService services.UserService,
+	permissionService services.PermissionService) UserController {
+	return &userController{cont, userService, permissionService}
 }
 
 // AddUser middleware. Top level handler of /users/:UserID POST requests.
-// Registers a new user.
+// Registers a new user. Only admins may register users.
 func (u userController) AddUser(c *gin.Context) {
 	userService := u.userService
+	permissionService := u.permissionService
+
+	if !authorized(c, permissionService.CheckUserManagement(actor(c))) {
+		return
+	}
 
 	var p types.AddUserJSONBody
 	if err := c.BindJSON(&p); err != nil {
 		return
 	}
 
+	var role repository.Role
+	if p.Role != nil {
+		role = repository.Role(*p.Role)
+	}
+
 	userID, _ := c.Params.Get("UserID")
-	user, err := userService.RegisterUser(userID, p.Password)
+	user, err := userService.RegisterUser(userID, p.Password, role)
 
 	switch err.(type) {
 	case nil:
 		c.IndentedJSON(http.StatusCreated, populateUser(user))
-	case errortypes.MissingPasswordError:
+	case errortypes.MissingPasswordError, errortypes.InvalidRoleError:
 		_ = c.AbortWithError(http.StatusBadRequest, err)
 	case errortypes.PasswordHashingError:
 		_ = c.AbortWithError(http.StatusBadRequest, err)
@@ -59,15 +72,21 @@ func (u userController) AddUser(c *gin.Context) {
 }
 
 // UpdateUser middleware. Top level handler of /users/:UserID PUT requests.
+// Users may only update themselves, unless they are admins.
 func (u userController) UpdateUser(c *gin.Context) {
 	userService := u.userService
+	permissionService := u.permissionService
+
+	userID, _ := c.Params.Get("UserID")
+	if !authorized(c, permissionService.CheckUserUpdate(actor(c), userID)) {
+		return
+	}
 
 	var p types.UpdateUserJSONBody
 	if err := c.BindJSON(&p); err != nil {
 		return
 	}
 
-	userID, _ := c.Params.Get("UserID")
 	user, err := userService.UpdateUser(userID, p.OldPassword, p.NewPassword)
 
 	switch err.(type) {
@@ -82,9 +101,45 @@ func (u userController) UpdateUser(c *gin.Context) {
 	}
 }
 
+// UpdateUserRole middleware. Top level handler of /users/:UserID/role PUT requests.
+// Only admins may change the role of users.
+func (u userController) UpdateUserRole(c *gin.Context) {
+	userService := u.userService
+	permissionService := u.permissionService
+
+	if !authorized(c, permissionService.CheckUserManagement(actor(c))) {
+		return
+	}
+
+	var p types.UpdateUserRoleJSONBody
+	if err := c.BindJSON(&p); err != nil {
+		return
+	}
+
+	userID, _ := c.Params.Get("UserID")
+	user, err := userService.UpdateUserRole(userID, repository.Role(p.Role))
+
+	switch err.(type) {
+	case nil:
+		c.IndentedJSON(http.StatusOK, populateUser(user))
+	case errortypes.InvalidRoleError:
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+	case errortypes.UserNotFoundError:
+		_ = c.AbortWithError(http.StatusNotFound, err)
+	default:
+		_ = c.AbortWithError(http.StatusInternalServerError, errortypes.UnexpectedUserError{UserName: userID})
+	}
+}
+
 // DeleteUser middleware. Top level handler of /users/:UserID DELETE requests.
+// Only admins may delete users.
 func (u userController) DeleteUser(c *gin.Context) {
 	userService := u.userService
+	permissionService := u.permissionService
+
+	if !authorized(c, permissionService.CheckUserManagement(actor(c))) {
+		return
+	}
 
 	userID, _ := c.Params.Get("UserID")
 	err := userService.DeleteUser(userID)
@@ -154,6 +209,11 @@ func populateUser(user repository.User) types.User {
 		UserID: user.UserName,
 	}
 
+	if user.Role != "" {
+		role := types.Role(user.Role)
+		u.Role = &role
+	}
+
 	if len(user.Posts) > 0 {
 		posts := populatePostMetadataSlice(user.Posts)
 		u.Posts = &posts
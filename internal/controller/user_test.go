@@ -12,6 +12,7 @@ import (
 	"github.com/wlachs/blog/internal/logger"
 	"github.com/wlachs/blog/internal/mocks"
 	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
 	"github.com/wlachs/blog/internal/test"
 	"go.uber.org/mock/gomock"
 	"net/http/httptest"
@@ -21,10 +22,11 @@ import (
 
 // userTestContext contains commonly used services, controllers and other objects relevant for testing the UserController.
 type userTestContext struct {
-	mockUserService *mocks.MockUserService
-	sut             controller.UserController
-	ctx             *gin.Context
-	rec             *httptest.ResponseRecorder
+	mockUserService       *mocks.MockUserService
+	mockPermissionService *mocks.MockPermissionService
+	sut                   controller.UserController
+	ctx                   *gin.Context
+	rec                   *httptest.ResponseRecorder
 }
 
 // createUserControllerContext creates the context for testing the UserController and reduces code duplication.
@@ -33,11 +35,12 @@ func createUserControllerContext(t *testing.T) *userTestContext {
 
 	mockCtrl := gomock.NewController(t)
 	mockUserService := mocks.NewMockUserService(mockCtrl)
-	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil)
-	sut := controller.CreateUserController(cont, mockUserService)
+	mockPermissionService := mocks.NewMockPermissionService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := controller.CreateUserController(cont, mockUserService, mockPermissionService)
 	ctx, rec := test.CreateControllerContext()
 
-	return &userTestContext{mockUserService, sut, ctx, rec}
+	return &userTestContext{mockUserService, mockPermissionService, sut, ctx, rec}
 }
 
 // TestUserController_AddUser tests adding a new user.
@@ -61,7 +64,8 @@ func TestUserController_AddUser(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
-	c.mockUserService.EXPECT().RegisterUser(userName, input.Password).Return(userModel, nil)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().RegisterUser(userName, input.Password, repository.Role("")).Return(userModel, nil)
 
 	c.sut.AddUser(c.ctx)
 
@@ -81,6 +85,8 @@ func TestUserController_AddUser_Invalid_Input(t *testing.T) {
 	userName := "testAuthor"
 	c.ctx.AddParam("UserID", userName)
 
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+
 	c.sut.AddUser(c.ctx)
 
 	errors := c.ctx.Errors.Errors()
@@ -100,7 +106,8 @@ func TestUserController_AddUser_Missing_Password(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
-	c.mockUserService.EXPECT().RegisterUser(userName, input.Password).Return(repository.User{}, expectedError)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().RegisterUser(userName, input.Password, repository.Role("")).Return(repository.User{}, expectedError)
 
 	c.sut.AddUser(c.ctx)
 
@@ -122,7 +129,8 @@ func TestUserController_AddUser_Invalid_Password(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
-	c.mockUserService.EXPECT().RegisterUser(userName, input.Password).Return(repository.User{}, expectedError)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().RegisterUser(userName, input.Password, repository.Role("")).Return(repository.User{}, expectedError)
 
 	c.sut.AddUser(c.ctx)
 
@@ -144,7 +152,8 @@ func TestUserController_AddUser_Duplicate_User(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
-	c.mockUserService.EXPECT().RegisterUser(userName, input.Password).Return(repository.User{}, expectedError)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().RegisterUser(userName, input.Password, repository.Role("")).Return(repository.User{}, expectedError)
 
 	c.sut.AddUser(c.ctx)
 
@@ -166,7 +175,8 @@ func TestUserController_AddUser_Unexpected_Error(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
-	c.mockUserService.EXPECT().RegisterUser(userName, input.Password).Return(repository.User{}, fmt.Errorf("unexpected error"))
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().RegisterUser(userName, input.Password, repository.Role("")).Return(repository.User{}, fmt.Errorf("unexpected error"))
 
 	c.sut.AddUser(c.ctx)
 
@@ -511,6 +521,7 @@ func TestUserController_UpdateUser(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", expectedOutput.UserID)
+	c.mockPermissionService.EXPECT().CheckUserUpdate(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().UpdateUser(expectedOutput.UserID, input.OldPassword, input.NewPassword).Return(userModel, nil)
 	c.sut.UpdateUser(c.ctx)
 
@@ -530,6 +541,8 @@ func TestUserController_UpdateUser_Invalid_Input(t *testing.T) {
 	userName := "testAuthor"
 	c.ctx.AddParam("UserID", userName)
 
+	c.mockPermissionService.EXPECT().CheckUserUpdate(gomock.Any(), gomock.Any()).Return(nil)
+
 	c.sut.UpdateUser(c.ctx)
 
 	errors := c.ctx.Errors.Errors()
@@ -552,6 +565,7 @@ func TestUserController_UpdateUser_Incorrect_Username(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
+	c.mockPermissionService.EXPECT().CheckUserUpdate(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().UpdateUser(userName, input.OldPassword, input.NewPassword).Return(repository.User{}, expectedError)
 	c.sut.UpdateUser(c.ctx)
 
@@ -576,6 +590,7 @@ func TestUserController_UpdateUser_Invalid_Password(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
+	c.mockPermissionService.EXPECT().CheckUserUpdate(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().UpdateUser(userName, input.OldPassword, input.NewPassword).Return(repository.User{}, expectedError)
 	c.sut.UpdateUser(c.ctx)
 
@@ -600,6 +615,7 @@ func TestUserController_UpdateUser_Unexpected_Error(t *testing.T) {
 	test.MockJsonPost(c.ctx, input)
 
 	c.ctx.AddParam("UserID", userName)
+	c.mockPermissionService.EXPECT().CheckUserUpdate(gomock.Any(), gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().UpdateUser(userName, input.OldPassword, input.NewPassword).Return(repository.User{}, fmt.Errorf("unexpected error"))
 	c.sut.UpdateUser(c.ctx)
 
@@ -617,6 +633,7 @@ func TestUserController_DeleteUser(t *testing.T) {
 	userName := "testAuthor"
 
 	c.ctx.AddParam("UserID", userName)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().DeleteUser(userName).Return(nil)
 
 	c.sut.DeleteUser(c.ctx)
@@ -634,6 +651,7 @@ func TestUserController_DeleteUser_Record_Not_Found(t *testing.T) {
 	expectedError := errortypes.UserNotFoundError{UserName: userName}
 
 	c.ctx.AddParam("UserID", userName)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().DeleteUser(userName).Return(expectedError)
 
 	c.sut.DeleteUser(c.ctx)
@@ -653,6 +671,7 @@ func TestUserController_DeleteUser_Unexpected_Error(t *testing.T) {
 	expectedError := errortypes.UnexpectedUserError{UserName: userName}
 
 	c.ctx.AddParam("UserID", userName)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
 	c.mockUserService.EXPECT().DeleteUser(userName).Return(fmt.Errorf("unexpected error"))
 
 	c.sut.DeleteUser(c.ctx)
@@ -662,3 +681,134 @@ func TestUserController_DeleteUser_Unexpected_Error(t *testing.T) {
 	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
 	assert.Equal(t, 500, c.rec.Code, "incorrect response status")
 }
+
+// TestUserController_AddUser_Forbidden tests adding a new user without the admin role.
+func TestUserController_AddUser_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	actor := services.Actor{UserName: "testEditor", Role: repository.RoleEditor}
+	expectedError := errortypes.PermissionDeniedError{UserName: actor.UserName}
+
+	c.ctx.Set("UserID", actor.UserName)
+	c.ctx.Set("Role", string(actor.Role))
+	c.ctx.AddParam("UserID", "testAuthor")
+	c.mockPermissionService.EXPECT().CheckUserManagement(actor).Return(expectedError)
+
+	c.sut.AddUser(c.ctx)
+
+	errors := c.ctx.Errors.Errors()
+	assert.Equal(t, 1, len(errors), "expected exactly 1 error")
+	assert.Equal(t, expectedError.Error(), errors[0], "incorrect error type")
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestUserController_AddUser_Role tests adding a new user with a role.
+func TestUserController_AddUser_Role(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	role := types.Editor
+	userModel := repository.User{UserName: "testEditor", Role: repository.RoleEditor}
+
+	test.MockJsonPost(c.ctx, types.AddUserJSONBody{Password: "test", Role: &role})
+
+	c.ctx.AddParam("UserID", userModel.UserName)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().RegisterUser(userModel.UserName, "test", repository.RoleEditor).Return(userModel, nil)
+
+	c.sut.AddUser(c.ctx)
+
+	var output types.User
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, role, *output.Role, "response body should contain the role")
+	assert.Equal(t, 201, c.rec.Code, "incorrect response status")
+}
+
+// TestUserController_UpdateUser_Forbidden tests updating another user without the admin role.
+func TestUserController_UpdateUser_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	expectedError := errortypes.PermissionDeniedError{UserName: "testEditor"}
+
+	c.ctx.AddParam("UserID", "testAuthor")
+	c.mockPermissionService.EXPECT().CheckUserUpdate(gomock.Any(), "testAuthor").Return(expectedError)
+
+	c.sut.UpdateUser(c.ctx)
+
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestUserController_DeleteUser_Forbidden tests deleting a user without the admin role.
+func TestUserController_DeleteUser_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	expectedError := errortypes.PermissionDeniedError{UserName: "testEditor"}
+
+	c.ctx.AddParam("UserID", "testAuthor")
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(expectedError)
+
+	c.sut.DeleteUser(c.ctx)
+
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
+
+// TestUserController_UpdateUserRole tests changing the role of a user.
+func TestUserController_UpdateUserRole(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	userModel := repository.User{UserName: "testAuthor", Role: repository.RoleEditor}
+
+	test.MockJsonPost(c.ctx, types.UpdateUserRoleJSONBody{Role: types.Editor})
+
+	c.ctx.AddParam("UserID", userModel.UserName)
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().UpdateUserRole(userModel.UserName, repository.RoleEditor).Return(userModel, nil)
+
+	c.sut.UpdateUserRole(c.ctx)
+
+	var output types.User
+	_ = json.Unmarshal(c.rec.Body.Bytes(), &output)
+
+	assert.Nil(t, c.ctx.Errors, "should complete without error")
+	assert.Equal(t, types.Editor, *output.Role, "response body should contain the new role")
+	assert.Equal(t, 200, c.rec.Code, "incorrect response status")
+}
+
+// TestUserController_UpdateUserRole_Invalid_Role tests changing the role of a user to an unknown one.
+func TestUserController_UpdateUserRole_Invalid_Role(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	expectedError := errortypes.InvalidRoleError{Role: "owner"}
+
+	test.MockJsonPost(c.ctx, types.UpdateUserRoleJSONBody{Role: "owner"})
+
+	c.ctx.AddParam("UserID", "testAuthor")
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(nil)
+	c.mockUserService.EXPECT().UpdateUserRole("testAuthor", repository.Role("owner")).Return(repository.User{}, expectedError)
+
+	c.sut.UpdateUserRole(c.ctx)
+
+	assert.Equal(t, 400, c.rec.Code, "incorrect response status")
+}
+
+// TestUserController_UpdateUserRole_Forbidden tests changing the role of a user without the admin role.
+func TestUserController_UpdateUserRole_Forbidden(t *testing.T) {
+	t.Parallel()
+	c := createUserControllerContext(t)
+
+	expectedError := errortypes.PermissionDeniedError{UserName: "testAuthor"}
+
+	c.ctx.AddParam("UserID", "testAuthor")
+	c.mockPermissionService.EXPECT().CheckUserManagement(gomock.Any()).Return(expectedError)
+
+	c.sut.UpdateUserRole(c.ctx)
+
+	assert.Equal(t, 403, c.rec.Code, "incorrect response status")
+}
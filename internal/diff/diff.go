@@ -0,0 +1,123 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+)
+
+// MaxLines is the maximum number of lines of either text that can be compared, apart from the lines they start
+// and end with. Comparing texts takes memory proportional to the product of their line counts.
+const MaxLines = 1000
+
+// ErrTooLarge is returned if the texts differ in too many lines to be compared.
+var ErrTooLarge = errors.New("too many changed lines to compare")
+
+// Operation describes how a line changed between two texts
+type Operation string
+
+const (
+	// Equal marks lines present in both texts
+	Equal Operation = "equal"
+	// Insert marks lines only present in the new text
+	Insert Operation = "insert"
+	// Delete marks lines only present in the old text
+	Delete Operation = "delete"
+)
+
+// Line is a single line of a line-based diff
+type Line struct {
+	Operation Operation
+	Text      string
+}
+
+// Lines computes a line-based diff turning the old text into the new one.
+// The result is based on the longest common subsequence of the lines, deleted lines come before inserted ones.
+// If the changed part of either text is longer than MaxLines, ErrTooLarge is returned.
+func Lines(oldText string, newText string) ([]Line, error) {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// Lines both texts start or end with are equal, only the lines between them have to be compared
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	if len(a)-prefix-suffix > MaxLines || len(b)-prefix-suffix > MaxLines {
+		return nil, ErrTooLarge
+	}
+
+	lines := make([]Line, 0, max(len(a), len(b)))
+
+	for _, line := range a[:prefix] {
+		lines = append(lines, Line{Equal, line})
+	}
+
+	lines = appendChanges(lines, a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Equal, line})
+	}
+
+	return lines, nil
+}
+
+// appendChanges appends the diff turning the old lines into the new ones to the given lines.
+func appendChanges(lines []Line, a []string, b []string) []Line {
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Equal, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Delete, a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Insert, b[j]})
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Delete, a[i]})
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Insert, b[j]})
+	}
+
+	return lines
+}
+
+// splitLines splits the text into lines. An empty text has no lines.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
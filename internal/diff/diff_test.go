@@ -0,0 +1,87 @@
+package diff_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/diff"
+	"strings"
+	"testing"
+)
+
+// TestLines tests computing the diff of two texts with changed, added and removed lines.
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	oldText := "first\nsecond\nthird\nfourth"
+	newText := "first\nchanged\nthird\nfourth\nfifth"
+	expectedLines := []diff.Line{
+		{Operation: diff.Equal, Text: "first"},
+		{Operation: diff.Delete, Text: "second"},
+		{Operation: diff.Insert, Text: "changed"},
+		{Operation: diff.Equal, Text: "third"},
+		{Operation: diff.Equal, Text: "fourth"},
+		{Operation: diff.Insert, Text: "fifth"},
+	}
+
+	lines, err := diff.Lines(oldText, newText)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedLines, lines, "diff doesn't match the expected one")
+}
+
+// TestLines_Empty_Text tests computing the diff against an empty text.
+func TestLines_Empty_Text(t *testing.T) {
+	t.Parallel()
+
+	lines, _ := diff.Lines("", "new")
+	assert.Equal(t, []diff.Line{{Operation: diff.Insert, Text: "new"}}, lines, "every line should be inserted")
+
+	lines, _ = diff.Lines("old", "")
+	assert.Equal(t, []diff.Line{{Operation: diff.Delete, Text: "old"}}, lines, "every line should be deleted")
+
+	lines, _ = diff.Lines("", "")
+	assert.Equal(t, []diff.Line{}, lines, "empty texts shouldn't differ")
+}
+
+// TestLines_Line_Endings tests that Windows line endings don't count as changes.
+func TestLines_Line_Endings(t *testing.T) {
+	t.Parallel()
+
+	expectedLines := []diff.Line{
+		{Operation: diff.Equal, Text: "first"},
+		{Operation: diff.Equal, Text: "second"},
+	}
+
+	lines, err := diff.Lines("first\r\nsecond", "first\nsecond")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedLines, lines, "line endings should be ignored")
+}
+
+// TestLines_Large_Text tests that long texts can be compared as long as only a few lines changed.
+func TestLines_Large_Text(t *testing.T) {
+	t.Parallel()
+
+	unchanged := strings.Repeat("line\n", 10*diff.MaxLines)
+	oldText := unchanged + "old\n" + unchanged
+	newText := unchanged + "new\n" + unchanged
+
+	lines, err := diff.Lines(oldText, newText)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, diff.Line{Operation: diff.Delete, Text: "old"}, lines[10*diff.MaxLines], "changed line should be deleted")
+	assert.Equal(t, diff.Line{Operation: diff.Insert, Text: "new"}, lines[10*diff.MaxLines+1], "changed line should be inserted")
+	assert.Equal(t, 20*diff.MaxLines+3, len(lines), "incorrect number of lines")
+}
+
+// TestLines_Too_Large tests that texts differing in too many lines are rejected.
+func TestLines_Too_Large(t *testing.T) {
+	t.Parallel()
+
+	oldText := strings.Repeat("old\n", diff.MaxLines+1)
+	newText := strings.Repeat("new\n", diff.MaxLines+1)
+
+	lines, err := diff.Lines(oldText, newText)
+
+	assert.Nil(t, lines, "should not return lines")
+	assert.Equal(t, diff.ErrTooLarge, err, "error doesn't match expected one")
+}
@@ -0,0 +1,60 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type APITokenNotFoundError struct {
+	ID uint
+}
+
+func (e APITokenNotFoundError) Error() string {
+	return fmt.Sprintf("API token %d not found", e.ID)
+}
+
+type InvalidAPITokenIDError struct {
+	ID string
+}
+
+func (e InvalidAPITokenIDError) Error() string {
+	return fmt.Sprintf("API token ID \"%s\" not valid", e.ID)
+}
+
+type InvalidAPITokenNameError struct {
+	Name string
+}
+
+func (e InvalidAPITokenNameError) Error() string {
+	return fmt.Sprintf("API token name \"%s\" not valid, it must have between 1 and 64 characters", e.Name)
+}
+
+type InvalidScopeError struct {
+	Scope string
+}
+
+func (e InvalidScopeError) Error() string {
+	if e.Scope == "" {
+		return "at least one scope is required"
+	}
+	return fmt.Sprintf("scope \"%s\" not valid", e.Scope)
+}
+
+type MissingScopeError struct {
+	Scope string
+}
+
+func (e MissingScopeError) Error() string {
+	return fmt.Sprintf("API token lacks the \"%s\" scope", e.Scope)
+}
+
+type SessionRequiredError struct{}
+
+func (e SessionRequiredError) Error() string {
+	return "this operation requires logging in, API tokens cannot be used"
+}
+
+type UnexpectedAPITokenError struct{}
+
+func (e UnexpectedAPITokenError) Error() string {
+	return "unexpected error encountered with API tokens"
+}
@@ -0,0 +1,19 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type InvalidArchivePeriodError struct {
+	Period string
+}
+
+func (e InvalidArchivePeriodError) Error() string {
+	return fmt.Sprintf("archive period %s not valid", e.Period)
+}
+
+type UnexpectedArchiveError struct{}
+
+func (e UnexpectedArchiveError) Error() string {
+	return "unexpected archive error encountered"
+}
@@ -1,5 +1,9 @@
 package errortypes
 
+import (
+	"fmt"
+)
+
 type MissingAuthTokenError struct{}
 
 func (m MissingAuthTokenError) Error() string {
@@ -11,3 +15,29 @@ type InvalidAuthTokenError struct{}
 func (i InvalidAuthTokenError) Error() string {
 	return "auth token expired or invalid"
 }
+
+type PermissionDeniedError struct {
+	UserName string
+}
+
+func (e PermissionDeniedError) Error() string {
+	return fmt.Sprintf("user \"%s\" is not permitted to perform this operation", e.UserName)
+}
+
+type UnexpectedPermissionError struct{}
+
+func (e UnexpectedPermissionError) Error() string {
+	return "unexpected error encountered while checking permissions"
+}
+
+type InvalidRefreshTokenError struct{}
+
+func (i InvalidRefreshTokenError) Error() string {
+	return "refresh token expired, revoked or invalid"
+}
+
+type UnexpectedTokenError struct{}
+
+func (e UnexpectedTokenError) Error() string {
+	return "unexpected error encountered while issuing tokens"
+}
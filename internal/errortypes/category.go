@@ -0,0 +1,47 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type UnexpectedCategoryError struct {
+	URLHandle string
+}
+
+func (e UnexpectedCategoryError) Error() string {
+	if e.URLHandle != "" {
+		return fmt.Sprintf("unexpected error encountered with category \"%s\"", e.URLHandle)
+	}
+	return "unexpected category error encountered"
+}
+
+type CategoryNotFoundError struct {
+	URLHandle string
+}
+
+func (e CategoryNotFoundError) Error() string {
+	return fmt.Sprintf("category with URL handle \"%s\" not found", e.URLHandle)
+}
+
+type InvalidCategoryParentError struct {
+	URLHandle string
+	Parent    string
+}
+
+func (e InvalidCategoryParentError) Error() string {
+	return fmt.Sprintf("category \"%s\" can't be moved under its own descendant \"%s\"", e.URLHandle, e.Parent)
+}
+
+type CategoryNotEmptyError struct {
+	URLHandle string
+}
+
+func (e CategoryNotEmptyError) Error() string {
+	return fmt.Sprintf("category \"%s\" still has subcategories", e.URLHandle)
+}
+
+type MissingCategoryNameError struct{}
+
+func (e MissingCategoryNameError) Error() string {
+	return "no category name provided"
+}
@@ -0,0 +1,19 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type InvalidFeedContentError struct {
+	Content string
+}
+
+func (e InvalidFeedContentError) Error() string {
+	return fmt.Sprintf("feed content \"%s\" not valid, expected \"full\" or \"summary\"", e.Content)
+}
+
+type UnexpectedFeedError struct{}
+
+func (e UnexpectedFeedError) Error() string {
+	return "unexpected feed error encountered"
+}
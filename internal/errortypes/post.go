@@ -36,3 +36,56 @@ type InvalidPostPageError struct {
 func (e InvalidPostPageError) Error() string {
 	return fmt.Sprintf("post page with number %d not valid", e.Page)
 }
+
+type InvalidPostScheduleError struct {
+	URLHandle string
+}
+
+func (e InvalidPostScheduleError) Error() string {
+	return fmt.Sprintf("post \"%s\" must be unpublished after it gets published", e.URLHandle)
+}
+
+type InvalidPostMetaError struct {
+	Key    string
+	Reason string
+}
+
+func (e InvalidPostMetaError) Error() string {
+	return fmt.Sprintf("invalid post metadata field \"%s\": %s", e.Key, e.Reason)
+}
+
+type PostMovedError struct {
+	URLHandle    string
+	NewURLHandle string
+}
+
+func (e PostMovedError) Error() string {
+	return fmt.Sprintf("post with URL handle \"%s\" moved to \"%s\"", e.URLHandle, e.NewURLHandle)
+}
+
+type InvalidBodyFormatError struct {
+	Format string
+}
+
+func (e InvalidBodyFormatError) Error() string {
+	return fmt.Sprintf("body format \"%s\" not valid", e.Format)
+}
+
+type InvalidRelatedPostLimitError struct {
+	Limit int
+}
+
+func (e InvalidRelatedPostLimitError) Error() string {
+	return fmt.Sprintf("related post limit %d not valid", e.Limit)
+}
+
+type MissingTitleError struct {
+	URLHandle string
+}
+
+func (e MissingTitleError) Error() string {
+	if e.URLHandle != "" {
+		return fmt.Sprintf("no title provided for post \"%s\"", e.URLHandle)
+	}
+	return "no post title provided"
+}
@@ -0,0 +1,40 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type UnexpectedRevisionError struct {
+	URLHandle string
+}
+
+func (e UnexpectedRevisionError) Error() string {
+	return fmt.Sprintf("unexpected error encountered with revisions of post \"%s\"", e.URLHandle)
+}
+
+type RevisionNotFoundError struct {
+	URLHandle string
+	Number    int
+}
+
+func (e RevisionNotFoundError) Error() string {
+	return fmt.Sprintf("revision %d of post \"%s\" not found", e.Number, e.URLHandle)
+}
+
+type InvalidRevisionError struct {
+	Revision string
+}
+
+func (e InvalidRevisionError) Error() string {
+	return fmt.Sprintf("revision \"%s\" not valid", e.Revision)
+}
+
+type RevisionDiffTooLargeError struct {
+	URLHandle string
+	From      int
+	To        int
+}
+
+func (e RevisionDiffTooLargeError) Error() string {
+	return fmt.Sprintf("revisions %d and %d of post \"%s\" differ in too many lines to compare", e.From, e.To, e.URLHandle)
+}
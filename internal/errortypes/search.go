@@ -0,0 +1,13 @@
+package errortypes
+
+type MissingSearchQueryError struct{}
+
+func (e MissingSearchQueryError) Error() string {
+	return "no search query provided"
+}
+
+type UnexpectedSearchError struct{}
+
+func (e UnexpectedSearchError) Error() string {
+	return "unexpected search error encountered"
+}
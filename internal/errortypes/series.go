@@ -0,0 +1,50 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type UnexpectedSeriesError struct {
+	URLHandle string
+}
+
+func (e UnexpectedSeriesError) Error() string {
+	if e.URLHandle != "" {
+		return fmt.Sprintf("unexpected error encountered with series \"%s\"", e.URLHandle)
+	}
+	return "unexpected series error encountered"
+}
+
+type SeriesNotFoundError struct {
+	URLHandle string
+}
+
+func (e SeriesNotFoundError) Error() string {
+	if e.URLHandle != "" {
+		return fmt.Sprintf("series with URL handle \"%s\" not found", e.URLHandle)
+	}
+	return "series not found"
+}
+
+type MissingSeriesTitleError struct{}
+
+func (e MissingSeriesTitleError) Error() string {
+	return "no series title provided"
+}
+
+type DuplicateSeriesPostError struct {
+	URLHandle string
+}
+
+func (e DuplicateSeriesPostError) Error() string {
+	return fmt.Sprintf("post \"%s\" is listed more than once in the series", e.URLHandle)
+}
+
+type PostInOtherSeriesError struct {
+	URLHandle string
+	Series    string
+}
+
+func (e PostInOtherSeriesError) Error() string {
+	return fmt.Sprintf("post \"%s\" already belongs to series \"%s\"", e.URLHandle, e.Series)
+}
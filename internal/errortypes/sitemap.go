@@ -0,0 +1,19 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type SitemapNotFoundError struct {
+	Page string
+}
+
+func (e SitemapNotFoundError) Error() string {
+	return fmt.Sprintf("sitemap %s not found", e.Page)
+}
+
+type UnexpectedSitemapError struct{}
+
+func (e UnexpectedSitemapError) Error() string {
+	return "unexpected sitemap error encountered"
+}
@@ -0,0 +1,20 @@
+package errortypes
+
+import (
+	"fmt"
+)
+
+type UnexpectedTagError struct{}
+
+func (e UnexpectedTagError) Error() string {
+	return "unexpected tag error encountered"
+}
+
+type InvalidTagError struct {
+	Name      string
+	MaxLength int
+}
+
+func (e InvalidTagError) Error() string {
+	return fmt.Sprintf("tag \"%s\" is longer than %d characters", e.Name, e.MaxLength)
+}
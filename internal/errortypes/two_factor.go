@@ -0,0 +1,31 @@
+package errortypes
+
+type TwoFactorRequiredError struct{}
+
+func (e TwoFactorRequiredError) Error() string {
+	return "two-factor authentication code is required"
+}
+
+type InvalidTwoFactorCodeError struct{}
+
+func (e InvalidTwoFactorCodeError) Error() string {
+	return "two-factor authentication code expired, already used or invalid"
+}
+
+type TwoFactorNotPendingError struct{}
+
+func (e TwoFactorNotPendingError) Error() string {
+	return "two-factor authentication enrolment not started"
+}
+
+type TwoFactorNotEnabledError struct{}
+
+func (e TwoFactorNotEnabledError) Error() string {
+	return "two-factor authentication not enabled"
+}
+
+type UnexpectedTwoFactorError struct{}
+
+func (e UnexpectedTwoFactorError) Error() string {
+	return "unexpected error encountered with two-factor authentication"
+}
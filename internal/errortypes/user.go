@@ -62,3 +62,11 @@ type InvalidUserPageError struct {
 func (e InvalidUserPageError) Error() string {
 	return fmt.Sprintf("user page with number %d not valid", e.Page)
 }
+
+type InvalidRoleError struct {
+	Role string
+}
+
+func (e InvalidRoleError) Error() string {
+	return fmt.Sprintf("role \"%s\" not valid", e.Role)
+}
@@ -0,0 +1,297 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/golang-jwt/jwt"
+	"go.uber.org/zap"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+)
+
+// minRSAKeySize is the smallest RSA modulus in bits accepted for RS256.
+const minRSAKeySize = 2048
+
+// JSONWebKey is the public part of a key as published in the JWKS.
+// RSA keys set the modulus and exponent, Ed25519 keys set the curve and the public key.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// Key signs or verifies tokens using a single algorithm.
+// Keys are identified by their RFC 7638 thumbprint, which tokens carry in the kid header,
+// so a key keeps its ID when it is moved from signing to verification only.
+type Key struct {
+	ID        string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	jwk       *JSONWebKey
+}
+
+// Keyring contains the key signing new tokens and every key whose tokens are still accepted.
+type Keyring struct {
+	signingKey Key
+	keys       map[string]Key
+	legacyKey  *Key
+}
+
+// CreateHMACKey creates an HS256 key from a shared secret.
+func CreateHMACKey(secret []byte) (Key, error) {
+	if strings.TrimSpace(string(secret)) == "" {
+		return Key{}, fmt.Errorf("HMAC secret is empty")
+	}
+
+	id := thumbprint(map[string]string{"k": encode(secret), "kty": "oct"})
+	return Key{ID: id, method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+}
+
+// ParseKey reads a PEM-encoded RSA or Ed25519 key. Private keys can sign tokens, public keys can only verify them.
+// RSA keys sign with RS256, Ed25519 keys with EdDSA.
+func ParseKey(data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, fmt.Errorf("no PEM-encoded key found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return Key{}, fmt.Errorf("unsupported PEM block \"%s\"", block.Type)
+	}
+
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return createRSAKey(k, &k.PublicKey)
+	case *rsa.PublicKey:
+		return createRSAKey(nil, k)
+	case ed25519.PrivateKey:
+		return createEd25519Key(k, k.Public().(ed25519.PublicKey)), nil
+	case ed25519.PublicKey:
+		return createEd25519Key(nil, k), nil
+	default:
+		return Key{}, fmt.Errorf("unsupported key type %T, only RSA and Ed25519 keys are supported", parsed)
+	}
+}
+
+// createRSAKey creates an RS256 key. The private key is nil for verification-only keys.
+func createRSAKey(private *rsa.PrivateKey, public *rsa.PublicKey) (Key, error) {
+	if public.N.BitLen() < minRSAKeySize {
+		return Key{}, fmt.Errorf("RSA key has %d bits, at least %d are required", public.N.BitLen(), minRSAKeySize)
+	}
+
+	n := encode(public.N.Bytes())
+	e := encode(big.NewInt(int64(public.E)).Bytes())
+	id := thumbprint(map[string]string{"e": e, "kty": "RSA", "n": n})
+
+	key := Key{
+		ID:        id,
+		method:    jwt.SigningMethodRS256,
+		verifyKey: public,
+		jwk:       &JSONWebKey{KeyType: "RSA", KeyID: id, Use: "sig", Algorithm: "RS256", N: n, E: e},
+	}
+
+	if private != nil {
+		key.signKey = private
+	}
+
+	return key, nil
+}
+
+// createEd25519Key creates an EdDSA key. The private key is nil for verification-only keys.
+func createEd25519Key(private ed25519.PrivateKey, public ed25519.PublicKey) Key {
+	x := encode(public)
+	id := thumbprint(map[string]string{"crv": "Ed25519", "kty": "OKP", "x": x})
+
+	key := Key{
+		ID:        id,
+		method:    jwt.SigningMethodEdDSA,
+		verifyKey: public,
+		jwk:       &JSONWebKey{KeyType: "OKP", KeyID: id, Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: x},
+	}
+
+	if private != nil {
+		key.signKey = private
+	}
+
+	return key
+}
+
+// CreateKeyring creates a keyring signing with the given key and accepting the tokens of every given key.
+func CreateKeyring(signingKey Key, verificationKeys ...Key) (Keyring, error) {
+	if signingKey.signKey == nil {
+		return Keyring{}, fmt.Errorf("key %s cannot sign tokens, a private key is required", signingKey.ID)
+	}
+
+	keyring := Keyring{signingKey: signingKey, keys: map[string]Key{}}
+	for _, key := range append([]Key{signingKey}, verificationKeys...) {
+		keyring.keys[key.ID] = key
+
+		// Tokens issued before key IDs were introduced are signed by the HMAC secret.
+		if key.method == jwt.SigningMethodHS256 && keyring.legacyKey == nil {
+			legacyKey := key
+			keyring.legacyKey = &legacyKey
+		}
+	}
+
+	return keyring, nil
+}
+
+// LoadKeyring creates the keyring from the environment:
+// - JWT_PRIVATE_KEY_FILE: PEM-encoded RSA or Ed25519 private key signing new tokens
+// - JWT_SIGNING_KEY: HMAC secret, signs new tokens if no private key is set, otherwise only verifies them
+// - JWT_VERIFICATION_KEY_FILES: comma-separated PEM-encoded keys whose tokens are still accepted
+func LoadKeyring(logger *zap.SugaredLogger) (Keyring, error) {
+	var signingKey *Key
+	var verificationKeys []Key
+
+	if path := os.Getenv("JWT_PRIVATE_KEY_FILE"); path != "" {
+		key, err := readKey(path)
+		if err != nil {
+			return Keyring{}, fmt.Errorf("invalid JWT_PRIVATE_KEY_FILE: %v", err)
+		}
+		signingKey = &key
+	}
+
+	if secret := os.Getenv("JWT_SIGNING_KEY"); secret != "" {
+		key, err := CreateHMACKey([]byte(secret))
+		if err != nil {
+			return Keyring{}, fmt.Errorf("invalid JWT_SIGNING_KEY: %v", err)
+		}
+
+		if signingKey == nil {
+			signingKey = &key
+		} else {
+			verificationKeys = append(verificationKeys, key)
+		}
+	}
+
+	for _, path := range strings.Split(os.Getenv("JWT_VERIFICATION_KEY_FILES"), ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+
+		key, err := readKey(path)
+		if err != nil {
+			return Keyring{}, fmt.Errorf("invalid JWT_VERIFICATION_KEY_FILES entry \"%s\": %v", path, err)
+		}
+		verificationKeys = append(verificationKeys, key)
+	}
+
+	if signingKey == nil {
+		return Keyring{}, fmt.Errorf("no signing key configured, set JWT_PRIVATE_KEY_FILE or JWT_SIGNING_KEY")
+	}
+
+	keyring, err := CreateKeyring(*signingKey, verificationKeys...)
+	if err != nil {
+		return Keyring{}, err
+	}
+
+	logger.Infof("signing tokens with %s key %s, accepting %d keys", signingKey.method.Alg(), signingKey.ID, len(keyring.keys))
+	return keyring, nil
+}
+
+// readKey reads a PEM-encoded key from a file.
+func readKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+
+	return ParseKey(data)
+}
+
+// PublicKeys returns the public keys of the keyring, starting with the signing key.
+// HMAC secrets are never published.
+func (k Keyring) PublicKeys() []JSONWebKey {
+	keys := make([]JSONWebKey, 0, len(k.keys))
+	if k.signingKey.jwk != nil {
+		keys = append(keys, *k.signingKey.jwk)
+	}
+
+	var others []JSONWebKey
+	for id, key := range k.keys {
+		if key.jwk != nil && id != k.signingKey.ID {
+			others = append(others, *key.jwk)
+		}
+	}
+
+	sort.Slice(others, func(i, j int) bool {
+		return others[i].KeyID < others[j].KeyID
+	})
+
+	return append(keys, others...)
+}
+
+// sign creates a token with the given claims, signed by the signing key.
+func (k Keyring) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(k.signingKey.method, claims)
+	token.Header["kid"] = k.signingKey.ID
+
+	return token.SignedString(k.signingKey.signKey)
+}
+
+// verificationKey selects the key verifying the token by its kid header.
+// The algorithm of the token must match the key, otherwise a public key could be misused as an HMAC secret.
+func (k Keyring) verificationKey(token *jwt.Token) (interface{}, error) {
+	var key Key
+	switch id, ok := token.Header["kid"].(string); {
+	case ok:
+		found, exists := k.keys[id]
+		if !exists {
+			return nil, fmt.Errorf("unknown signing key: %s", id)
+		}
+		key = found
+	case k.legacyKey != nil:
+		key = *k.legacyKey
+	default:
+		return nil, fmt.Errorf("missing signing key ID")
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	return key.verifyKey, nil
+}
+
+// thumbprint calculates the RFC 7638 thumbprint of a key from its required members.
+// json.Marshal sorts the members and leaves no whitespace, as the RFC requires.
+func thumbprint(members map[string]string) string {
+	data, _ := json.Marshal(members)
+	sum := sha256.Sum256(data)
+	return encode(sum[:])
+}
+
+// encode encodes bytes as unpadded base64url, as used by JSON Web Keys.
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
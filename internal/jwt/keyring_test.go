@@ -0,0 +1,270 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	jwtlib "github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/jwt"
+	"github.com/wlachs/blog/internal/logger"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// encodePrivateKey encodes a private key as PKCS #8 PEM.
+func encodePrivateKey(t *testing.T, key crypto.PrivateKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.Nil(t, err, "failed to encode private key")
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// encodePublicKey encodes a public key as PKIX PEM.
+func encodePublicKey(t *testing.T, key crypto.PublicKey) []byte {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.Nil(t, err, "failed to encode public key")
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+// createEd25519Key generates an Ed25519 key pair and returns both its private and public part.
+func createEd25519Key(t *testing.T) (jwt.Key, jwt.Key) {
+	t.Helper()
+
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	privateKey, err := jwt.ParseKey(encodePrivateKey(t, private))
+	assert.Nil(t, err, "failed to parse private key")
+	publicKey, err := jwt.ParseKey(encodePublicKey(t, public))
+	assert.Nil(t, err, "failed to parse public key")
+
+	return privateKey, publicKey
+}
+
+// createTokenUtils creates TokenUtils signing with the first key and accepting the tokens of the others.
+func createTokenUtils(t *testing.T, signingKey jwt.Key, verificationKeys ...jwt.Key) jwt.TokenUtils {
+	t.Helper()
+
+	keyring, err := jwt.CreateKeyring(signingKey, verificationKeys...)
+	assert.Nil(t, err, "failed to create keyring")
+	return jwt.CreateTokenUtils(logger.CreateLogger(), keyring)
+}
+
+// keyID reads the kid header of a token without verifying it.
+func keyID(t *testing.T, token string) string {
+	t.Helper()
+
+	parsed, _, err := new(jwtlib.Parser).ParseUnverified(token, jwtlib.MapClaims{})
+	assert.Nil(t, err, "failed to parse token")
+	return parsed.Header["kid"].(string)
+}
+
+// TestKeyring_RS256 tests signing and verifying tokens with an RSA key.
+func TestKeyring_RS256(t *testing.T) {
+	t.Parallel()
+
+	private, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key, err := jwt.ParseKey(encodePrivateKey(t, private))
+	assert.Nil(t, err, "expected to complete without error")
+
+	sut := createTokenUtils(t, key)
+	token, _ := sut.GenerateJWT("TestAuthor", "author", "testSession")
+	claims, err := sut.ParseJWT(token)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, "TestAuthor", claims.UserName, "resolved user name doesn't match the expected value")
+	assert.Equal(t, key.ID, keyID(t, token), "token should name its key")
+	assert.True(t, strings.HasPrefix(token, "eyJhbGciOiJSUzI1NiI"), "token should be signed with RS256")
+}
+
+// TestKeyring_EdDSA tests signing and verifying tokens with an Ed25519 key.
+func TestKeyring_EdDSA(t *testing.T) {
+	t.Parallel()
+
+	key, _ := createEd25519Key(t)
+
+	sut := createTokenUtils(t, key)
+	token, _ := sut.GenerateJWT("TestAuthor", "author", "testSession")
+	claims, err := sut.ParseJWT(token)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, "TestAuthor", claims.UserName, "resolved user name doesn't match the expected value")
+	assert.Equal(t, key.ID, keyID(t, token), "token should name its key")
+}
+
+// TestKeyring_Rotation tests that tokens of the previous signing key stay valid after a rotation.
+func TestKeyring_Rotation(t *testing.T) {
+	t.Parallel()
+
+	oldKey, oldPublicKey := createEd25519Key(t)
+	newKey, _ := createEd25519Key(t)
+
+	token, _ := createTokenUtils(t, oldKey).GenerateJWT("TestAuthor", "author", "testSession")
+
+	_, err := createTokenUtils(t, newKey, oldPublicKey).ParseJWT(token)
+	assert.Nil(t, err, "token of the previous key should be accepted")
+
+	_, err = createTokenUtils(t, newKey).ParseJWT(token)
+	assert.NotNil(t, err, "token of a removed key should lead to error")
+	assert.Equal(t, "unknown signing key: "+oldKey.ID, err.Error(), "incorrect error type")
+}
+
+// TestKeyring_Algorithm_Mismatch tests that a public key cannot be misused as an HMAC secret.
+func TestKeyring_Algorithm_Mismatch(t *testing.T) {
+	t.Parallel()
+
+	key, publicKey := createEd25519Key(t)
+	sut := createTokenUtils(t, key)
+
+	forged := jwtlib.NewWithClaims(jwtlib.SigningMethodHS256, jwtlib.MapClaims{
+		"exp":  time.Now().Add(time.Hour).Unix(),
+		"user": "TestAuthor",
+		"role": "admin",
+		"sid":  "testSession",
+	})
+	forged.Header["kid"] = publicKey.ID
+	token, _ := forged.SignedString([]byte(publicKey.ID))
+
+	_, err := sut.ParseJWT(token)
+	assert.NotNil(t, err, "forged token should lead to error")
+	assert.Equal(t, "unexpected signing method: HS256", err.Error(), "incorrect error type")
+}
+
+// TestKeyring_Public_Signing_Key tests that a public key cannot sign tokens.
+func TestKeyring_Public_Signing_Key(t *testing.T) {
+	t.Parallel()
+
+	_, publicKey := createEd25519Key(t)
+
+	_, err := jwt.CreateKeyring(publicKey)
+	assert.NotNil(t, err, "public key should not sign tokens")
+}
+
+// TestKeyring_PublicKeys tests listing the public keys, starting with the signing key.
+func TestKeyring_PublicKeys(t *testing.T) {
+	t.Parallel()
+
+	signingKey, _ := createEd25519Key(t)
+	_, oldPublicKey := createEd25519Key(t)
+	hmacKey, _ := jwt.CreateHMACKey([]byte("testSecret"))
+
+	keyring, _ := jwt.CreateKeyring(signingKey, oldPublicKey, hmacKey)
+	keys := keyring.PublicKeys()
+
+	assert.Equal(t, 2, len(keys), "HMAC secrets should not be published")
+	assert.Equal(t, signingKey.ID, keys[0].KeyID, "signing key should be listed first")
+	assert.Equal(t, oldPublicKey.ID, keys[1].KeyID, "verification key should be listed")
+	assert.Equal(t, "OKP", keys[0].KeyType, "incorrect key type")
+	assert.Equal(t, "EdDSA", keys[0].Algorithm, "incorrect algorithm")
+	assert.Equal(t, "Ed25519", keys[0].Curve, "incorrect curve")
+	assert.Equal(t, "sig", keys[0].Use, "incorrect key use")
+}
+
+// TestParseKey_Thumbprint tests that RSA keys are identified by their RFC 7638 thumbprint, using the example of the RFC.
+func TestParseKey_Thumbprint(t *testing.T) {
+	t.Parallel()
+
+	n, _ := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+
+	key, err := jwt.ParseKey(encodePublicKey(t, public))
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", key.ID, "incorrect key ID")
+}
+
+// TestParseKey_Weak_RSA_Key tests rejecting RSA keys that are too short.
+func TestParseKey_Weak_RSA_Key(t *testing.T) {
+	t.Parallel()
+
+	private, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	_, err := jwt.ParseKey(encodePrivateKey(t, private))
+	assert.NotNil(t, err, "weak key should lead to error")
+	assert.Equal(t, "RSA key has 1024 bits, at least 2048 are required", err.Error(), "incorrect error type")
+}
+
+// TestParseKey_Invalid_Key tests parsing data that is not a PEM-encoded key.
+func TestParseKey_Invalid_Key(t *testing.T) {
+	t.Parallel()
+
+	_, err := jwt.ParseKey([]byte("not a key"))
+	assert.NotNil(t, err, "invalid key should lead to error")
+	assert.Equal(t, "no PEM-encoded key found", err.Error(), "incorrect error type")
+}
+
+// TestCreateHMACKey_Empty_Secret tests rejecting an empty HMAC secret.
+func TestCreateHMACKey_Empty_Secret(t *testing.T) {
+	t.Parallel()
+
+	_, err := jwt.CreateHMACKey([]byte(" "))
+	assert.NotNil(t, err, "empty secret should lead to error")
+}
+
+// TestLoadKeyring_No_Key tests that loading fails if no signing key is configured.
+func TestLoadKeyring_No_Key(t *testing.T) {
+	t.Setenv("JWT_PRIVATE_KEY_FILE", "")
+	t.Setenv("JWT_SIGNING_KEY", "")
+	t.Setenv("JWT_VERIFICATION_KEY_FILES", "")
+
+	_, err := jwt.LoadKeyring(logger.CreateLogger())
+	assert.NotNil(t, err, "missing key should lead to error")
+	assert.Equal(t, "no signing key configured, set JWT_PRIVATE_KEY_FILE or JWT_SIGNING_KEY", err.Error(), "incorrect error type")
+}
+
+// TestLoadKeyring_Invalid_Key_File tests that loading fails if a key file cannot be used.
+func TestLoadKeyring_Invalid_Key_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	_ = os.WriteFile(path, []byte("not a key"), 0600)
+
+	t.Setenv("JWT_PRIVATE_KEY_FILE", path)
+	t.Setenv("JWT_SIGNING_KEY", "testSecret")
+	t.Setenv("JWT_VERIFICATION_KEY_FILES", "")
+
+	_, err := jwt.LoadKeyring(logger.CreateLogger())
+	assert.NotNil(t, err, "invalid key file should lead to error")
+	assert.Equal(t, "invalid JWT_PRIVATE_KEY_FILE: no PEM-encoded key found", err.Error(), "incorrect error type")
+}
+
+// TestLoadKeyring_Migration tests signing with a private key while accepting the tokens of the HMAC secret and a retired key.
+func TestLoadKeyring_Migration(t *testing.T) {
+	dir := t.TempDir()
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	retiredPublic, retiredPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	privatePath := filepath.Join(dir, "private.pem")
+	retiredPath := filepath.Join(dir, "retired.pem")
+	_ = os.WriteFile(privatePath, encodePrivateKey(t, private), 0600)
+	_ = os.WriteFile(retiredPath, encodePublicKey(t, retiredPublic), 0600)
+
+	t.Setenv("JWT_PRIVATE_KEY_FILE", privatePath)
+	t.Setenv("JWT_SIGNING_KEY", "testSecret")
+	t.Setenv("JWT_VERIFICATION_KEY_FILES", " "+retiredPath+" ,")
+
+	keyring, err := jwt.LoadKeyring(logger.CreateLogger())
+	assert.Nil(t, err, "expected to complete without error")
+
+	sut := jwt.CreateTokenUtils(logger.CreateLogger(), keyring)
+	hmacKey, _ := jwt.CreateHMACKey([]byte("testSecret"))
+	retiredKey, _ := jwt.ParseKey(encodePrivateKey(t, retiredPrivate))
+
+	token, _ := sut.GenerateJWT("TestAuthor", "author", "testSession")
+	hmacToken, _ := createTokenUtils(t, hmacKey).GenerateJWT("TestAuthor", "author", "testSession")
+	retiredToken, _ := createTokenUtils(t, retiredKey).GenerateJWT("TestAuthor", "author", "testSession")
+
+	assert.Equal(t, keyring.PublicKeys()[0].KeyID, keyID(t, token), "private key should sign new tokens")
+	_, err = sut.ParseJWT(hmacToken)
+	assert.Nil(t, err, "tokens of the HMAC secret should be accepted")
+	_, err = sut.ParseJWT(retiredToken)
+	assert.Nil(t, err, "tokens of the retired key should be accepted")
+}
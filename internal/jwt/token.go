@@ -10,61 +10,100 @@ import (
 	"time"
 )
 
-// signingKey is the JWT secret key stored as an environment variable
-var signingKey = []byte(os.Getenv("JWT_SIGNING_KEY"))
+// defaultAccessTokenLifetime sets how long access tokens are valid if ACCESS_TOKEN_TTL is not set
+const defaultAccessTokenLifetime = 15 * time.Minute
+
+// Claims contains the user-related fields carried by a JWT.
+// The session ID links the token to the refresh tokens of the login that issued it.
+type Claims struct {
+	UserName  string
+	Role      string
+	SessionID string
+}
 
 // TokenUtils interface. JWT-related utility methods.
 type TokenUtils interface {
-	ParseJWT(t string) (string, error)
-	GenerateJWT(userName string) (string, error)
+	ParseJWT(t string) (Claims, error)
+	GenerateJWT(userName string, role string, sessionID string) (string, error)
+	PublicKeys() []JSONWebKey
 }
 
-// tokenUtils struct. Placeholder receiver struct for JWT utils.
+// tokenUtils struct. Receiver struct for JWT utils.
 type tokenUtils struct {
-	logger *zap.SugaredLogger
+	logger   *zap.SugaredLogger
+	keyring  Keyring
+	lifetime time.Duration
 }
 
-// CreateTokenUtils instantiates the tokenUtils implementation.
-func CreateTokenUtils(logger *zap.SugaredLogger) TokenUtils {
+// CreateTokenUtils instantiates the tokenUtils implementation signing and verifying tokens with the keyring.
+func CreateTokenUtils(logger *zap.SugaredLogger, keyring Keyring) TokenUtils {
 	return &tokenUtils{
-		logger: logger,
+		logger:   logger,
+		keyring:  keyring,
+		lifetime: loadAccessTokenLifetime(logger),
 	}
 }
 
-// ParseJWT parses a token and extracts the user field if valid.
-func (j tokenUtils) ParseJWT(t string) (string, error) {
-	token, err := jwt.Parse(t, func(token *jwt.Token) (interface{}, error) {
-		// Don't forget to validate the alg is what you expect:
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+// loadAccessTokenLifetime reads how long access tokens are valid from the ACCESS_TOKEN_TTL environment variable.
+func loadAccessTokenLifetime(logger *zap.SugaredLogger) time.Duration {
+	value := os.Getenv("ACCESS_TOKEN_TTL")
+	if value == "" {
+		return defaultAccessTokenLifetime
+	}
+
+	lifetime, err := time.ParseDuration(value)
+	if err != nil || lifetime <= 0 {
+		logger.Errorf("invalid ACCESS_TOKEN_TTL value \"%s\", falling back to %v", value, defaultAccessTokenLifetime)
+		return defaultAccessTokenLifetime
+	}
+
+	return lifetime
+}
 
-		// hmacSampleSecret is a []byte containing your secret, e.g. []byte("my_secret_key")
-		return signingKey, nil
-	})
+// ParseJWT parses a token and extracts the user, role and session fields if valid.
+func (j tokenUtils) ParseJWT(t string) (Claims, error) {
+	token, err := jwt.Parse(t, j.keyring.verificationKey)
 
 	if err != nil {
-		return "", err
+		return Claims{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, fmt.Errorf("failed to get jwt claims")
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && claims["user"] != nil {
-		return claims["user"].(string), nil
-	} else {
-		return "", fmt.Errorf("failed to get jwt claims")
+	userName, userOk := claims["user"].(string)
+	role, roleOk := claims["role"].(string)
+	sessionID, sessionOk := claims["sid"].(string)
+	if !userOk || !roleOk || !sessionOk {
+		return Claims{}, fmt.Errorf("failed to get jwt claims")
 	}
+
+	return Claims{UserName: userName, Role: role, SessionID: sessionID}, nil
 }
 
 // GenerateJWT creates a JWT containing the following fields:
 // - username
+// - role
+// - session ID
 // - authorized flag
-// - expiration date
-func (j tokenUtils) GenerateJWT(userName string) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
-	claims := token.Claims.(jwt.MapClaims)
+// - issue and expiration date
+func (j tokenUtils) GenerateJWT(userName string, role string, sessionID string) (string, error) {
+	claims := jwt.MapClaims{}
+	now := time.Now()
 
-	claims["exp"] = time.Now().Add(24 * time.Hour).Unix()
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(j.lifetime).Unix()
 	claims["authorized"] = true
 	claims["user"] = userName
+	claims["role"] = role
+	claims["sid"] = sessionID
+
+	return j.keyring.sign(claims)
+}
 
-	return token.SignedString(signingKey)
+// PublicKeys returns the public keys verifying the tokens, so that other services can verify them too.
+func (j tokenUtils) PublicKeys() []JSONWebKey {
+	return j.keyring.PublicKeys()
 }
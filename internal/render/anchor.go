@@ -0,0 +1,122 @@
+package render
+
+import (
+	"fmt"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/slug"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+	"regexp"
+	"strings"
+)
+
+// anchorFallback is the anchor of headings without any usable characters
+const anchorFallback = "section"
+
+// anchorPattern restricts the id attributes kept on headings
+var anchorPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// headingElements are the HTML elements of the headings, indexed by their level
+var headingElements = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// headingAnchors assigns an anchor to every Markdown heading without an explicit one, derived from its text.
+// Anchors only depend on the text of the heading and of the preceding headings with the same text,
+// so they don't change when other parts of the post are edited.
+type headingAnchors struct{}
+
+// Transform implements the parser.ASTTransformer interface.
+func (headingAnchors) Transform(document *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+	var headings []*ast.Heading
+	used := map[string]bool{}
+
+	_ = ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := node.(*ast.Heading); ok && entering {
+			// Explicit anchors, written as {#anchor}, are reserved first
+			if id, found := heading.AttributeString("id"); found {
+				used[string(id.([]byte))] = true
+			} else {
+				headings = append(headings, heading)
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, heading := range headings {
+		base := slug.MakeWithFallback(plainText(heading, source), anchorFallback)
+
+		anchor := base
+		for i := 2; used[anchor]; i++ {
+			anchor = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		used[anchor] = true
+		heading.SetAttributeString("id", []byte(anchor))
+	}
+}
+
+// plainText collects the text of the node and its descendants.
+func plainText(node ast.Node, source []byte) string {
+	var b strings.Builder
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch t := n.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+
+	return b.String()
+}
+
+// TableOfContents lists the headings of the rendered HTML that have an anchor, in document order.
+func TableOfContents(body string) []repository.Heading {
+	var headings []repository.Heading
+	var current *repository.Heading
+	var b strings.Builder
+
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return headings
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			level, isHeading := headingElements[token.Data]
+			if !isHeading || current != nil {
+				continue
+			}
+
+			for _, attribute := range token.Attr {
+				if attribute.Key == "id" && attribute.Val != "" {
+					current = &repository.Heading{Level: level, Anchor: attribute.Val}
+					b.Reset()
+				}
+			}
+		case html.TextToken:
+			if current != nil {
+				b.Write(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if _, isHeading := headingElements[token.Data]; isHeading && current != nil {
+				current.Text = strings.Join(strings.Fields(b.String()), " ")
+				headings = append(headings, *current)
+				current = nil
+			}
+		}
+	}
+}
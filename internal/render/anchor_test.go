@@ -0,0 +1,54 @@
+package render_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"testing"
+)
+
+// TestRenderer_Render_Heading_Anchors tests generating unique anchors from the text of the headings.
+func TestRenderer_Render_Heading_Anchors(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.Render(repository.BodyFormatMarkdown, "# Getting *Started*\n\n## Setup\n\n## Setup\n\n### Größe & Ärger\n\n### 🚀\n")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "<h1 id=\"getting-started\">Getting <em>Started</em></h1>\n<h2 id=\"setup\">Setup</h2>\n<h2 id=\"setup-2\">Setup</h2>\n<h3 id=\"grosse-and-arger\">Größe &amp; Ärger</h3>\n<h3 id=\"section\">🚀</h3>\n", output, "incorrect anchors")
+}
+
+// TestRenderer_Render_Explicit_Anchors tests that explicit anchors are kept and reserved, unless they are invalid.
+func TestRenderer_Render_Explicit_Anchors(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.Render(repository.BodyFormatMarkdown, "## Setup\n\n## Installation {#setup}\n\n## Unsafe {id=\"a b\"}\n")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "<h2 id=\"setup-2\">Setup</h2>\n<h2 id=\"setup\">Installation</h2>\n<h2>Unsafe</h2>\n", output, "incorrect anchors")
+}
+
+// TestRenderer_Render_Stable_Anchors tests that editing the text around a heading doesn't change its anchor.
+func TestRenderer_Render_Stable_Anchors(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	before, _ := sut.Render(repository.BodyFormatMarkdown, "Intro\n\n## Details\n\nText\n")
+	after, _ := sut.Render(repository.BodyFormatMarkdown, "A longer intro.\n\n## Other\n\nMore\n\n## Details\n\nChanged text\n")
+
+	assert.Equal(t, []repository.Heading{{Level: 2, Text: "Details", Anchor: "details"}}, render.TableOfContents(before), "incorrect table of contents")
+	assert.Contains(t, render.TableOfContents(after), repository.Heading{Level: 2, Text: "Details", Anchor: "details"}, "anchor should be stable")
+}
+
+// TestTableOfContents tests listing the anchored headings of rendered HTML.
+func TestTableOfContents(t *testing.T) {
+	t.Parallel()
+
+	output := render.TableOfContents("<h1 id=\"title\">The <em>Title</em></h1>\n<p>text</p>\n<h2>No anchor</h2>\n<h3 id=\"q-a\">Q &amp; A</h3>\n")
+
+	assert.Equal(t, []repository.Heading{
+		{Level: 1, Text: "The Title", Anchor: "title"},
+		{Level: 3, Text: "Q & A", Anchor: "q-a"},
+	}, output, "incorrect table of contents")
+}
@@ -0,0 +1,235 @@
+package render
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/util"
+	stdhtml "html"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// version changes whenever the rendering pipeline changes in a way that invalidates previously cached output
+const version = "2"
+
+// DefaultElements are the HTML elements kept by default when sanitizing rendered posts
+const DefaultElements = "p,br,hr,h1,h2,h3,h4,h5,h6,strong,em,del,code,pre,blockquote,ul,ol,li,a,img,table,thead,tbody,tr,th,td"
+
+// DefaultAttributes are the HTML attributes kept by default when sanitizing rendered posts
+const DefaultAttributes = "a:href,a:title,img:src,img:alt,img:title,code:class,ol:start,th:align,td:align"
+
+// paragraphPattern matches the blank lines separating the paragraphs of plain text bodies
+var paragraphPattern = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// blockTagPattern matches the tags of the HTML elements that separate words
+var blockTagPattern = regexp.MustCompile(`(?i)</?(p|br|hr|h[1-6]|div|pre|blockquote|li|ul|ol|table|tr|th|td)\b`)
+
+// namePattern restricts the names of the HTML elements and attributes of a policy
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Policy lists the HTML elements and attributes that are kept when sanitizing rendered HTML.
+// Attributes are grouped by element, the attributes under "*" are allowed on every element.
+type Policy struct {
+	Elements   []string
+	Attributes map[string][]string
+}
+
+// ParsePolicy parses a comma-separated list of elements such as "p,a" and
+// a comma-separated list of element:attribute pairs such as "a:href,*:title".
+func ParsePolicy(elements string, attributes string) (Policy, error) {
+	policy := Policy{Attributes: map[string][]string{}}
+
+	for _, element := range strings.Split(elements, ",") {
+		element = strings.ToLower(strings.TrimSpace(element))
+		if element == "" {
+			continue
+		}
+
+		if !namePattern.MatchString(element) {
+			return Policy{}, fmt.Errorf("invalid HTML element \"%s\"", element)
+		}
+
+		policy.Elements = append(policy.Elements, element)
+	}
+
+	for _, entry := range strings.Split(attributes, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		element, attribute, found := strings.Cut(strings.ToLower(entry), ":")
+		element = strings.TrimSpace(element)
+		attribute = strings.TrimSpace(attribute)
+
+		if !found || (element != "*" && !namePattern.MatchString(element)) || !namePattern.MatchString(attribute) {
+			return Policy{}, fmt.Errorf("invalid HTML attribute definition \"%s\"", entry)
+		}
+
+		policy.Attributes[element] = append(policy.Attributes[element], attribute)
+	}
+
+	return policy, nil
+}
+
+// key returns a stable identifier of the policy.
+func (p Policy) key() string {
+	elements := append([]string{}, p.Elements...)
+	sort.Strings(elements)
+
+	var attributes []string
+	for element, names := range p.Attributes {
+		for _, name := range names {
+			attributes = append(attributes, element+":"+name)
+		}
+	}
+	sort.Strings(attributes)
+
+	return strings.Join(elements, ",") + ";" + strings.Join(attributes, ",")
+}
+
+// sanitizer creates the HTML sanitizer enforcing the policy.
+// Links and images are restricted to relative, http, https and mailto URLs.
+// Headings always keep their anchors, so that the table of contents can link to them.
+func (p Policy) sanitizer() *bluemonday.Policy {
+	sanitizer := bluemonday.NewPolicy()
+	sanitizer.AllowElements(p.Elements...)
+	sanitizer.AllowAttrs("id").Matching(anchorPattern).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+
+	for element, names := range p.Attributes {
+		if element == "*" {
+			sanitizer.AllowAttrs(names...).Globally()
+		} else {
+			sanitizer.AllowAttrs(names...).OnElements(element)
+		}
+	}
+
+	sanitizer.RequireParseableURLs(true)
+	sanitizer.AllowRelativeURLs(true)
+	sanitizer.AllowURLSchemes("http", "https", "mailto")
+
+	return sanitizer
+}
+
+// Renderer converts post bodies to sanitized HTML.
+type Renderer interface {
+	Render(format repository.BodyFormat, body string) (string, error)
+	Key(format repository.BodyFormat) string
+	PlainText(format repository.BodyFormat, body string) (string, error)
+}
+
+// renderer is the concrete implementation of the Renderer interface.
+type renderer struct {
+	markdown  goldmark.Markdown
+	sanitizer *bluemonday.Policy
+	stripper  *bluemonday.Policy
+	policyKey string
+}
+
+// CreateRenderer instantiates a Renderer, sanitizing the output with the given policy.
+// Markdown bodies are rendered as GitHub Flavored Markdown. Raw HTML in the Markdown source is passed to the sanitizer,
+// so the allowed elements may also be written as HTML. Headings get anchors generated from their text, unless an
+// explicit anchor is given as in "## Heading {#anchor}".
+func CreateRenderer(policy Policy) Renderer {
+	return &renderer{
+		markdown: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithParserOptions(
+				parser.WithAttribute(),
+				parser.WithASTTransformers(util.Prioritized(headingAnchors{}, 1000)),
+			),
+			goldmark.WithRendererOptions(html.WithUnsafe()),
+		),
+		sanitizer: policy.sanitizer(),
+		stripper:  bluemonday.StrictPolicy(),
+		policyKey: policy.key(),
+	}
+}
+
+// Render converts the body written in the given format to sanitized HTML.
+// Bodies without format are treated as Markdown.
+func (r renderer) Render(format repository.BodyFormat, body string) (string, error) {
+	unsafe, err := r.toHTML(format, body)
+	if err != nil {
+		return "", err
+	}
+
+	return r.sanitizer.Sanitize(unsafe), nil
+}
+
+// PlainText strips the markup from the body written in the given format.
+// The words of the text are separated by single spaces.
+func (r renderer) PlainText(format repository.BodyFormat, body string) (string, error) {
+	unsafe, err := r.toHTML(format, body)
+	if err != nil {
+		return "", err
+	}
+
+	// Words in separate blocks must not be joined when the tags are stripped
+	unsafe = blockTagPattern.ReplaceAllString(unsafe, " $0")
+	text := stdhtml.UnescapeString(r.stripper.Sanitize(unsafe))
+	return strings.Join(strings.Fields(text), " "), nil
+}
+
+// toHTML converts the body written in the given format to unsanitized HTML.
+func (r renderer) toHTML(format repository.BodyFormat, body string) (string, error) {
+	var unsafe string
+
+	switch format {
+	case repository.BodyFormatMarkdown, "":
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(body), &buf); err != nil {
+			return "", err
+		}
+		unsafe = buf.String()
+	case repository.BodyFormatHTML:
+		unsafe = body
+	case repository.BodyFormatText:
+		unsafe = renderText(body)
+	default:
+		return "", fmt.Errorf("unknown body format \"%s\"", format)
+	}
+
+	return unsafe, nil
+}
+
+// Key identifies the configuration of the renderer for the given format.
+// Output cached with a different key has to be rendered again.
+func (r renderer) Key(format repository.BodyFormat) string {
+	if format == "" {
+		format = repository.BodyFormatMarkdown
+	}
+
+	hash := sha256.Sum256([]byte(version + ";" + string(format) + ";" + r.policyKey))
+	return hex.EncodeToString(hash[:])
+}
+
+// renderText converts plain text to HTML. Blank lines separate paragraphs, single line breaks are kept.
+func renderText(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, paragraph := range paragraphPattern.Split(strings.TrimSpace(body), -1) {
+		if paragraph == "" {
+			continue
+		}
+
+		lines := strings.Split(paragraph, "\n")
+		for i, line := range lines {
+			lines[i] = stdhtml.EscapeString(line)
+		}
+
+		b.WriteString("<p>" + strings.Join(lines, "<br>\n") + "</p>\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package render_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"testing"
+)
+
+// createDefaultRenderer creates a Renderer with the default policy and reduces code duplication.
+func createDefaultRenderer(t *testing.T) render.Renderer {
+	t.Helper()
+
+	policy, err := render.ParsePolicy(render.DefaultElements, render.DefaultAttributes)
+	assert.Nil(t, err, "default policy should be valid")
+
+	return render.CreateRenderer(policy)
+}
+
+// TestRenderer_Render tests rendering Markdown to HTML.
+func TestRenderer_Render(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.Render(repository.BodyFormatMarkdown, "# Title\n\nSome **bold** text with a [link](https://example.com).\n\n- one\n- ~~two~~\n")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "<h1 id=\"title\">Title</h1>\n<p>Some <strong>bold</strong> text with a <a href=\"https://example.com\">link</a>.</p>\n<ul>\n<li>one</li>\n<li><del>two</del></li>\n</ul>\n", output, "incorrect HTML")
+}
+
+// TestRenderer_Render_Sanitize tests that scripts, event handlers and unsafe URLs are removed.
+func TestRenderer_Render_Sanitize(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.Render(repository.BodyFormatMarkdown, "<script>alert(1)</script>\n\n<p onclick=\"alert(1)\">text</p>\n\n[click](javascript:alert(1))\n")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "\n<p>text</p>\n<p>click</p>\n", output, "unsafe HTML should be removed")
+}
+
+// TestRenderer_Render_Custom_Policy tests that only the configured elements and attributes are kept.
+func TestRenderer_Render_Custom_Policy(t *testing.T) {
+	t.Parallel()
+
+	policy, err := render.ParsePolicy("p, img", "img:src, *:title")
+	assert.Nil(t, err, "policy should be valid")
+	sut := render.CreateRenderer(policy)
+
+	output, err := sut.Render(repository.BodyFormatMarkdown, "**bold** ![alt](https://example.com/a.png \"image\")\n")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "<p>bold <img src=\"https://example.com/a.png\" title=\"image\"></p>\n", output, "incorrect HTML")
+}
+
+// TestRenderer_Key tests that the key changes with the policy.
+func TestRenderer_Key(t *testing.T) {
+	t.Parallel()
+
+	first, _ := render.ParsePolicy("p,a", "a:href")
+	second, _ := render.ParsePolicy("a, p", "a:href")
+	third, _ := render.ParsePolicy("p,a", "a:href,a:title")
+
+	assert.Equal(t, render.CreateRenderer(first).Key(repository.BodyFormatMarkdown), render.CreateRenderer(second).Key(repository.BodyFormatMarkdown), "equivalent policies should have the same key")
+	assert.NotEqual(t, render.CreateRenderer(first).Key(repository.BodyFormatMarkdown), render.CreateRenderer(third).Key(repository.BodyFormatMarkdown), "different policies should have different keys")
+}
+
+// TestParsePolicy_Invalid tests parsing invalid policies.
+func TestParsePolicy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := render.ParsePolicy("p,<script>", "")
+	assert.NotNil(t, err, "invalid element should lead to error")
+
+	_, err = render.ParsePolicy("p", "href")
+	assert.NotNil(t, err, "attribute without element should lead to error")
+}
+
+// TestRenderer_Render_HTML tests sanitizing bodies written in HTML.
+func TestRenderer_Render_HTML(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.Render(repository.BodyFormatHTML, "<p>**not markdown**</p><iframe src=\"https://example.com\"></iframe>")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "<p>**not markdown**</p>", output, "incorrect HTML")
+}
+
+// TestRenderer_Render_Text tests rendering plain text bodies with paragraphs and line breaks.
+func TestRenderer_Render_Text(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.Render(repository.BodyFormatText, "First <line>\r\nsecond *line*\n\n\nNext paragraph")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "<p>First &lt;line&gt;<br>\nsecond *line*</p>\n<p>Next paragraph</p>\n", output, "incorrect HTML")
+}
+
+// TestRenderer_Render_Unknown_Format tests rendering a body with an unsupported format.
+func TestRenderer_Render_Unknown_Format(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	_, err := sut.Render("rst", "body")
+
+	assert.NotNil(t, err, "unknown format should lead to error")
+}
+
+// TestRenderer_Key_Format tests that the key changes with the body format.
+func TestRenderer_Key_Format(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	assert.Equal(t, sut.Key(repository.BodyFormatMarkdown), sut.Key(""), "missing format should be treated as Markdown")
+	assert.NotEqual(t, sut.Key(repository.BodyFormatMarkdown), sut.Key(repository.BodyFormatHTML), "different formats should have different keys")
+}
+
+// TestRenderer_PlainText tests stripping Markdown from a body.
+func TestRenderer_PlainText(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.PlainText(repository.BodyFormatMarkdown, "# Title\n\nSome **bold** text &amp; a [link](https://example.com).\n\n- one\n- two\n\n<script>alert(1)</script>\n")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "Title Some bold text & a link. one two", output, "incorrect plain text")
+}
+
+// TestRenderer_PlainText_HTML tests that the words of adjacent HTML blocks are kept apart.
+func TestRenderer_PlainText_HTML(t *testing.T) {
+	t.Parallel()
+	sut := createDefaultRenderer(t)
+
+	output, err := sut.PlainText(repository.BodyFormatHTML, "<p>first<br>line</p><p>second <em>para</em>graph</p>")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "first line second paragraph", output, "incorrect plain text")
+}
@@ -0,0 +1,144 @@
+package render
+
+import (
+	stdhtml "html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ellipsis marks excerpts that were cut within a sentence
+const ellipsis = "…"
+
+// Excerpt shortens the plain text to at most the given number of characters, not counting the ellipsis.
+// The text is cut after the last complete sentence that fits, or at the last word boundary if the first sentence
+// alone is too long. Texts cut within a sentence end with an ellipsis.
+func Excerpt(text string, length int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= length {
+		return string(runes)
+	}
+
+	// Sentences end with punctuation followed by a space
+	for i := length - 1; i > 0; i-- {
+		if isSentenceEnd(runes[i]) && unicode.IsSpace(runes[i+1]) {
+			return string(runes[:i+1])
+		}
+	}
+
+	cut := length
+	for i := length; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+
+	return strings.TrimRight(string(runes[:cut]), " \t\n,;:-–—") + ellipsis
+}
+
+// CountWords counts the words of the plain text.
+func CountWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+// Highlight returns an HTML snippet of about the given number of bytes, taken from the text around the first
+// occurrence of a word of the query. Every occurrence of the words of the query in the snippet is wrapped in a <mark>
+// element, the rest of the text is escaped. Words are matched case-insensitively, but only as whole words.
+// The second return parameter reports whether any word of the query was found.
+func Highlight(text string, query string, length int) (string, bool) {
+	matches := findWords(text, query)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	// Start a little before the first match, at a word boundary
+	start := matches[0][0] - length/4
+	if start <= 0 {
+		start = 0
+	} else if space := strings.IndexFunc(text[start:matches[0][0]], unicode.IsSpace); space >= 0 {
+		start += space + 1
+	} else {
+		start = matches[0][0]
+	}
+
+	end := start + length
+	if end >= len(text) {
+		end = len(text)
+	} else if space := strings.LastIndexFunc(text[matches[0][1]:end], unicode.IsSpace); space >= 0 {
+		end = matches[0][1] + space
+	} else {
+		end = max(end, matches[0][1])
+		for end < len(text) && !utf8.RuneStart(text[end]) {
+			end++
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+
+	position := start
+	for _, match := range matches {
+		if match[0] < start || match[1] > end {
+			continue
+		}
+
+		b.WriteString(stdhtml.EscapeString(text[position:match[0]]))
+		b.WriteString("<mark>" + stdhtml.EscapeString(text[match[0]:match[1]]) + "</mark>")
+		position = match[1]
+	}
+	b.WriteString(stdhtml.EscapeString(text[position:end]))
+
+	if end < len(text) {
+		b.WriteString(ellipsis)
+	}
+
+	return b.String(), true
+}
+
+// findWords returns the byte ranges of the occurrences of the words of the query in the text.
+func findWords(text string, query string) [][]int {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !isWordRune(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	// Longer words are preferred if a word is the prefix of another one
+	sort.Slice(words, func(i, j int) bool {
+		return len(words[i]) > len(words[j])
+	})
+
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+
+	pattern := regexp.MustCompile("(?i)" + strings.Join(words, "|"))
+
+	var matches [][]int
+	for _, match := range pattern.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+
+		if !isWordRune(before) && !isWordRune(after) {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+// isWordRune checks whether the character is part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isSentenceEnd checks whether the character terminates a sentence.
+func isSentenceEnd(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
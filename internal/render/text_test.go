@@ -0,0 +1,65 @@
+package render_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/render"
+	"testing"
+)
+
+// TestExcerpt tests shortening texts at sentence and word boundaries.
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		length   int
+		expected string
+	}{
+		{"Short text.", 20, "Short text."},
+		{"First sentence. Second sentence is longer.", 30, "First sentence."},
+		{"Does it work? Yes! It surely does.", 20, "Does it work? Yes!"},
+		{"A single sentence that is far too long.", 20, "A single sentence…"},
+		{"Words, separated by commas, are cut", 14, "Words…"},
+		{"Unbreakable", 5, "Unbre…"},
+		{"Ünïcödé characters count once.", 10, "Ünïcödé…"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, render.Excerpt(test.text, test.length), "incorrect excerpt of %q", test.text)
+	}
+}
+
+// TestCountWords tests counting the words of a text.
+func TestCountWords(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, render.CountWords(" "), "empty text should have no words")
+	assert.Equal(t, 4, render.CountWords("one two\nthree  four"), "incorrect word count")
+}
+
+// TestHighlight tests highlighting the words of a query in a snippet of the text.
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		query    string
+		length   int
+		expected string
+		found    bool
+	}{
+		{"Go is fun & Go is <fast>", "go", 100, "<mark>Go</mark> is fun &amp; <mark>Go</mark> is &lt;fast&gt;", true},
+		{"Gophers go going", "GO", 100, "Gophers <mark>go</mark> going", true},
+		{"The quick brown fox jumps over the lazy dog", "lazy fox", 100, "The quick brown <mark>fox</mark> jumps over the <mark>lazy</mark> dog", true},
+		{"one two three four five six seven eight nine ten", "seven", 20, "…six <mark>seven</mark> eight…", true},
+		{"Árvíztűrő tükörfúrógép", "TÜKÖRFÚRÓGÉP", 100, "Árvíztűrő <mark>tükörfúrógép</mark>", true},
+		{"no match here", "missing", 100, "", false},
+		{"symbols only", "!?", 100, "", false},
+	}
+
+	for _, test := range tests {
+		snippet, found := render.Highlight(test.text, test.query, test.length)
+		assert.Equal(t, test.expected, snippet, "incorrect snippet of %q", test.text)
+		assert.Equal(t, test.found, found, "incorrect match of %q", test.text)
+	}
+}
@@ -0,0 +1,167 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=api_token.go -destination=../mocks/mock_api_token_repository.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/errortypes"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Scope of a personal API token, determining which routes it may be used for.
+// Public routes, such as reading users, categories and series, need no token, so there are no scopes for them.
+type Scope string
+
+const (
+	// ScopePostsRead tokens may read drafts, the trash and the revisions of posts.
+	ScopePostsRead Scope = "posts:read"
+	// ScopePostsWrite tokens may add, modify, publish and delete posts.
+	ScopePostsWrite Scope = "posts:write"
+	// ScopeCategoriesWrite tokens may add, modify and delete categories.
+	ScopeCategoriesWrite Scope = "categories:write"
+	// ScopeSeriesWrite tokens may add, modify and delete series.
+	ScopeSeriesWrite Scope = "series:write"
+	// ScopeUsersWrite tokens may add, modify and delete users.
+	ScopeUsersWrite Scope = "users:write"
+)
+
+// Valid checks whether the scope is one of the supported scopes.
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopePostsRead, ScopePostsWrite, ScopeCategoriesWrite, ScopeSeriesWrite, ScopeUsersWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIToken DB schema. Personal API tokens are long-lived and only the hash of the token is stored.
+type APIToken struct {
+	ID         uint    `gorm:"primaryKey;autoIncrement"`
+	Name       string  `gorm:"size:64;not null"`
+	TokenHash  string  `gorm:"size:64;unique;not null"`
+	Scopes     []Scope `gorm:"type:json;serializer:json"`
+	UserID     uint    `gorm:"index;not null"`
+	User       User    `gorm:"constraint:OnDelete:CASCADE;"`
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// APITokenRepository interface defining personal API token-related database operations.
+type APITokenRepository interface {
+	AddAPIToken(token APIToken) (APIToken, error)
+	GetAPITokens(userID uint) ([]APIToken, error)
+	GetAPITokenByHash(tokenHash string) (APIToken, error)
+	UpdateAPITokenLastUsed(tokenID uint, lastUsedAt time.Time) error
+	DeleteAPIToken(userID uint, tokenID uint) error
+}
+
+// apiTokenRepository is the concrete implementation of the APITokenRepository interface.
+type apiTokenRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateAPITokenRepository instantiates the apiTokenRepository
+func CreateAPITokenRepository(logger *zap.SugaredLogger, repository Repository) APITokenRepository {
+	initAPITokenModel(logger, repository)
+
+	return &apiTokenRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initAPITokenModel initializes the APIToken schema in the database.
+func initAPITokenModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&APIToken{}); err != nil {
+		logger.Errorf("failed to initialize API token model: %v", err)
+	}
+}
+
+// AddAPIToken stores a new personal API token.
+func (a apiTokenRepository) AddAPIToken(token APIToken) (APIToken, error) {
+	log := a.logger
+	repo := a.repository
+
+	if result := repo.Omit("User").Create(&token); result.Error != nil {
+		log.Debugf("failed to store API token \"%s\" of user %d, error: %v", token.Name, token.UserID, result.Error)
+		return APIToken{}, result.Error
+	}
+
+	log.Debugf("stored API token \"%s\" of user %d", token.Name, token.UserID)
+	return token, nil
+}
+
+// GetAPITokens retrieves the personal API tokens of the user, oldest first.
+func (a apiTokenRepository) GetAPITokens(userID uint) ([]APIToken, error) {
+	log := a.logger
+	repo := a.repository
+
+	var tokens []APIToken
+	result := repo.Where("user_id = ?", userID).Order("id").Find(&tokens)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve API tokens of user %d, error: %v", userID, result.Error)
+		return []APIToken{}, result.Error
+	}
+
+	log.Debugf("retrieved %d API tokens of user %d", len(tokens), userID)
+	return tokens, nil
+}
+
+// GetAPITokenByHash retrieves the personal API token with the given hash along with its user.
+func (a apiTokenRepository) GetAPITokenByHash(tokenHash string) (APIToken, error) {
+	log := a.logger
+	repo := a.repository
+
+	var token APIToken
+	result := repo.Preload("User").Where("token_hash = ?", tokenHash).Take(&token)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve API token, error: %v", result.Error)
+		if result.Error.Error() == "record not found" {
+			return APIToken{}, errortypes.InvalidAuthTokenError{}
+		}
+		return APIToken{}, result.Error
+	}
+
+	log.Debugf("retrieved API token %d of user %s", token.ID, token.User.UserName)
+	return token, nil
+}
+
+// UpdateAPITokenLastUsed records when the personal API token was last used.
+func (a apiTokenRepository) UpdateAPITokenLastUsed(tokenID uint, lastUsedAt time.Time) error {
+	log := a.logger
+	repo := a.repository
+
+	result := repo.Model(&APIToken{}).Where("id = ?", tokenID).Update("last_used_at", lastUsedAt)
+
+	if result.Error != nil {
+		log.Debugf("failed to update last use of API token %d, error: %v", tokenID, result.Error)
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteAPIToken revokes the personal API token of the user by deleting it.
+func (a apiTokenRepository) DeleteAPIToken(userID uint, tokenID uint) error {
+	log := a.logger
+	repo := a.repository
+
+	result := repo.Where("id = ? AND user_id = ?", tokenID, userID).Delete(&APIToken{})
+
+	if result.Error != nil {
+		log.Debugf("failed to delete API token %d of user %d, error: %v", tokenID, userID, result.Error)
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Debugf("API token %d of user %d not found", tokenID, userID)
+		return errortypes.APITokenNotFoundError{ID: tokenID}
+	}
+
+	log.Debugf("deleted API token %d of user %d", tokenID, userID)
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// apiTokenTestContext contains objects relevant for testing the APITokenRepository.
+type apiTokenTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.APITokenRepository
+}
+
+// createAPITokenRepositoryContext creates the context for testing the APITokenRepository and reduces code duplication.
+func createAPITokenRepositoryContext(t *testing.T) *apiTokenTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateAPITokenRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &apiTokenTestContext{mock, sut}
+}
+
+// TestAPITokenRepository_AddAPIToken tests storing a new personal API token.
+func TestAPITokenRepository_AddAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenRepositoryContext(t)
+
+	token := repository.APIToken{
+		Name:      "CI",
+		TokenHash: "hash",
+		Scopes:    []repository.Scope{repository.ScopePostsWrite},
+		UserID:    1,
+	}
+
+	query := regexp.QuoteMeta("INSERT INTO `api_tokens` (`name`,`token_hash`,`scopes`,`user_id`,`last_used_at`,`created_at`) VALUES (?,?,?,?,?,?)")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).
+		WithArgs("CI", "hash", `["posts:write"]`, 1, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	c.mockDb.ExpectCommit()
+
+	result, err := c.sut.AddAPIToken(token)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, uint(1), result.ID, "stored token should have an ID")
+}
+
+// TestAPITokenRepository_GetAPITokens tests retrieving the personal API tokens of a user.
+func TestAPITokenRepository_GetAPITokens(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `api_tokens` WHERE user_id = ? ORDER BY id")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "scopes"}).
+			AddRow(1, "CI", `["posts:write"]`).
+			AddRow(2, "Backup", `["posts:read"]`))
+
+	tokens, err := c.sut.GetAPITokens(1)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(tokens), "incorrect number of tokens")
+	assert.Equal(t, []repository.Scope{repository.ScopePostsWrite}, tokens[0].Scopes, "scopes should be decoded")
+}
+
+// TestAPITokenRepository_GetAPITokenByHash_Not_Found tests retrieving a personal API token that doesn't exist.
+func TestAPITokenRepository_GetAPITokenByHash_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `api_tokens` WHERE token_hash = ? LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs("hash", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	token, err := c.sut.GetAPITokenByHash("hash")
+
+	assert.Equal(t, repository.APIToken{}, token, "should not return a token")
+	assert.Equal(t, errortypes.InvalidAuthTokenError{}, err, "received error should match the expected one")
+}
+
+// TestAPITokenRepository_UpdateAPITokenLastUsed tests recording the last use of a personal API token.
+func TestAPITokenRepository_UpdateAPITokenLastUsed(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenRepositoryContext(t)
+
+	now := time.Now()
+	query := regexp.QuoteMeta("UPDATE `api_tokens` SET `last_used_at`=? WHERE id = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(now, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.UpdateAPITokenLastUsed(1, now)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestAPITokenRepository_DeleteAPIToken tests deleting a personal API token of a user.
+func TestAPITokenRepository_DeleteAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenRepositoryContext(t)
+
+	query := regexp.QuoteMeta("DELETE FROM `api_tokens` WHERE id = ? AND user_id = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DeleteAPIToken(1, 3)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestAPITokenRepository_DeleteAPIToken_Not_Found tests deleting a personal API token of another user.
+func TestAPITokenRepository_DeleteAPIToken_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenRepositoryContext(t)
+
+	query := regexp.QuoteMeta("DELETE FROM `api_tokens` WHERE id = ? AND user_id = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(3, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DeleteAPIToken(1, 3)
+
+	assert.Equal(t, errortypes.APITokenNotFoundError{ID: 3}, err, "received error should match the expected one")
+}
@@ -0,0 +1,166 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=category.go -destination=../mocks/mock_category_repository.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/errortypes"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// Category DB schema
+type Category struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement"`
+	URLHandle string     `gorm:"type:varchar(128);unique;not null"`
+	Name      string     `gorm:"not null"`
+	ParentID  *uint      `gorm:"index"`
+	Parent    *Category  `gorm:"constraint:OnDelete:RESTRICT;"`
+	Children  []Category `gorm:"foreignKey:ParentID"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// categoryTreeCondition selects the posts of the category with the given URL handle and of all its descendants.
+const categoryTreeCondition = "posts.category_id IN (" +
+	"WITH RECURSIVE category_tree AS (" +
+	"SELECT categories.id FROM categories WHERE categories.url_handle = ? " +
+	"UNION ALL " +
+	"SELECT categories.id FROM categories JOIN category_tree ON categories.parent_id = category_tree.id" +
+	") SELECT category_tree.id FROM category_tree)"
+
+// CategoryRepository interface defining category-related database operations.
+type CategoryRepository interface {
+	AddCategory(category Category) (Category, error)
+	UpdateCategory(category Category) (Category, error)
+	DeleteCategory(urlHandle string) error
+	GetCategory(urlHandle string) (Category, error)
+	GetCategories() ([]Category, error)
+}
+
+// categoryRepository is the concrete implementation of the CategoryRepository interface.
+type categoryRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateCategoryRepository instantiates the categoryRepository
+func CreateCategoryRepository(logger *zap.SugaredLogger, repository Repository) CategoryRepository {
+	initCategoryModel(logger, repository)
+
+	return &categoryRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initCategoryModel initializes the Category schema in the database
+func initCategoryModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&Category{}); err != nil {
+		logger.Errorf("failed to initialize category model: %v", err)
+	}
+}
+
+// AddCategory adds a new category to the database.
+func (r categoryRepository) AddCategory(category Category) (Category, error) {
+	log := r.logger
+	repo := r.repository
+
+	if result := repo.Omit("Parent", "Children").Create(&category); result.Error == nil {
+		log.Debugf("created category: %v", category)
+		return r.GetCategory(category.URLHandle)
+	} else if strings.Contains(result.Error.Error(), "1062") {
+		log.Debugf("failed to create category, duplicate key: %s, error: %v", category.URLHandle, result.Error)
+		return Category{}, errortypes.DuplicateElementError{Key: category.URLHandle}
+	} else {
+		log.Debugf("failed to create category: %v, error: %s", category, result.Error)
+		return Category{}, result.Error
+	}
+}
+
+// UpdateCategory overwrites the name and the parent of an existing category.
+// Unlike most updates, a nil parent moves the category to the top level.
+func (r categoryRepository) UpdateCategory(category Category) (Category, error) {
+	log := r.logger
+	repo := r.repository
+
+	c := Category{
+		URLHandle: category.URLHandle,
+	}
+
+	if result := repo.Model(&Category{}).Where(&c).Select("Name", "ParentID").Updates(&category); result.Error == nil {
+		if result.RowsAffected > 0 {
+			log.Debugf("updated category: %v", category)
+			return r.GetCategory(category.URLHandle)
+		} else {
+			return Category{}, errortypes.CategoryNotFoundError{URLHandle: category.URLHandle}
+		}
+	} else {
+		log.Debugf("failed to update category: %v, error: %s", category, result.Error)
+		return Category{}, result.Error
+	}
+}
+
+// DeleteCategory deletes the category with the given URL handle from the database.
+// The posts of the category are left without a category.
+func (r categoryRepository) DeleteCategory(urlHandle string) error {
+	log := r.logger
+	repo := r.repository
+
+	category := Category{
+		URLHandle: urlHandle,
+	}
+
+	if result := repo.Where(category).Delete(category); result.Error == nil {
+		if result.RowsAffected > 0 {
+			log.Debugf("deleted category: %s", urlHandle)
+			return nil
+		} else {
+			return errortypes.CategoryNotFoundError{URLHandle: urlHandle}
+		}
+	} else {
+		log.Debugf("failed to delete category: %v, error: %s", category, result.Error)
+		return result.Error
+	}
+}
+
+// GetCategory retrieves the category with the given URL handle together with its parent and its direct children.
+func (r categoryRepository) GetCategory(urlHandle string) (Category, error) {
+	log := r.logger
+	repo := r.repository
+
+	category := Category{
+		URLHandle: urlHandle,
+	}
+
+	result := repo.Preload("Parent").Preload("Children").Where(&category).Take(&category)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve category with handle: %s, error: %v", urlHandle, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Category{}, errortypes.CategoryNotFoundError{URLHandle: urlHandle}
+		}
+		return Category{}, result.Error
+	}
+
+	log.Debugf("retrieved category: %v", category)
+	return category, nil
+}
+
+// GetCategories retrieves every category from the database ordered by name.
+// The relations aren't loaded, the tree can be built using the parent IDs.
+func (r categoryRepository) GetCategories() ([]Category, error) {
+	log := r.logger
+	repo := r.repository
+
+	var categories []Category
+	result := repo.Order("name ASC").Find(&categories)
+
+	if result.Error != nil {
+		log.Debugf("error fetching categories: %v", result.Error)
+		return []Category{}, result.Error
+	}
+
+	log.Debugf("fetched categories: %v", categories)
+	return categories, nil
+}
@@ -0,0 +1,217 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// categoryTestContext contains objects relevant for testing the CategoryRepository.
+type categoryTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.CategoryRepository
+}
+
+// createCategoryRepositoryContext creates the context for testing the CategoryRepository and reduces code duplication.
+func createCategoryRepositoryContext(t *testing.T) *categoryTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateCategoryRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &categoryTestContext{mock, sut}
+}
+
+// TestCategoryRepository_AddCategory tests adding a new category to the system
+func TestCategoryRepository_AddCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	parentID := uint(1)
+	inputCategory := repository.Category{
+		URLHandle: "go",
+		Name:      "Go",
+		ParentID:  &parentID,
+	}
+
+	insertQuery := regexp.QuoteMeta("INSERT INTO `categories` (`url_handle`,`name`,`parent_id`,`created_at`,`updated_at`) VALUES (?,?,?,?,?)")
+	query := regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`url_handle` = ? LIMIT ?")
+	parentQuery := regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ?")
+	childrenQuery := regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`parent_id` = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(2, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WithArgs("go", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "name", "parent_id"}).
+			AddRow(2, "go", "Go", parentID))
+	c.mockDb.ExpectQuery(childrenQuery).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "name", "parent_id"}))
+	c.mockDb.ExpectQuery(parentQuery).
+		WithArgs(parentID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "name"}).
+			AddRow(parentID, "engineering", "Engineering"))
+
+	category, err := c.sut.AddCategory(inputCategory)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "go", category.URLHandle, "incorrect category handle")
+	assert.Equal(t, "engineering", category.Parent.URLHandle, "incorrect parent handle")
+	assert.Equal(t, 0, len(category.Children), "category shouldn't have children")
+}
+
+// TestCategoryRepository_AddCategory_Duplicate_Category tests adding a category with an existing URL handle
+func TestCategoryRepository_AddCategory_Duplicate_Category(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	insertQuery := regexp.QuoteMeta("INSERT INTO `categories`")
+	expectedError := errortypes.DuplicateElementError{Key: "go"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(insertQuery).WillReturnError(fmt.Errorf("Error 1062 (23000): Duplicate entry"))
+	c.mockDb.ExpectRollback()
+
+	_, err := c.sut.AddCategory(repository.Category{URLHandle: "go", Name: "Go"})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestCategoryRepository_UpdateCategory tests moving a category to the top level
+func TestCategoryRepository_UpdateCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	updateQuery := regexp.QuoteMeta("UPDATE `categories` SET `name`=?,`parent_id`=?,`updated_at`=? WHERE `categories`.`url_handle` = ?")
+	query := regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`url_handle` = ? LIMIT ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).
+		WithArgs("Go", nil, sqlmock.AnyArg(), "go").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"url_handle", "name"}).AddRow("go", "Go"))
+
+	category, err := c.sut.UpdateCategory(repository.Category{URLHandle: "go", Name: "Go"})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, repository.Category{URLHandle: "go", Name: "Go"}, category, "category doesn't match the expected one")
+}
+
+// TestCategoryRepository_UpdateCategory_Record_Not_Found tests updating a non-existing category
+func TestCategoryRepository_UpdateCategory_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	updateQuery := regexp.QuoteMeta("UPDATE `categories`")
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "go"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	_, err := c.sut.UpdateCategory(repository.Category{URLHandle: "go", Name: "Go"})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestCategoryRepository_DeleteCategory tests deleting a category
+func TestCategoryRepository_DeleteCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `categories` WHERE `categories`.`url_handle` = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(deleteQuery).WithArgs("go").WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DeleteCategory("go")
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestCategoryRepository_DeleteCategory_Record_Not_Found tests deleting a non-existing category
+func TestCategoryRepository_DeleteCategory_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `categories` WHERE `categories`.`url_handle` = ?")
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "go"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(deleteQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DeleteCategory("go")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestCategoryRepository_GetCategory_Record_Not_Found tests retrieving a non-existing category
+func TestCategoryRepository_GetCategory_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`url_handle` = ? LIMIT ?")
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "go"}
+
+	c.mockDb.ExpectQuery(query).WillReturnError(fmt.Errorf("record not found"))
+
+	_, err := c.sut.GetCategory("go")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestCategoryRepository_GetCategories tests retrieving every category
+func TestCategoryRepository_GetCategories(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	parentID := uint(1)
+	expectedCategories := []repository.Category{
+		{ID: 1, URLHandle: "engineering", Name: "Engineering"},
+		{ID: 2, URLHandle: "go", Name: "Go", ParentID: &parentID},
+	}
+
+	query := regexp.QuoteMeta("SELECT * FROM `categories` ORDER BY name ASC")
+
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "name", "parent_id"}).
+			AddRow(1, "engineering", "Engineering", nil).
+			AddRow(2, "go", "Go", parentID))
+
+	categories, err := c.sut.GetCategories()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedCategories, categories, "categories don't match the expected ones")
+}
+
+// TestCategoryRepository_GetCategories_Unexpected_Error tests handling an unexpected error while retrieving the categories
+func TestCategoryRepository_GetCategories_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createCategoryRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `categories` ORDER BY name ASC")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	_, err := c.sut.GetCategories()
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
@@ -3,33 +3,201 @@ package repository
 //go:generate mockgen-v0.4.0 -source=post.go -destination=../mocks/mock_post_repository.go -package=mocks
 
 import (
+	"fmt"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/wlachs/blog/internal/errortypes"
 )
 
+// PostStatus describes the lifecycle state of a post
+type PostStatus string
+
+const (
+	// PostStatusDraft marks posts that are only visible to their author
+	PostStatusDraft PostStatus = "draft"
+	// PostStatusPublished marks posts that are visible to everyone
+	PostStatusPublished PostStatus = "published"
+	// PostStatusArchived marks posts that were taken out of circulation
+	PostStatusArchived PostStatus = "archived"
+)
+
+// BodyFormat describes the markup language of the body of a post
+type BodyFormat string
+
+const (
+	// BodyFormatMarkdown marks bodies written in Markdown
+	BodyFormatMarkdown BodyFormat = "markdown"
+	// BodyFormatHTML marks bodies written in HTML
+	BodyFormatHTML BodyFormat = "html"
+	// BodyFormatText marks bodies written in plain text
+	BodyFormatText BodyFormat = "text"
+)
+
 // Post DB schema
 type Post struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	URLHandle   string `gorm:"unique;not null"`
+	AuthorID    uint
+	Author      User
+	Title       *string
+	Summary     *string
+	Body        *string
+	BodyFormat  BodyFormat `gorm:"type:varchar(16);not null;default:markdown"`
+	BodyHTML    *string    `gorm:"-"`
+	Excerpt     *string    `gorm:"type:text"`
+	WordCount   *int
+	ReadingTime *int
+	Status      PostStatus `gorm:"type:varchar(16);not null;default:draft;index"`
+	PublishedAt *time.Time
+	PublishAt   *time.Time             `gorm:"index"`
+	UnpublishAt *time.Time             `gorm:"index"`
+	Meta        map[string]interface{} `gorm:"type:json;serializer:json"`
+	CategoryID  *uint                  `gorm:"index"`
+	Category    *Category              `gorm:"constraint:OnDelete:SET NULL;"`
+	Tags        []Tag                  `gorm:"many2many:post_tags;"`
+	Series      *SeriesNavigation      `gorm:"-"`
+	Older       *Post                  `gorm:"-"`
+	Newer       *Post                  `gorm:"-"`
+	Contents    []Heading              `gorm:"-"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt `gorm:"index"`
+}
+
+// Heading is an entry of the table of contents of a post, linking to the heading with the given anchor.
+type Heading struct {
+	Level  int
+	Text   string
+	Anchor string
+}
+
+// PostRedirect DB schema. Maps a former URL handle to the post that used it.
+type PostRedirect struct {
 	ID        uint   `gorm:"primaryKey;autoIncrement"`
 	URLHandle string `gorm:"unique;not null"`
-	AuthorID  uint
-	Author    User
-	Title     *string
-	Summary   *string
-	Body      *string
+	PostID    uint   `gorm:"index;not null"`
+	Post      Post   `gorm:"constraint:OnDelete:CASCADE;"`
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+// ArchiveMonth is the number of public posts created in a given month.
+type ArchiveMonth struct {
+	Year  int
+	Month int
+	Count int
+}
+
+// PostFilter narrows down the posts returned by GetPosts. Empty fields don't restrict the result.
+// CreatedFrom is inclusive, CreatedBefore is exclusive.
+type PostFilter struct {
+	AuthorID      uint
+	Tag           string
+	Category      string
+	Meta          map[string]string
+	CreatedFrom   time.Time
+	CreatedBefore time.Time
+}
+
+// apply adds the conditions of the filter to the query
+func (f PostFilter) apply(db *gorm.DB) *gorm.DB {
+	if f.AuthorID != 0 {
+		db = db.Where("posts.author_id = ?", f.AuthorID)
+	}
+
+	if f.Tag != "" {
+		db = db.Where(
+			"posts.id IN (SELECT post_tags.post_id FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE tags.name = ?)",
+			f.Tag,
+		)
+	}
+
+	if f.Category != "" {
+		db = db.Where(categoryTreeCondition, f.Category)
+	}
+
+	if !f.CreatedFrom.IsZero() {
+		db = db.Where("posts.created_at >= ?", f.CreatedFrom)
+	}
+
+	if !f.CreatedBefore.IsZero() {
+		db = db.Where("posts.created_at < ?", f.CreatedBefore)
+	}
+
+	// Sort the keys to keep the generated query deterministic
+	keys := make([]string, 0, len(f.Meta))
+	for key := range f.Meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		db = db.Where("JSON_UNQUOTE(JSON_EXTRACT(posts.meta, ?)) = ?", metaPath(key), f.Meta[key])
+	}
+
+	return db
+}
+
+// metaPath returns the JSON path of a metadata field in the meta column.
+func metaPath(key string) string {
+	return fmt.Sprintf("$.\"%s\"", key)
+}
+
+// publicPostCondition is the SQL counterpart of Post.IsPublishedAt.
+// It has to be used together with publicPostArgs.
+const publicPostCondition = "(posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)"
+
+// publicPostArgs returns the arguments of publicPostCondition evaluated at the given time.
+func publicPostArgs(t time.Time) []interface{} {
+	return []interface{}{PostStatusPublished, PostStatusDraft, t, t}
+}
+
+// IsPublished checks whether the post is currently visible to anonymous readers.
+func (p Post) IsPublished() bool {
+	return p.IsPublishedAt(time.Now())
+}
+
+// IsPublishedAt checks whether the post is visible to anonymous readers at the given time.
+// Drafts whose scheduled publication time has passed count as published even if the scheduler hasn't flipped them yet.
+func (p Post) IsPublishedAt(t time.Time) bool {
+	if p.UnpublishAt != nil && !p.UnpublishAt.After(t) {
+		return false
+	}
+
+	if p.Status == PostStatusPublished {
+		return true
+	}
+
+	return p.Status == PostStatusDraft && p.PublishAt != nil && !p.PublishAt.After(t)
 }
 
 // PostRepository interface defining post-related database operations.
 type PostRepository interface {
 	AddPost(post Post) (Post, error)
-	UpdatePost(post Post) (Post, error)
+	UpdatePost(post Post, editorID uint) (Post, error)
+	ReplacePostContent(post Post, editorID uint) (Post, error)
 	DeletePost(urlHandle string) error
 	GetPost(urlHandle string) (Post, error)
-	GetPosts(pageIndex int, pageSize int) ([]Post, int, error)
+	GetPostAuthor(urlHandle string) (User, error)
+	GetPosts(filter PostFilter, pageIndex int, pageSize int) ([]Post, int, error)
+	GetLatestPosts(filter PostFilter, limit int) ([]Post, error)
+	GetAdjacentPosts(post Post) (*Post, *Post, error)
+	GetArchiveMonths() ([]ArchiveMonth, error)
+	GetPostSitemap(pageIndex int, pageSize int) ([]Post, int, error)
+	GetPostsByAuthor(authorID uint, status PostStatus, pageIndex int, pageSize int) ([]Post, int, error)
+	GetTrashedPosts(filter PostFilter, pageIndex int, pageSize int) ([]Post, int, error)
+	RestorePost(urlHandle string) (Post, error)
+	RenamePost(urlHandle string, newURLHandle string) (Post, error)
+	GetRedirectedPost(urlHandle string) (Post, error)
+	GetReservedURLHandles(base string) ([]string, error)
+	PurgeTrashedPosts(deletedBefore time.Time) (int, error)
+	UpdatePostStatus(urlHandle string, status PostStatus, publishedAt *time.Time) (Post, error)
+	GetDuePosts(now time.Time) ([]Post, error)
+	PublishDuePost(id uint, now time.Time) (bool, error)
+	UnpublishDuePost(id uint, now time.Time) (bool, error)
 }
 
 // postRepository is the concrete implementation of the PostRepository interface.
@@ -48,33 +216,72 @@ func CreatePostRepository(logger *zap.SugaredLogger, repository Repository) Post
 	}
 }
 
-// initPostModel initializes the Post schema in the database
+// initPostModel initializes the Post schema in the database.
+// Posts created before the status column existed were public, so they are marked as published.
 func initPostModel(logger *zap.SugaredLogger, repository Repository) {
+	hadStatus := repository.Migrator().HasColumn(&Post{}, "Status")
+
 	if err := repository.AutoMigrate(&Post{}); err != nil {
 		logger.Errorf("failed to initialize post model: %v", err)
+		return
+	}
+
+	if err := repository.AutoMigrate(&PostRedirect{}); err != nil {
+		logger.Errorf("failed to initialize post redirect model: %v", err)
+	}
+
+	if hadStatus {
+		return
+	}
+
+	result := repository.
+		Model(&Post{}).
+		Where("1 = 1").
+		Updates(map[string]interface{}{
+			"status":       PostStatusPublished,
+			"published_at": gorm.Expr("created_at"),
+		})
+
+	if result.Error != nil {
+		logger.Errorf("failed to mark existing posts as published: %v", result.Error)
 	}
 }
 
 // AddPost adds a new post with the provided fields to the database.
-// The second parameter holds information about the author.
+// Its content is saved as the first revision of the post, attributed to its author.
 func (p postRepository) AddPost(post Post) (Post, error) {
 	log := p.logger
 	repo := p.repository
 
-	if result := repo.Create(&post); result.Error == nil {
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		if err := resolveTags(tx, post.Tags); err != nil {
+			log.Debugf("failed to resolve tags of post: %v, error: %v", post, err)
+			return err
+		}
+
+		if result := tx.Create(&post); result.Error != nil {
+			return result.Error
+		}
+
+		return addRevision(tx, post.URLHandle, post.AuthorID)
+	})
+
+	if err == nil {
 		log.Debugf("created post: %v", post)
 		return p.GetPost(post.URLHandle)
-	} else if strings.Contains(result.Error.Error(), "1062") {
-		log.Debugf("failed to create post, duplicate key: %s, error: %v", post.URLHandle, result.Error)
+	} else if strings.Contains(err.Error(), "1062") {
+		log.Debugf("failed to create post, duplicate key: %s, error: %v", post.URLHandle, err)
 		return Post{}, errortypes.DuplicateElementError{Key: post.URLHandle}
 	} else {
-		log.Debugf("failed to create post: %v, error: %s", post, result.Error)
-		return Post{}, result.Error
+		log.Debugf("failed to create post: %v, error: %s", post, err)
+		return Post{}, err
 	}
 }
 
 // UpdatePost updates an existing post with the provided fields to the database.
-func (p postRepository) UpdatePost(updatedPost Post) (Post, error) {
+// If the tags of the updated post are not nil, they replace the current tags of the post.
+// The updated content is saved as a new revision of the post, attributed to the given editor.
+func (p postRepository) UpdatePost(updatedPost Post, editorID uint) (Post, error) {
 	log := p.logger
 	repo := p.repository
 
@@ -82,20 +289,79 @@ func (p postRepository) UpdatePost(updatedPost Post) (Post, error) {
 		URLHandle: updatedPost.URLHandle,
 	}
 
-	if result := repo.Where(post).Updates(updatedPost); result.Error == nil {
-		if result.RowsAffected > 0 {
-			log.Debugf("updated post: %v", updatedPost)
-			return p.GetPost(post.URLHandle)
-		} else {
-			return Post{}, errortypes.PostNotFoundError{URLHandle: post.URLHandle}
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where(post).Omit("Tags", "Category").Updates(updatedPost)
+		if result.Error != nil {
+			return result.Error
 		}
-	} else {
-		log.Debugf("failed to update post: %v, error: %s", post, result.Error)
-		return Post{}, result.Error
+
+		if result.RowsAffected == 0 {
+			return errortypes.PostNotFoundError{URLHandle: post.URLHandle}
+		}
+
+		if updatedPost.Tags != nil {
+			if err := p.replaceTags(tx, post.URLHandle, updatedPost.Tags); err != nil {
+				return err
+			}
+		}
+
+		return addRevision(tx, post.URLHandle, editorID)
+	})
+
+	if err != nil {
+		log.Debugf("failed to update post: %v, error: %s", post, err)
+		return Post{}, err
+	}
+
+	log.Debugf("updated post: %v", updatedPost)
+	return p.GetPost(post.URLHandle)
+}
+
+// ReplacePostContent overwrites the title, the summary, the body, its format and the body statistics of an existing post.
+// Unlike UpdatePost, nil fields clear the stored values.
+// The replaced content is saved as a new revision of the post, attributed to the given editor.
+func (p postRepository) ReplacePostContent(updatedPost Post, editorID uint) (Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	post := Post{
+		URLHandle: updatedPost.URLHandle,
+	}
+
+	fields := map[string]interface{}{
+		"title":        updatedPost.Title,
+		"summary":      updatedPost.Summary,
+		"body":         updatedPost.Body,
+		"body_format":  updatedPost.BodyFormat,
+		"excerpt":      updatedPost.Excerpt,
+		"word_count":   updatedPost.WordCount,
+		"reading_time": updatedPost.ReadingTime,
+	}
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Post{}).Where(&post).Updates(fields)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errortypes.PostNotFoundError{URLHandle: post.URLHandle}
+		}
+
+		return addRevision(tx, post.URLHandle, editorID)
+	})
+
+	if err != nil {
+		log.Debugf("failed to replace content of post: %s, error: %v", post.URLHandle, err)
+		return Post{}, err
 	}
+
+	log.Debugf("replaced content of post: %s", post.URLHandle)
+	return p.GetPost(post.URLHandle)
 }
 
-// DeletePost deletes a post with the provided post ID from the database.
+// DeletePost moves the post with the provided post ID to the trash.
+// Trashed posts are hidden from every other query, but their URL handles stay reserved until they are purged.
 func (p postRepository) DeletePost(urlHandle string) error {
 	log := p.logger
 	repo := p.repository
@@ -104,9 +370,9 @@ func (p postRepository) DeletePost(urlHandle string) error {
 		URLHandle: urlHandle,
 	}
 
-	if result := repo.Where(post).Delete(post); result.Error == nil {
+	if result := repo.Where(&post).Delete(&Post{}); result.Error == nil {
 		if result.RowsAffected > 0 {
-			log.Debugf("deleted post: %s", urlHandle)
+			log.Debugf("moved post to trash: %s", urlHandle)
 			return nil
 		} else {
 			return errortypes.PostNotFoundError{URLHandle: urlHandle}
@@ -126,7 +392,7 @@ func (p postRepository) GetPost(urlHandle string) (Post, error) {
 		URLHandle: urlHandle,
 	}
 
-	result := repo.Preload("Author").Where(&post).Take(&post)
+	result := repo.Preload("Author").Preload("Tags").Preload("Category").Where(&post).Take(&post)
 
 	if result.Error != nil {
 		log.Debugf("failed to retrieve post with handle: %s, error: %v", urlHandle, result.Error)
@@ -140,14 +406,45 @@ func (p postRepository) GetPost(urlHandle string) (Post, error) {
 	return post, nil
 }
 
-// GetPosts retrieves a specific page of posts from the database.
-func (p postRepository) GetPosts(pageIndex int, pageSize int) ([]Post, int, error) {
+// GetPostAuthor retrieves the author of the post with the given handle, regardless of its status.
+// Trashed posts are included, so that the author of a post can be checked before restoring it.
+func (p postRepository) GetPostAuthor(urlHandle string) (User, error) {
 	log := p.logger
 	repo := p.repository
 
-	var posts []Post
+	var post Post
 	result := repo.
+		Unscoped().
+		Preload("Author").
+		Select("id", "url_handle", "author_id").
+		Where("url_handle = ?", urlHandle).
+		Take(&post)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve author of post with handle: %s, error: %v", urlHandle, result.Error)
+		if result.Error.Error() == "record not found" {
+			return User{}, errortypes.PostNotFoundError{URLHandle: urlHandle}
+		}
+		return User{}, result.Error
+	}
+
+	log.Debugf("retrieved author of post %s: %s", urlHandle, post.Author.UserName)
+	return post.Author, nil
+}
+
+// GetPosts retrieves a specific page of public posts matching the filter from the database.
+func (p postRepository) GetPosts(filter PostFilter, pageIndex int, pageSize int) ([]Post, int, error) {
+	log := p.logger
+	repo := p.repository
+
+	now := time.Now()
+
+	var posts []Post
+	result := filter.apply(repo.
 		Preload("Author").
+		Preload("Tags").
+		Preload("Category").
+		Where(publicPostCondition, publicPostArgs(now)...)).
 		Order("created_at DESC").
 		Limit(pageSize).
 		Offset((pageIndex - 1) * pageSize).
@@ -159,8 +456,502 @@ func (p postRepository) GetPosts(pageIndex int, pageSize int) ([]Post, int, erro
 	}
 
 	var count int64
-	repo.Model(&Post{}).Count(&count)
+	filter.apply(repo.Model(&Post{}).Where(publicPostCondition, publicPostArgs(now)...)).Count(&count)
 
 	log.Debugf("fetched posts: %v, item count %d", posts, count)
 	return posts, int(count), nil
 }
+
+// GetLatestPosts retrieves the most recently published public posts matching the filter.
+// Posts without publication time are ordered by their creation time instead.
+func (p postRepository) GetLatestPosts(filter PostFilter, limit int) ([]Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	var posts []Post
+	result := filter.apply(repo.
+		Preload("Author").
+		Preload("Tags").
+		Preload("Category").
+		Where(publicPostCondition, publicPostArgs(time.Now())...)).
+		Order("COALESCE(published_at, created_at) DESC").
+		Limit(limit).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching latest posts: %v", result.Error)
+		return []Post{}, result.Error
+	}
+
+	log.Debugf("fetched latest posts: %v", posts)
+	return posts, nil
+}
+
+// GetAdjacentPosts retrieves the public posts created right before and right after the given post, in this order.
+// Posts created at the same time are ordered by their ID. A missing neighbour is returned as nil.
+func (p postRepository) GetAdjacentPosts(post Post) (*Post, *Post, error) {
+	older, err := p.getAdjacentPost(post, "posts.created_at < ? OR (posts.created_at = ? AND posts.id < ?)", "created_at DESC, id DESC")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newer, err := p.getAdjacentPost(post, "posts.created_at > ? OR (posts.created_at = ? AND posts.id > ?)", "created_at, id")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return older, newer, nil
+}
+
+// getAdjacentPost retrieves the handle and the title of the first public post matching the condition in the given order.
+func (p postRepository) getAdjacentPost(post Post, condition string, order string) (*Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	var posts []Post
+	result := repo.
+		Select("id", "url_handle", "title", "created_at").
+		Where(publicPostCondition, publicPostArgs(time.Now())...).
+		Where(condition, post.CreatedAt, post.CreatedAt, post.ID).
+		Order(order).
+		Limit(1).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching neighbour of post %s: %v", post.URLHandle, result.Error)
+		return nil, result.Error
+	}
+
+	if len(posts) == 0 {
+		return nil, nil
+	}
+
+	return &posts[0], nil
+}
+
+// GetArchiveMonths counts the public posts by the month of their creation, most recent month first.
+// Months without public posts are left out.
+func (p postRepository) GetArchiveMonths() ([]ArchiveMonth, error) {
+	log := p.logger
+	repo := p.repository
+
+	var months []ArchiveMonth
+	result := repo.
+		Model(&Post{}).
+		Select("YEAR(posts.created_at) AS year, MONTH(posts.created_at) AS month, COUNT(*) AS count").
+		Where(publicPostCondition, publicPostArgs(time.Now())...).
+		Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&months)
+
+	if result.Error != nil {
+		log.Debugf("error fetching archive months: %v", result.Error)
+		return []ArchiveMonth{}, result.Error
+	}
+
+	log.Debugf("fetched archive months: %v", months)
+	return months, nil
+}
+
+// GetPostSitemap retrieves the URL handle and the update time of a specific page of public posts, in the order of
+// their creation. The second return parameter holds the overall item count.
+func (p postRepository) GetPostSitemap(pageIndex int, pageSize int) ([]Post, int, error) {
+	log := p.logger
+	repo := p.repository
+
+	now := time.Now()
+
+	var posts []Post
+	result := repo.
+		Select("id", "url_handle", "updated_at").
+		Where(publicPostCondition, publicPostArgs(now)...).
+		Order("id").
+		Limit(pageSize).
+		Offset((pageIndex - 1) * pageSize).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching post sitemap: %v", result.Error)
+		return []Post{}, -1, result.Error
+	}
+
+	var count int64
+	repo.Model(&Post{}).Where(publicPostCondition, publicPostArgs(now)...).Count(&count)
+
+	log.Debugf("fetched post sitemap, item count %d", count)
+	return posts, int(count), nil
+}
+
+// GetPostsByAuthor retrieves a specific page of posts with the given status written by the given author.
+// The second return parameter holds the overall item count.
+func (p postRepository) GetPostsByAuthor(authorID uint, status PostStatus, pageIndex int, pageSize int) ([]Post, int, error) {
+	log := p.logger
+	repo := p.repository
+
+	var posts []Post
+	result := repo.
+		Preload("Author").
+		Preload("Tags").
+		Preload("Category").
+		Where("author_id = ? AND status = ?", authorID, status).
+		Order("updated_at DESC").
+		Limit(pageSize).
+		Offset((pageIndex - 1) * pageSize).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching %s posts of author %d: %v", status, authorID, result.Error)
+		return []Post{}, -1, result.Error
+	}
+
+	var count int64
+	repo.Model(&Post{}).Where("author_id = ? AND status = ?", authorID, status).Count(&count)
+
+	log.Debugf("fetched %s posts of author %d: %v, item count %d", status, authorID, posts, count)
+	return posts, int(count), nil
+}
+
+// GetTrashedPosts retrieves a specific page of trashed posts matching the filter, most recently deleted first.
+// The second return parameter holds the overall item count.
+func (p postRepository) GetTrashedPosts(filter PostFilter, pageIndex int, pageSize int) ([]Post, int, error) {
+	log := p.logger
+	repo := p.repository
+
+	var posts []Post
+	result := filter.apply(repo.
+		Unscoped().
+		Preload("Author").
+		Preload("Tags").
+		Preload("Category").
+		Where("posts.deleted_at IS NOT NULL")).
+		Order("deleted_at DESC").
+		Limit(pageSize).
+		Offset((pageIndex - 1) * pageSize).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching trashed posts: %v", result.Error)
+		return []Post{}, -1, result.Error
+	}
+
+	var count int64
+	filter.apply(repo.Unscoped().Model(&Post{}).Where("posts.deleted_at IS NOT NULL")).Count(&count)
+
+	log.Debugf("fetched trashed posts: %v, item count %d", posts, count)
+	return posts, int(count), nil
+}
+
+// RestorePost moves the trashed post with the given URL handle back out of the trash.
+func (p postRepository) RestorePost(urlHandle string) (Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	result := repo.
+		Unscoped().
+		Model(&Post{}).
+		Where("url_handle = ? AND deleted_at IS NOT NULL", urlHandle).
+		UpdateColumn("deleted_at", nil)
+
+	if result.Error != nil {
+		log.Debugf("failed to restore post %s, error: %v", urlHandle, result.Error)
+		return Post{}, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return Post{}, errortypes.PostNotFoundError{URLHandle: urlHandle}
+	}
+
+	log.Debugf("restored post: %s", urlHandle)
+	return p.GetPost(urlHandle)
+}
+
+// RenamePost changes the URL handle of an existing post.
+// The former URL handle is kept as a redirect to the post. A redirect of the same post using the new URL handle is
+// dropped, while the former URL handles of other posts can't be taken over.
+func (p postRepository) RenamePost(urlHandle string, newURLHandle string) (Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		post := Post{
+			URLHandle: urlHandle,
+		}
+
+		if result := tx.Where(&post).Take(&post); result.Error != nil {
+			if result.Error.Error() == "record not found" {
+				return errortypes.PostNotFoundError{URLHandle: urlHandle}
+			}
+			return result.Error
+		}
+
+		if result := tx.Model(&post).Update("url_handle", newURLHandle); result.Error != nil {
+			if strings.Contains(result.Error.Error(), "1062") {
+				return errortypes.DuplicateElementError{Key: newURLHandle}
+			}
+			return result.Error
+		}
+
+		if result := tx.Where(&PostRedirect{URLHandle: newURLHandle, PostID: post.ID}).Delete(&PostRedirect{}); result.Error != nil {
+			return result.Error
+		}
+
+		var taken int64
+		if result := tx.Model(&PostRedirect{}).Where(&PostRedirect{URLHandle: newURLHandle}).Count(&taken); result.Error != nil {
+			return result.Error
+		} else if taken > 0 {
+			return errortypes.DuplicateElementError{Key: newURLHandle}
+		}
+
+		if result := tx.Create(&PostRedirect{URLHandle: urlHandle, PostID: post.ID}); result.Error != nil {
+			if strings.Contains(result.Error.Error(), "1062") {
+				return errortypes.DuplicateElementError{Key: urlHandle}
+			}
+			return result.Error
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Debugf("failed to rename post %s to %s, error: %v", urlHandle, newURLHandle, err)
+		return Post{}, err
+	}
+
+	log.Debugf("renamed post %s to %s", urlHandle, newURLHandle)
+	return p.GetPost(newURLHandle)
+}
+
+// GetRedirectedPost retrieves the post that used the given URL handle before it was renamed.
+func (p postRepository) GetRedirectedPost(urlHandle string) (Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	var post Post
+	result := repo.
+		Joins("JOIN post_redirects ON post_redirects.post_id = posts.id").
+		Where("post_redirects.url_handle = ?", urlHandle).
+		Take(&post)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve redirected post with handle: %s, error: %v", urlHandle, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Post{}, errortypes.PostNotFoundError{URLHandle: urlHandle}
+		}
+		return Post{}, result.Error
+	}
+
+	log.Debugf("resolved redirect %s to post: %s", urlHandle, post.URLHandle)
+	return post, nil
+}
+
+// GetReservedURLHandles retrieves the URL handles equal to the base or starting with the base and a hyphen.
+// Both the URL handles of posts, including trashed posts, and the URL handles of redirects are considered reserved.
+func (p postRepository) GetReservedURLHandles(base string) ([]string, error) {
+	log := p.logger
+	repo := p.repository
+
+	pattern := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(base) + "-%"
+
+	var postHandles []string
+	result := repo.
+		Unscoped().
+		Model(&Post{}).
+		Where("url_handle = ? OR url_handle LIKE ?", base, pattern).
+		Pluck("url_handle", &postHandles)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve URL handles of posts like %s, error: %v", base, result.Error)
+		return nil, result.Error
+	}
+
+	var redirectHandles []string
+	result = repo.
+		Model(&PostRedirect{}).
+		Where("url_handle = ? OR url_handle LIKE ?", base, pattern).
+		Pluck("url_handle", &redirectHandles)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve URL handles of redirects like %s, error: %v", base, result.Error)
+		return nil, result.Error
+	}
+
+	log.Debugf("retrieved URL handles like %s: %v %v", base, postHandles, redirectHandles)
+	return append(postHandles, redirectHandles...), nil
+}
+
+// PurgeTrashedPosts permanently deletes every post that was moved to the trash before the given time.
+// The revisions, the series entries and the redirects of the posts are removed by the database, the tag associations are removed explicitly.
+// The number of purged posts is returned.
+func (p postRepository) PurgeTrashedPosts(deletedBefore time.Time) (int, error) {
+	log := p.logger
+	repo := p.repository
+
+	var ids []uint
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Unscoped().
+			Model(&Post{}).
+			Where("deleted_at <= ?", deletedBefore).
+			Pluck("id", &ids)
+
+		if result.Error != nil || len(ids) == 0 {
+			return result.Error
+		}
+
+		if result := tx.Exec("DELETE FROM post_tags WHERE post_id IN ?", ids); result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&Post{}).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to purge posts trashed before %v, error: %v", deletedBefore, err)
+		return 0, err
+	}
+
+	log.Debugf("purged posts trashed before %v: %v", deletedBefore, ids)
+	return len(ids), nil
+}
+
+// UpdatePostStatus sets the lifecycle status and the publication time of an existing post.
+// Unlike UpdatePost, a nil publication time clears the stored value.
+// A manual status change cancels every pending publication schedule of the post.
+func (p postRepository) UpdatePostStatus(urlHandle string, status PostStatus, publishedAt *time.Time) (Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	post := Post{
+		URLHandle: urlHandle,
+	}
+
+	fields := map[string]interface{}{
+		"status":       status,
+		"published_at": publishedAt,
+		"publish_at":   nil,
+		"unpublish_at": nil,
+	}
+
+	if result := repo.Model(&Post{}).Where(&post).Updates(fields); result.Error == nil {
+		if result.RowsAffected > 0 {
+			log.Debugf("set status of post %s to %s", urlHandle, status)
+			return p.GetPost(urlHandle)
+		} else {
+			return Post{}, errortypes.PostNotFoundError{URLHandle: urlHandle}
+		}
+	} else {
+		log.Debugf("failed to set status of post %s to %s, error: %v", urlHandle, status, result.Error)
+		return Post{}, result.Error
+	}
+}
+
+// GetDuePosts retrieves every post with a scheduled status change that is due at the given time.
+func (p postRepository) GetDuePosts(now time.Time) ([]Post, error) {
+	log := p.logger
+	repo := p.repository
+
+	var posts []Post
+	result := repo.
+		Where("status = ? AND publish_at <= ?", PostStatusDraft, now).
+		Or("status = ? AND unpublish_at <= ?", PostStatusPublished, now).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching posts with due schedules: %v", result.Error)
+		return []Post{}, result.Error
+	}
+
+	log.Debugf("fetched posts with due schedules: %v", posts)
+	return posts, nil
+}
+
+// PublishDuePost publishes the draft with the given ID if its scheduled publication time has passed.
+// The scheduled time becomes the publication time of the post.
+// The update is conditional, so when several instances race for the same post, only one of them reports a change.
+func (p postRepository) PublishDuePost(id uint, now time.Time) (bool, error) {
+	log := p.logger
+	repo := p.repository
+
+	changed := false
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		// The schedule is only cleared in a second statement, since MySQL applies the assignments of an update in order
+		result := tx.
+			Model(&Post{}).
+			Where("id = ? AND status = ? AND publish_at <= ?", id, PostStatusDraft, now).
+			Updates(map[string]interface{}{
+				"status":       PostStatusPublished,
+				"published_at": gorm.Expr("publish_at"),
+			})
+
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		changed = true
+		return tx.Model(&Post{}).Where("id = ?", id).Update("publish_at", nil).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to publish scheduled post %d, error: %v", id, err)
+		return false, err
+	}
+
+	return changed, nil
+}
+
+// UnpublishDuePost turns the published post with the given ID back into a draft if its scheduled unpublication time has passed.
+// The update is conditional, so when several instances race for the same post, only one of them reports a change.
+func (p postRepository) UnpublishDuePost(id uint, now time.Time) (bool, error) {
+	log := p.logger
+	repo := p.repository
+
+	result := repo.
+		Model(&Post{}).
+		Where("id = ? AND status = ? AND unpublish_at <= ?", id, PostStatusPublished, now).
+		Updates(map[string]interface{}{
+			"status":       PostStatusDraft,
+			"published_at": nil,
+			"unpublish_at": nil,
+		})
+
+	if result.Error != nil {
+		log.Debugf("failed to unpublish scheduled post %d, error: %v", id, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// resolveTags looks up the given tags by name and creates the missing ones, so that every tag has an ID.
+func resolveTags(tx *gorm.DB, tags []Tag) error {
+	for i := range tags {
+		if result := tx.Where(Tag{Name: tags[i].Name}).FirstOrCreate(&tags[i]); result.Error != nil {
+			return result.Error
+		}
+	}
+
+	return nil
+}
+
+// replaceTags replaces the tags of the post with the given URL handle.
+func (p postRepository) replaceTags(tx *gorm.DB, urlHandle string, tags []Tag) error {
+	log := p.logger
+
+	if err := resolveTags(tx, tags); err != nil {
+		log.Debugf("failed to resolve tags of post %s, error: %v", urlHandle, err)
+		return err
+	}
+
+	post := Post{URLHandle: urlHandle}
+	if result := tx.Where(&post).Take(&post); result.Error != nil {
+		log.Debugf("failed to retrieve post %s for tag update, error: %v", urlHandle, result.Error)
+		return result.Error
+	}
+
+	if err := tx.Model(&post).Association("Tags").Replace(tags); err != nil {
+		log.Debugf("failed to replace tags of post %s, error: %v", urlHandle, err)
+		return err
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"gorm.io/gorm"
 	"regexp"
 	"testing"
+	"time"
 )
 
 // postTestContext contains objects relevant for testing the PostRepository.
@@ -47,11 +48,23 @@ func TestPostRepository_AddPost(t *testing.T) {
 		URLHandle: inputPost.URLHandle,
 	}
 
-	postQuery := regexp.QuoteMeta("INSERT INTO `posts` (`url_handle`,`author_id`,`title`,`summary`,`body`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)")
-	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? LIMIT ?")
+	postQuery := regexp.QuoteMeta("INSERT INTO `posts` (`url_handle`,`author_id`,`title`,`summary`,`body`,`body_format`,`excerpt`,`word_count`,`reading_time`,`status`,`published_at`,`publish_at`,`unpublish_at`,`meta`,`category_id`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	lockQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")
+	numberQuery := regexp.QuoteMeta("SELECT COALESCE(MAX(number), 0) FROM `revisions` WHERE post_id = ?")
+	revisionQuery := regexp.QuoteMeta("INSERT INTO `revisions`")
 
 	c.mockDb.ExpectBegin()
-	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(3, 1))
+	c.mockDb.ExpectQuery(lockQuery).
+		WithArgs(inputPost.URLHandle, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).AddRow(3, inputPost.URLHandle))
+	c.mockDb.ExpectQuery(numberQuery).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(0))
+	c.mockDb.ExpectExec(revisionQuery).
+		WithArgs(3, 1, inputPost.AuthorID, nil, nil, nil, "", nil, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	c.mockDb.ExpectCommit()
 	c.mockDb.ExpectQuery(query).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
@@ -76,7 +89,7 @@ func TestPostRepository_AddPost_Duplicate_Post(t *testing.T) {
 	dbErr := fmt.Errorf("1062")
 	expectedError := errortypes.DuplicateElementError{Key: inputPost.URLHandle}
 
-	postQuery := regexp.QuoteMeta("INSERT INTO `posts` (`url_handle`,`author_id`,`title`,`summary`,`body`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)")
+	postQuery := regexp.QuoteMeta("INSERT INTO `posts` (`url_handle`,`author_id`,`title`,`summary`,`body`,`body_format`,`excerpt`,`word_count`,`reading_time`,`status`,`published_at`,`publish_at`,`unpublish_at`,`meta`,`category_id`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(postQuery).WillReturnError(dbErr)
@@ -100,7 +113,7 @@ func TestPostRepository_AddPost_Unexpected_Error(t *testing.T) {
 
 	expectedError := fmt.Errorf("unexpected error")
 
-	postQuery := regexp.QuoteMeta("INSERT INTO `posts` (`url_handle`,`author_id`,`title`,`summary`,`body`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?)")
+	postQuery := regexp.QuoteMeta("INSERT INTO `posts` (`url_handle`,`author_id`,`title`,`summary`,`body`,`body_format`,`excerpt`,`word_count`,`reading_time`,`status`,`published_at`,`publish_at`,`unpublish_at`,`meta`,`category_id`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(postQuery).WillReturnError(expectedError)
@@ -131,17 +144,31 @@ func TestPostRepository_UpdatePost(t *testing.T) {
 		URLHandle: inputPost.URLHandle,
 	}
 
-	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`summary`=?,`body`=?,`updated_at`=? WHERE `posts`.`url_handle` = ?")
-	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? LIMIT ?")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`summary`=?,`body`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+
+	lockQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")
+	numberQuery := regexp.QuoteMeta("SELECT COALESCE(MAX(number), 0) FROM `revisions` WHERE post_id = ?")
+	revisionQuery := regexp.QuoteMeta("INSERT INTO `revisions`")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectQuery(lockQuery).
+		WithArgs(inputPost.URLHandle, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "title", "summary", "body"}).
+			AddRow(3, inputPost.URLHandle, title, summary, body))
+	c.mockDb.ExpectQuery(numberQuery).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(2))
+	c.mockDb.ExpectExec(revisionQuery).
+		WithArgs(3, 3, 5, title, summary, body, "", nil, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(7, 1))
 	c.mockDb.ExpectCommit()
 	c.mockDb.ExpectQuery(query).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
 			AddRow(expectedPost.ID, expectedPost.URLHandle))
 
-	post, err := c.sut.UpdatePost(inputPost)
+	post, err := c.sut.UpdatePost(inputPost, 5)
 
 	assert.Nil(t, err, "should complete without error")
 	assert.Equal(t, expectedPost, post, "received post should match the expected one")
@@ -162,14 +189,14 @@ func TestPostRepository_UpdatePost_Record_Not_Found(t *testing.T) {
 		Body:      &body,
 	}
 
-	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`summary`=?,`body`=?,`updated_at`=? WHERE `posts`.`url_handle` = ?")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`summary`=?,`body`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
 	expectedError := errortypes.PostNotFoundError{URLHandle: inputPost.URLHandle}
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 0))
-	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectRollback()
 
-	post, err := c.sut.UpdatePost(inputPost)
+	post, err := c.sut.UpdatePost(inputPost, 5)
 
 	assert.Equal(t, repository.Post{}, post, "should not return a post")
 	assert.Equal(t, expectedError, err, "received error should match the expected one")
@@ -190,17 +217,50 @@ func TestPostRepository_UpdatePost_Unexpected_Error(t *testing.T) {
 		Body:      &body,
 	}
 
-	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`summary`=?,`body`=?,`updated_at`=? WHERE `posts`.`url_handle` = ?")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`summary`=?,`body`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
 	expectedError := fmt.Errorf("unexpected error")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(postQuery).WillReturnError(expectedError)
 	c.mockDb.ExpectRollback()
 
-	post, err := c.sut.UpdatePost(inputPost)
+	post, err := c.sut.UpdatePost(inputPost, 5)
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_UpdatePost_Revision_Error tests that the update is rolled back if its revision can't be recorded
+func TestPostRepository_UpdatePost_Revision_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	title := "newTitle"
+	inputPost := repository.Post{
+		URLHandle: "testHandle",
+		Title:     &title,
+	}
+
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`title`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
+	lockQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")
+	numberQuery := regexp.QuoteMeta("SELECT COALESCE(MAX(number), 0) FROM `revisions` WHERE post_id = ?")
+	revisionQuery := regexp.QuoteMeta("INSERT INTO `revisions`")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectQuery(lockQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "title"}).AddRow(3, inputPost.URLHandle, title))
+	c.mockDb.ExpectQuery(numberQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(2))
+	c.mockDb.ExpectExec(revisionQuery).WillReturnError(expectedError)
+	c.mockDb.ExpectRollback()
+
+	post, err := c.sut.UpdatePost(inputPost, 5)
 
 	assert.Equal(t, repository.Post{}, post, "should not return a post")
 	assert.Equal(t, expectedError, err, "received error should match the expected one")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "the update should be rolled back")
 }
 
 // TestPostRepository_DeletePost tests deleting a post
@@ -210,7 +270,7 @@ func TestPostRepository_DeletePost(t *testing.T) {
 
 	urlHandle := "testHandle"
 
-	postQuery := regexp.QuoteMeta("DELETE FROM `posts` WHERE `posts`.`url_handle` = ?")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `deleted_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 1))
@@ -228,7 +288,7 @@ func TestPostRepository_DeletePost_Record_Not_Found(t *testing.T) {
 
 	urlHandle := "testHandle"
 
-	postQuery := regexp.QuoteMeta("DELETE FROM `posts` WHERE `posts`.`url_handle` = ?")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `deleted_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
 	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
 
 	c.mockDb.ExpectBegin()
@@ -247,7 +307,7 @@ func TestPostRepository_DeletePost_Unexpected_Error(t *testing.T) {
 
 	urlHandle := "testHandle"
 
-	postQuery := regexp.QuoteMeta("DELETE FROM `posts` WHERE `posts`.`url_handle` = ?")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `deleted_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
 	expectedError := fmt.Errorf("unexpected error")
 
 	c.mockDb.ExpectBegin()
@@ -268,7 +328,7 @@ func TestPostRepository_GetPost(t *testing.T) {
 		URLHandle: "testHandle",
 	}
 
-	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? LIMIT ?")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
 
 	c.mockDb.ExpectQuery(query).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
@@ -280,6 +340,42 @@ func TestPostRepository_GetPost(t *testing.T) {
 	assert.Equal(t, expectedPost, post, "received post should match the expected one")
 }
 
+// TestPostRepository_GetPostAuthor tests retrieving the author of a post, including trashed ones.
+func TestPostRepository_GetPostAuthor(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	postQuery := regexp.QuoteMeta("SELECT `id`,`url_handle`,`author_id` FROM `posts` WHERE url_handle = ? LIMIT ?")
+	authorQuery := regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`id` = ?")
+
+	c.mockDb.ExpectQuery(postQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "author_id"}).
+			AddRow(1, "testHandle", 2))
+	c.mockDb.ExpectQuery(authorQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_name"}).
+			AddRow(2, "testUser"))
+
+	author, err := c.sut.GetPostAuthor("testHandle")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "testUser", author.UserName, "received author should match the expected one")
+}
+
+// TestPostRepository_GetPostAuthor_Record_Not_Found tests retrieving the author of a non-existent post.
+func TestPostRepository_GetPostAuthor_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT `id`,`url_handle`,`author_id` FROM `posts` WHERE url_handle = ? LIMIT ?")
+	expectedError := errortypes.PostNotFoundError{URLHandle: "testHandle"}
+
+	c.mockDb.ExpectQuery(query).WillReturnError(fmt.Errorf("record not found"))
+
+	_, err := c.sut.GetPostAuthor("testHandle")
+
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
 // TestPostRepository_GetPost_Record_Not_Found tests retrieving a non-existent post from the database
 func TestPostRepository_GetPost_Record_Not_Found(t *testing.T) {
 	t.Parallel()
@@ -289,7 +385,7 @@ func TestPostRepository_GetPost_Record_Not_Found(t *testing.T) {
 		URLHandle: "testHandle",
 	}
 
-	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? LIMIT ?")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
 	dbErr := fmt.Errorf("record not found")
 	expectedError := errortypes.PostNotFoundError{URLHandle: expectedPost.URLHandle}
 
@@ -306,7 +402,7 @@ func TestPostRepository_GetPost_Unexpected_Error(t *testing.T) {
 	t.Parallel()
 	c := createPostRepositoryContext(t)
 
-	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? LIMIT ?")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
 	expectedError := fmt.Errorf("unexpected error")
 
 	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
@@ -322,15 +418,19 @@ func TestPostRepository_GetPosts(t *testing.T) {
 	t.Parallel()
 	c := createPostRepositoryContext(t)
 
-	query := regexp.QuoteMeta("SELECT * FROM `posts` ORDER BY created_at DESC LIMIT ? OFFSET ?")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC LIMIT ? OFFSET ?")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` IN (?,?)")
 
 	c.mockDb.ExpectQuery(query).
-		WithArgs(3, 3).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 3).
 		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
 			AddRow(1, "test_1").
 			AddRow(2, "test_2"))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
 
-	posts, _, err := c.sut.GetPosts(2, 3)
+	posts, _, err := c.sut.GetPosts(repository.PostFilter{}, 2, 3)
 
 	assert.Nil(t, err, "should complete without error")
 	assert.Equal(t, 2, len(posts), "didn't receive the expected number of posts")
@@ -341,13 +441,878 @@ func TestPostRepository_GetPosts_Unexpected_Error(t *testing.T) {
 	t.Parallel()
 	c := createPostRepositoryContext(t)
 
-	query := regexp.QuoteMeta("SELECT * FROM `posts` ORDER BY created_at DESC")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	posts, _, err := c.sut.GetPosts(repository.PostFilter{}, 1, 1)
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Equal(t, 0, len(posts), "shouldn't receive any posts")
+}
+
+// TestPostRepository_GetLatestPosts tests retrieving the most recently published posts
+func TestPostRepository_GetLatestPosts(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND posts.author_id = ? AND `posts`.`deleted_at` IS NULL ORDER BY COALESCE(published_at, created_at) DESC LIMIT ?")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` IN (?,?)")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(1, "test_1").
+			AddRow(2, "test_2"))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+	posts, err := c.sut.GetLatestPosts(repository.PostFilter{AuthorID: 3}, 20)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_GetLatestPosts_Unexpected_Error tests retrieving the most recently published posts with an error
+func TestPostRepository_GetLatestPosts_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("ORDER BY COALESCE(published_at, created_at) DESC LIMIT ?")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	posts, err := c.sut.GetLatestPosts(repository.PostFilter{}, 20)
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Equal(t, 0, len(posts), "shouldn't receive any posts")
+}
+
+// TestPostRepository_GetAdjacentPosts tests retrieving the public posts created before and after a post
+func TestPostRepository_GetAdjacentPosts(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	public := "((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?))"
+	olderQuery := regexp.QuoteMeta("SELECT `id`,`url_handle`,`title`,`created_at` FROM `posts` WHERE " + public + " AND (posts.created_at < ? OR (posts.created_at = ? AND posts.id < ?)) AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC, id DESC LIMIT ?")
+	newerQuery := regexp.QuoteMeta("SELECT `id`,`url_handle`,`title`,`created_at` FROM `posts` WHERE " + public + " AND (posts.created_at > ? OR (posts.created_at = ? AND posts.id > ?)) AND `posts`.`deleted_at` IS NULL ORDER BY created_at, id LIMIT ?")
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle", CreatedAt: time.Now()}
+
+	c.mockDb.ExpectQuery(olderQuery).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), post.CreatedAt, post.CreatedAt, post.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).AddRow(3, "older"))
+	c.mockDb.ExpectQuery(newerQuery).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), post.CreatedAt, post.CreatedAt, post.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+
+	older, newer, err := c.sut.GetAdjacentPosts(post)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "older", older.URLHandle, "incorrect older post")
+	assert.Nil(t, newer, "the newest post shouldn't have a newer neighbour")
+}
+
+// TestPostRepository_GetAdjacentPosts_Unexpected_Error tests retrieving the neighbours of a post with an error
+func TestPostRepository_GetAdjacentPosts_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(regexp.QuoteMeta("SELECT `id`,`url_handle`,`title`,`created_at` FROM `posts`")).WillReturnError(expectedError)
+
+	older, newer, err := c.sut.GetAdjacentPosts(repository.Post{ID: 4})
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Nil(t, older, "shouldn't receive an older post")
+	assert.Nil(t, newer, "shouldn't receive a newer post")
+}
+
+// TestPostRepository_GetPosts_Created_Period tests retrieving the posts created in a given period
+func TestPostRepository_GetPosts_Created_Period(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.Local)
+	before := from.AddDate(0, 1, 0)
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND posts.created_at >= ? AND posts.created_at < ? AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), from, before, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+
+	posts, _, err := c.sut.GetPosts(repository.PostFilter{CreatedFrom: from, CreatedBefore: before}, 1, 5)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, len(posts), "shouldn't receive any posts")
+}
+
+// TestPostRepository_GetPosts_Author tests retrieving the posts of an author
+func TestPostRepository_GetPosts_Author(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND posts.author_id = ? AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), 3, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+
+	posts, _, err := c.sut.GetPosts(repository.PostFilter{AuthorID: 3}, 1, 20)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, len(posts), "shouldn't receive any posts")
+}
+
+// TestPostRepository_GetArchiveMonths tests counting the public posts by month
+func TestPostRepository_GetArchiveMonths(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT YEAR(posts.created_at) AS year, MONTH(posts.created_at) AS month, COUNT(*) AS count FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND `posts`.`deleted_at` IS NULL GROUP BY year, month ORDER BY year DESC, month DESC")
+	expectedMonths := []repository.ArchiveMonth{
+		{Year: 2024, Month: 3, Count: 2},
+		{Year: 2023, Month: 12, Count: 1},
+	}
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"year", "month", "count"}).
+			AddRow(2024, 3, 2).
+			AddRow(2023, 12, 1))
+
+	months, err := c.sut.GetArchiveMonths()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedMonths, months, "incorrect archive months")
+}
+
+// TestPostRepository_GetArchiveMonths_Unexpected_Error tests counting the posts by month with an error
+func TestPostRepository_GetArchiveMonths_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(regexp.QuoteMeta("SELECT YEAR(posts.created_at)")).WillReturnError(expectedError)
+
+	months, err := c.sut.GetArchiveMonths()
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Equal(t, 0, len(months), "shouldn't receive any months")
+}
+
+// TestPostRepository_GetPostSitemap tests retrieving the URL handles of the public posts
+func TestPostRepository_GetPostSitemap(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	condition := "((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?))"
+	query := regexp.QuoteMeta("SELECT `id`,`url_handle`,`updated_at` FROM `posts` WHERE " + condition + " AND `posts`.`deleted_at` IS NULL ORDER BY id LIMIT ? OFFSET ?")
+	countQuery := regexp.QuoteMeta("SELECT count(*) FROM `posts` WHERE " + condition + " AND `posts`.`deleted_at` IS NULL")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(3, "test_3").
+			AddRow(4, "test_4"))
+	c.mockDb.ExpectQuery(countQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	posts, count, err := c.sut.GetPostSitemap(2, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 5, count, "incorrect item count")
+	assert.Equal(t, 2, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_GetPostsByAuthor tests retrieving the posts of an author with a given status
+func TestPostRepository_GetPostsByAuthor(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE (author_id = ? AND status = ?) AND `posts`.`deleted_at` IS NULL ORDER BY updated_at DESC LIMIT ? OFFSET ?")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` IN (?,?)")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(1, repository.PostStatusDraft, 3, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "status"}).
+			AddRow(1, "draft_1", repository.PostStatusDraft).
+			AddRow(2, "draft_2", repository.PostStatusDraft))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+	posts, _, err := c.sut.GetPostsByAuthor(1, repository.PostStatusDraft, 2, 3)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_GetPostsByAuthor_Unexpected_Error tests retrieving the posts of an author with an error
+func TestPostRepository_GetPostsByAuthor_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE (author_id = ? AND status = ?) AND `posts`.`deleted_at` IS NULL")
 	expectedError := fmt.Errorf("unexpected error")
 
 	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
 
-	posts, _, err := c.sut.GetPosts(1, 1)
+	posts, _, err := c.sut.GetPostsByAuthor(1, repository.PostStatusDraft, 1, 1)
 
 	assert.Equal(t, expectedError, err, "error should match expected value")
 	assert.Equal(t, 0, len(posts), "shouldn't receive any posts")
 }
+
+// TestPostRepository_UpdatePostStatus tests changing the status of a post
+func TestPostRepository_UpdatePostStatus(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedPost := repository.Post{
+		URLHandle: "testHandle",
+		Status:    repository.PostStatusDraft,
+	}
+
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `publish_at`=?,`published_at`=?,`status`=?,`unpublish_at`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "status"}).
+			AddRow(expectedPost.ID, expectedPost.URLHandle, expectedPost.Status))
+
+	post, err := c.sut.UpdatePostStatus(expectedPost.URLHandle, repository.PostStatusDraft, nil)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedPost, post, "received post should match the expected one")
+}
+
+// TestPostRepository_UpdatePostStatus_Record_Not_Found tests changing the status of a non-existing post
+func TestPostRepository_UpdatePostStatus_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	urlHandle := "testHandle"
+
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `publish_at`=?,`published_at`=?,`status`=?,`unpublish_at`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
+	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	post, err := c.sut.UpdatePostStatus(urlHandle, repository.PostStatusPublished, nil)
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_ReplacePostContent tests that replacing the content of a post clears the missing fields
+func TestPostRepository_ReplacePostContent(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	title := "testTitle"
+	expectedPost := repository.Post{
+		URLHandle:  "testHandle",
+		Title:      &title,
+		BodyFormat: repository.BodyFormatText,
+	}
+
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `body`=?,`body_format`=?,`excerpt`=?,`reading_time`=?,`summary`=?,`title`=?,`word_count`=?,`updated_at`=? WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	lockQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")
+	numberQuery := regexp.QuoteMeta("SELECT COALESCE(MAX(number), 0) FROM `revisions` WHERE post_id = ?")
+	revisionQuery := regexp.QuoteMeta("INSERT INTO `revisions`")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).
+		WithArgs(nil, repository.BodyFormatText, nil, nil, nil, title, nil, sqlmock.AnyArg(), expectedPost.URLHandle).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectQuery(lockQuery).
+		WithArgs(expectedPost.URLHandle, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "title", "body_format"}).
+			AddRow(3, expectedPost.URLHandle, title, expectedPost.BodyFormat))
+	c.mockDb.ExpectQuery(numberQuery).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(4))
+	c.mockDb.ExpectExec(revisionQuery).
+		WithArgs(3, 5, 2, title, nil, nil, repository.BodyFormatText, nil, "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "title", "body_format"}).
+			AddRow(expectedPost.ID, expectedPost.URLHandle, title, expectedPost.BodyFormat))
+
+	post, err := c.sut.ReplacePostContent(expectedPost, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedPost, post, "received post should match the expected one")
+}
+
+// TestPostRepository_GetDuePosts tests retrieving posts with due publication schedules
+func TestPostRepository_GetDuePosts(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((status = ? AND publish_at <= ?) OR (status = ? AND unpublish_at <= ?)) AND `posts`.`deleted_at` IS NULL")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusDraft, now, repository.PostStatusPublished, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(1, "scheduled"))
+
+	posts, err := c.sut.GetDuePosts(now)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 1, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_PublishDuePost tests publishing a post with a due publication schedule
+func TestPostRepository_PublishDuePost(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	now := time.Now()
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `published_at`=publish_at,`status`=?,`updated_at`=? WHERE (id = ? AND status = ? AND publish_at <= ?) AND `posts`.`deleted_at` IS NULL")
+	scheduleQuery := regexp.QuoteMeta("UPDATE `posts` SET `publish_at`=?,`updated_at`=? WHERE id = ? AND `posts`.`deleted_at` IS NULL")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).
+		WithArgs(repository.PostStatusPublished, sqlmock.AnyArg(), 1, repository.PostStatusDraft, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(scheduleQuery).
+		WithArgs(nil, sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	changed, err := c.sut.PublishDuePost(1, now)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.True(t, changed, "post should have been published")
+}
+
+// TestPostRepository_PublishDuePost_Already_Published tests publishing a post that another instance has already published
+func TestPostRepository_PublishDuePost_Already_Published(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	now := time.Now()
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `published_at`=publish_at,`status`=?,`updated_at`=? WHERE (id = ? AND status = ? AND publish_at <= ?) AND `posts`.`deleted_at` IS NULL")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	changed, err := c.sut.PublishDuePost(1, now)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.False(t, changed, "post should not be reported as changed")
+}
+
+// TestPostRepository_UnpublishDuePost tests unpublishing a post with a due unpublication schedule
+func TestPostRepository_UnpublishDuePost(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	now := time.Now()
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `published_at`=?,`status`=?,`unpublish_at`=?,`updated_at`=? WHERE (id = ? AND status = ? AND unpublish_at <= ?) AND `posts`.`deleted_at` IS NULL")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	changed, err := c.sut.UnpublishDuePost(1, now)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.True(t, changed, "post should have been unpublished")
+}
+
+// TestPostRepository_UnpublishDuePost_Unexpected_Error tests unpublishing a post with an error
+func TestPostRepository_UnpublishDuePost_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnError(expectedError)
+	c.mockDb.ExpectRollback()
+
+	changed, err := c.sut.UnpublishDuePost(1, time.Now())
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.False(t, changed, "post should not be reported as changed")
+}
+
+// TestPostRepository_GetPosts_Tag_Filter tests retrieving the posts with a given tag from the database
+func TestPostRepository_GetPosts_Tag_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("AND posts.id IN (SELECT post_tags.post_id FROM post_tags JOIN tags ON tags.id = post_tags.tag_id WHERE tags.name = ?) AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), "go", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+
+	posts, _, err := c.sut.GetPosts(repository.PostFilter{Tag: "go"}, 1, 5)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_AddPost_Tags tests adding a new post with tags to the system
+func TestPostRepository_AddPost_Tags(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	inputPost := repository.Post{
+		URLHandle: "testHandle",
+		Tags:      []repository.Tag{{Name: "go"}},
+	}
+
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `tags` WHERE `tags`.`name` = ? ORDER BY `tags`.`id` LIMIT ?")
+	postQuery := regexp.QuoteMeta("INSERT INTO `posts`")
+	tagInsertQuery := regexp.QuoteMeta("INSERT INTO `tags` (`name`,`created_at`,`updated_at`,`id`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `id`=`id`")
+	joinQuery := regexp.QuoteMeta("INSERT INTO `post_tags` (`post_id`,`tag_id`) VALUES (?,?) ON DUPLICATE KEY UPDATE `post_id`=`post_id`")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	preloadQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` = ?")
+	lockQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ? FOR UPDATE")
+	numberQuery := regexp.QuoteMeta("SELECT COALESCE(MAX(number), 0) FROM `revisions` WHERE post_id = ?")
+	revisionQuery := regexp.QuoteMeta("INSERT INTO `revisions`")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs("go", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "go"))
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(3, 1))
+	c.mockDb.ExpectExec(tagInsertQuery).WillReturnResult(sqlmock.NewResult(7, 0))
+	c.mockDb.ExpectExec(joinQuery).WithArgs(3, 7).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectQuery(lockQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).AddRow(3, inputPost.URLHandle))
+	c.mockDb.ExpectQuery(numberQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"number"}).AddRow(0))
+	c.mockDb.ExpectExec(revisionQuery).WillReturnResult(sqlmock.NewResult(1, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(3, inputPost.URLHandle))
+	c.mockDb.ExpectQuery(preloadQuery).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+	_, err := c.sut.AddPost(inputPost)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostRepository_GetPosts_Meta_Filter tests getting posts with the given metadata values
+func TestPostRepository_GetPosts_Meta_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("AND JSON_UNQUOTE(JSON_EXTRACT(posts.meta, ?)) = ? AND JSON_UNQUOTE(JSON_EXTRACT(posts.meta, ?)) = ? AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC LIMIT ?")
+	filter := repository.PostFilter{
+		Meta: map[string]string{"license": "cc-by", "featured": "true"},
+	}
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), `$."featured"`, "true", `$."license"`, "cc-by", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+
+	posts, _, err := c.sut.GetPosts(filter, 1, 5)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_GetPosts_Category_Filter tests getting posts of a category and its descendants
+func TestPostRepository_GetPosts_Category_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("AND posts.category_id IN (WITH RECURSIVE category_tree AS (SELECT categories.id FROM categories WHERE categories.url_handle = ? UNION ALL SELECT categories.id FROM categories JOIN category_tree ON categories.parent_id = category_tree.id) SELECT category_tree.id FROM category_tree) AND `posts`.`deleted_at` IS NULL ORDER BY created_at DESC LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), "engineering", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+
+	posts, _, err := c.sut.GetPosts(repository.PostFilter{Category: "engineering"}, 1, 5)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, len(posts), "didn't receive the expected number of posts")
+}
+
+// TestPostRepository_GetTrashedPosts tests retrieving the posts in the trash
+func TestPostRepository_GetTrashedPosts(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE posts.deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT ? OFFSET ?")
+	authorQuery := regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`id` = ?")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` IN (?,?)")
+	countQuery := regexp.QuoteMeta("SELECT count(*) FROM `posts` WHERE posts.deleted_at IS NOT NULL")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(3, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "author_id"}).
+			AddRow(1, "test_1", 1).
+			AddRow(2, "test_2", 1))
+	c.mockDb.ExpectQuery(authorQuery).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_name"}).
+			AddRow(1, "testAuthor"))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+	c.mockDb.ExpectQuery(countQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	posts, count, err := c.sut.GetTrashedPosts(repository.PostFilter{}, 2, 3)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(posts), "didn't receive the expected number of posts")
+	assert.Equal(t, 5, count, "didn't receive the expected item count")
+}
+
+// TestPostRepository_GetTrashedPosts_Author tests retrieving the posts of an author in the trash
+func TestPostRepository_GetTrashedPosts_Author(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE posts.deleted_at IS NOT NULL AND posts.author_id = ? ORDER BY deleted_at DESC LIMIT ?")
+	countQuery := regexp.QuoteMeta("SELECT count(*) FROM `posts` WHERE posts.deleted_at IS NOT NULL AND posts.author_id = ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}))
+	c.mockDb.ExpectQuery(countQuery).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	posts, count, err := c.sut.GetTrashedPosts(repository.PostFilter{AuthorID: 2}, 1, 3)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, len(posts), "didn't receive the expected number of posts")
+	assert.Equal(t, 0, count, "didn't receive the expected item count")
+}
+
+// TestPostRepository_GetTrashedPosts_Unexpected_Error tests retrieving the posts in the trash with an error
+func TestPostRepository_GetTrashedPosts_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE posts.deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT ?")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	posts, _, err := c.sut.GetTrashedPosts(repository.PostFilter{}, 1, 3)
+
+	assert.Equal(t, []repository.Post{}, posts, "should not return posts")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_RestorePost tests moving a post out of the trash
+func TestPostRepository_RestorePost(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedPost := repository.Post{
+		URLHandle: "testHandle",
+	}
+
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `deleted_at`=? WHERE url_handle = ? AND deleted_at IS NOT NULL")
+	query := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).
+		WithArgs(nil, expectedPost.URLHandle).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(expectedPost.ID, expectedPost.URLHandle))
+
+	post, err := c.sut.RestorePost(expectedPost.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedPost, post, "received post should match the expected one")
+}
+
+// TestPostRepository_RestorePost_Record_Not_Found tests restoring a post that isn't in the trash
+func TestPostRepository_RestorePost_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	urlHandle := "testHandle"
+
+	postQuery := regexp.QuoteMeta("UPDATE `posts` SET `deleted_at`=? WHERE url_handle = ? AND deleted_at IS NOT NULL")
+	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(postQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	post, err := c.sut.RestorePost(urlHandle)
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_PurgeTrashedPosts tests permanently deleting the posts trashed before a given time
+func TestPostRepository_PurgeTrashedPosts(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	deletedBefore := time.Now()
+
+	idQuery := regexp.QuoteMeta("SELECT `id` FROM `posts` WHERE deleted_at <= ?")
+	tagQuery := regexp.QuoteMeta("DELETE FROM post_tags WHERE post_id IN (?,?)")
+	postQuery := regexp.QuoteMeta("DELETE FROM `posts` WHERE id IN (?,?)")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(idQuery).
+		WithArgs(deletedBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).
+			AddRow(1).
+			AddRow(2))
+	c.mockDb.ExpectExec(tagQuery).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(postQuery).
+		WithArgs(1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	c.mockDb.ExpectCommit()
+
+	purged, err := c.sut.PurgeTrashedPosts(deletedBefore)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, purged, "didn't purge the expected number of posts")
+}
+
+// TestPostRepository_PurgeTrashedPosts_Empty_Trash tests purging when no post is due for deletion
+func TestPostRepository_PurgeTrashedPosts_Empty_Trash(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	idQuery := regexp.QuoteMeta("SELECT `id` FROM `posts` WHERE deleted_at <= ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(idQuery).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	c.mockDb.ExpectCommit()
+
+	purged, err := c.sut.PurgeTrashedPosts(time.Now())
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, purged, "no post should have been purged")
+}
+
+// TestPostRepository_RenamePost tests changing the URL handle of a post
+func TestPostRepository_RenamePost(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedPost := repository.Post{
+		ID:        4,
+		URLHandle: "newHandle",
+		Tags:      []repository.Tag{},
+	}
+
+	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	renameQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`updated_at`=? WHERE `posts`.`deleted_at` IS NULL AND `id` = ?")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `post_redirects` WHERE `post_redirects`.`url_handle` = ? AND `post_redirects`.`post_id` = ?")
+	countQuery := regexp.QuoteMeta("SELECT count(*) FROM `post_redirects` WHERE `post_redirects`.`url_handle` = ?")
+	redirectQuery := regexp.QuoteMeta("INSERT INTO `post_redirects` (`url_handle`,`post_id`,`created_at`) VALUES (?,?,?)")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(postQuery).
+		WithArgs("oldHandle", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(expectedPost.ID, "oldHandle"))
+	c.mockDb.ExpectExec(renameQuery).
+		WithArgs(expectedPost.URLHandle, sqlmock.AnyArg(), expectedPost.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(deleteQuery).
+		WithArgs(expectedPost.URLHandle, expectedPost.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectQuery(countQuery).
+		WithArgs(expectedPost.URLHandle).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	c.mockDb.ExpectExec(redirectQuery).
+		WithArgs("oldHandle", expectedPost.ID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(postQuery).
+		WithArgs(expectedPost.URLHandle, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(expectedPost.ID, expectedPost.URLHandle))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(expectedPost.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+	post, err := c.sut.RenamePost("oldHandle", expectedPost.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedPost, post, "received post should match the expected one")
+}
+
+// TestPostRepository_RenamePost_Record_Not_Found tests renaming a non-existing post
+func TestPostRepository_RenamePost_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	expectedError := errortypes.PostNotFoundError{URLHandle: "oldHandle"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(postQuery).WillReturnError(gorm.ErrRecordNotFound)
+	c.mockDb.ExpectRollback()
+
+	post, err := c.sut.RenamePost("oldHandle", "newHandle")
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_RenamePost_Duplicate_Post tests renaming a post to the URL handle of another post
+func TestPostRepository_RenamePost_Duplicate_Post(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	renameQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`updated_at`=? WHERE `posts`.`deleted_at` IS NULL AND `id` = ?")
+	expectedError := errortypes.DuplicateElementError{Key: "newHandle"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(postQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(4, "oldHandle"))
+	c.mockDb.ExpectExec(renameQuery).WillReturnError(fmt.Errorf("1062"))
+	c.mockDb.ExpectRollback()
+
+	post, err := c.sut.RenamePost("oldHandle", "newHandle")
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_RenamePost_Redirect_Of_Other_Post tests renaming a post to the former URL handle of another post
+func TestPostRepository_RenamePost_Redirect_Of_Other_Post(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`url_handle` = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+	renameQuery := regexp.QuoteMeta("UPDATE `posts` SET `url_handle`=?,`updated_at`=? WHERE `posts`.`deleted_at` IS NULL AND `id` = ?")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `post_redirects` WHERE `post_redirects`.`url_handle` = ? AND `post_redirects`.`post_id` = ?")
+	countQuery := regexp.QuoteMeta("SELECT count(*) FROM `post_redirects` WHERE `post_redirects`.`url_handle` = ?")
+	expectedError := errortypes.DuplicateElementError{Key: "newHandle"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectQuery(postQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(4, "oldHandle"))
+	c.mockDb.ExpectExec(renameQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(deleteQuery).
+		WithArgs("newHandle", 4).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectQuery(countQuery).
+		WithArgs("newHandle").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	c.mockDb.ExpectRollback()
+
+	post, err := c.sut.RenamePost("oldHandle", "newHandle")
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_GetRedirectedPost tests retrieving the post that used a URL handle before it was renamed
+func TestPostRepository_GetRedirectedPost(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	expectedPost := repository.Post{
+		ID:        4,
+		URLHandle: "newHandle",
+	}
+
+	query := regexp.QuoteMeta("FROM `posts` JOIN post_redirects ON post_redirects.post_id = posts.id WHERE post_redirects.url_handle = ? AND `posts`.`deleted_at` IS NULL LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs("oldHandle", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(expectedPost.ID, expectedPost.URLHandle))
+
+	post, err := c.sut.GetRedirectedPost("oldHandle")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedPost, post, "received post should match the expected one")
+}
+
+// TestPostRepository_GetRedirectedPost_Record_Not_Found tests resolving a URL handle that never belonged to a post
+func TestPostRepository_GetRedirectedPost_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	query := regexp.QuoteMeta("FROM `posts` JOIN post_redirects ON post_redirects.post_id = posts.id WHERE post_redirects.url_handle = ?")
+	expectedError := errortypes.PostNotFoundError{URLHandle: "oldHandle"}
+
+	c.mockDb.ExpectQuery(query).WillReturnError(gorm.ErrRecordNotFound)
+
+	post, err := c.sut.GetRedirectedPost("oldHandle")
+
+	assert.Equal(t, repository.Post{}, post, "should not return a post")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestPostRepository_GetReservedURLHandles tests retrieving the URL handles reserved by posts and redirects
+func TestPostRepository_GetReservedURLHandles(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	postQuery := regexp.QuoteMeta("SELECT `url_handle` FROM `posts` WHERE url_handle = ? OR url_handle LIKE ?")
+	redirectQuery := regexp.QuoteMeta("SELECT `url_handle` FROM `post_redirects` WHERE url_handle = ? OR url_handle LIKE ?")
+
+	c.mockDb.ExpectQuery(postQuery).
+		WithArgs("test_post", "test\\_post-%").
+		WillReturnRows(sqlmock.NewRows([]string{"url_handle"}).
+			AddRow("test_post").
+			AddRow("test_post-2"))
+	c.mockDb.ExpectQuery(redirectQuery).
+		WithArgs("test_post", "test\\_post-%").
+		WillReturnRows(sqlmock.NewRows([]string{"url_handle"}).
+			AddRow("test_post-3"))
+
+	handles, err := c.sut.GetReservedURLHandles("test_post")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, []string{"test_post", "test_post-2", "test_post-3"}, handles, "received URL handles should match the expected ones")
+}
+
+// TestPostRepository_GetReservedURLHandles_Unexpected_Error tests retrieving the reserved URL handles with an error
+func TestPostRepository_GetReservedURLHandles_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostRepositoryContext(t)
+
+	postQuery := regexp.QuoteMeta("SELECT `url_handle` FROM `posts` WHERE url_handle = ? OR url_handle LIKE ?")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(postQuery).WillReturnError(expectedError)
+
+	handles, err := c.sut.GetReservedURLHandles("testPost")
+
+	assert.Nil(t, handles, "should not return URL handles")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
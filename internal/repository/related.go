@@ -0,0 +1,104 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=related.go -destination=../mocks/mock_related_post_repository.go -package=mocks
+
+import (
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RelatedPost DB schema. Precomputed similarity of two posts, ordered per post by position, starting at 1.
+type RelatedPost struct {
+	PostID        uint    `gorm:"primaryKey;autoIncrement:false"`
+	Post          Post    `gorm:"constraint:OnDelete:CASCADE;"`
+	RelatedPostID uint    `gorm:"primaryKey;autoIncrement:false;index"`
+	RelatedPost   Post    `gorm:"constraint:OnDelete:CASCADE;"`
+	Position      int     `gorm:"not null"`
+	Score         float64 `gorm:"not null"`
+}
+
+// RelatedPostRepository interface defining related post-related database operations.
+type RelatedPostRepository interface {
+	ReplaceRelatedPosts(relatedPosts []RelatedPost) error
+	GetRelatedPosts(postID uint, limit int) ([]Post, error)
+}
+
+// relatedPostRepository is the concrete implementation of the RelatedPostRepository interface.
+type relatedPostRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// relatedPostBatchSize limits the number of rows inserted by a single statement
+const relatedPostBatchSize = 500
+
+// CreateRelatedPostRepository instantiates the relatedPostRepository
+func CreateRelatedPostRepository(logger *zap.SugaredLogger, repository Repository) RelatedPostRepository {
+	initRelatedPostModel(logger, repository)
+
+	return &relatedPostRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initRelatedPostModel initializes the RelatedPost schema in the database.
+func initRelatedPostModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&RelatedPost{}); err != nil {
+		logger.Errorf("failed to initialize related post model: %v", err)
+	}
+}
+
+// ReplaceRelatedPosts replaces every stored related post in a single transaction,
+// so readers never see a partially updated ranking.
+func (r relatedPostRepository) ReplaceRelatedPosts(relatedPosts []RelatedPost) error {
+	log := r.logger
+	repo := r.repository
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&RelatedPost{}); result.Error != nil {
+			return result.Error
+		}
+
+		if len(relatedPosts) == 0 {
+			return nil
+		}
+
+		return tx.Omit("Post", "RelatedPost").CreateInBatches(&relatedPosts, relatedPostBatchSize).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to replace related posts, error: %v", err)
+		return err
+	}
+
+	log.Debugf("replaced related posts: %d", len(relatedPosts))
+	return nil
+}
+
+// GetRelatedPosts retrieves the public posts related to the given post, most similar first.
+func (r relatedPostRepository) GetRelatedPosts(postID uint, limit int) ([]Post, error) {
+	log := r.logger
+	repo := r.repository
+
+	var posts []Post
+	result := repo.
+		Preload("Author").
+		Preload("Tags").
+		Preload("Category").
+		Joins("JOIN related_posts ON related_posts.related_post_id = posts.id").
+		Where("related_posts.post_id = ?", postID).
+		Where(publicPostCondition, publicPostArgs(time.Now())...).
+		Order("related_posts.position").
+		Limit(limit).
+		Find(&posts)
+
+	if result.Error != nil {
+		log.Debugf("error fetching posts related to post %d: %v", postID, result.Error)
+		return []Post{}, result.Error
+	}
+
+	log.Debugf("fetched posts related to post %d: %v", postID, posts)
+	return posts, nil
+}
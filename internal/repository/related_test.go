@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// relatedPostTestContext contains objects relevant for testing the RelatedPostRepository.
+type relatedPostTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.RelatedPostRepository
+}
+
+// createRelatedPostRepositoryContext creates the context for testing the RelatedPostRepository and reduces code duplication.
+func createRelatedPostRepositoryContext(t *testing.T) *relatedPostTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateRelatedPostRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &relatedPostTestContext{mock, sut}
+}
+
+// TestRelatedPostRepository_ReplaceRelatedPosts tests replacing every related post
+func TestRelatedPostRepository_ReplaceRelatedPosts(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostRepositoryContext(t)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `related_posts`")
+	insertQuery := regexp.QuoteMeta("INSERT INTO `related_posts` (`post_id`,`related_post_id`,`position`,`score`) VALUES (?,?,?,?),(?,?,?,?)")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(deleteQuery).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	c.mockDb.ExpectExec(insertQuery).
+		WithArgs(1, 2, 1, 0.5, 2, 1, 1, 0.5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.ReplaceRelatedPosts([]repository.RelatedPost{
+		{PostID: 1, RelatedPostID: 2, Position: 1, Score: 0.5},
+		{PostID: 2, RelatedPostID: 1, Position: 1, Score: 0.5},
+	})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "expectations were not met")
+}
+
+// TestRelatedPostRepository_ReplaceRelatedPosts_Unexpected_Error tests that a failing insert keeps the previous related posts
+func TestRelatedPostRepository_ReplaceRelatedPosts_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostRepositoryContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(regexp.QuoteMeta("DELETE FROM `related_posts`")).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	c.mockDb.ExpectExec(regexp.QuoteMeta("INSERT INTO `related_posts`")).
+		WillReturnError(expectedError)
+	c.mockDb.ExpectRollback()
+
+	err := c.sut.ReplaceRelatedPosts([]repository.RelatedPost{{PostID: 1, RelatedPostID: 2, Position: 1, Score: 0.5}})
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "expectations were not met")
+}
+
+// TestRelatedPostRepository_GetRelatedPosts tests retrieving the public posts related to a post
+func TestRelatedPostRepository_GetRelatedPosts(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostRepositoryContext(t)
+
+	condition := "related_posts.post_id = ? AND ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?))"
+	query := regexp.QuoteMeta("SELECT `posts`.`id`")
+	join := regexp.QuoteMeta("FROM `posts` JOIN related_posts ON related_posts.related_post_id = posts.id WHERE " + condition + " AND `posts`.`deleted_at` IS NULL ORDER BY related_posts.position LIMIT ?")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` IN (?,?)")
+
+	c.mockDb.ExpectQuery(query+".*"+join).
+		WithArgs(3, repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(5, "closest").
+			AddRow(4, "second"))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(5, 4).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+
+	posts, err := c.sut.GetRelatedPosts(3, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(posts), "didn't receive the expected number of posts")
+	assert.Equal(t, "closest", posts[0].URLHandle, "posts should keep their order")
+}
+
+// TestRelatedPostRepository_GetRelatedPosts_Unexpected_Error tests retrieving related posts with an error
+func TestRelatedPostRepository_GetRelatedPosts_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostRepositoryContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(regexp.QuoteMeta("SELECT")).WillReturnError(expectedError)
+
+	posts, err := c.sut.GetRelatedPosts(3, 2)
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Equal(t, 0, len(posts), "shouldn't receive any posts")
+}
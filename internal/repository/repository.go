@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"database/sql"
 	"gorm.io/gorm"
 )
 
@@ -13,10 +14,17 @@ type Repository interface {
 	Delete(value interface{}) *gorm.DB
 	Where(query interface{}, args ...interface{}) *gorm.DB
 	Preload(column string, conditions ...interface{}) *gorm.DB
+	Omit(columns ...string) *gorm.DB
+	Order(value interface{}) *gorm.DB
+	Joins(query string, args ...interface{}) *gorm.DB
+	Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error
+	Exec(sql string, values ...interface{}) *gorm.DB
+	Unscoped() *gorm.DB
 	Close() error
 	AutoMigrate(value interface{}) error
 	Count(count *int64) *gorm.DB
 	Model(value interface{}) *gorm.DB
+	Migrator() gorm.Migrator
 }
 
 // repository implements the Repository interface and stores the concrete Gorm DB implementation
@@ -65,6 +73,37 @@ func (rep *repository) Preload(column string, conditions ...interface{}) *gorm.D
 	return rep.db.Preload(column, conditions...)
 }
 
+// Omit specify fields to be ignored when writing to the database
+func (rep *repository) Omit(columns ...string) *gorm.DB {
+	return rep.db.Omit(columns...)
+}
+
+// Order specifies the order of the retrieved rows
+func (rep *repository) Order(value interface{}) *gorm.DB {
+	return rep.db.Order(value)
+}
+
+// Joins specifies tables to be joined to the query
+func (rep *repository) Joins(query string, args ...interface{}) *gorm.DB {
+	return rep.db.Joins(query, args...)
+}
+
+// Transaction runs the given function in a database transaction.
+// The transaction is committed if the function returns nil and rolled back otherwise.
+func (rep *repository) Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	return rep.db.Transaction(fc, opts...)
+}
+
+// Exec executes a raw SQL statement
+func (rep *repository) Exec(sql string, values ...interface{}) *gorm.DB {
+	return rep.db.Exec(sql, values...)
+}
+
+// Unscoped includes soft deleted rows in the query
+func (rep *repository) Unscoped() *gorm.DB {
+	return rep.db.Unscoped()
+}
+
 // Close closes the database connection
 func (rep *repository) Close() error {
 	sqlDB, _ := rep.db.DB()
@@ -83,3 +122,8 @@ func (rep *repository) Count(count *int64) *gorm.DB {
 func (rep *repository) Model(value interface{}) *gorm.DB {
 	return rep.db.Model(value)
 }
+
+// Migrator returns the schema migrator of the database
+func (rep *repository) Migrator() gorm.Migrator {
+	return rep.db.Migrator()
+}
@@ -0,0 +1,202 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=revision.go -destination=../mocks/mock_revision_repository.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/errortypes"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// Revision DB schema. Immutable snapshot of the content of a post, saved whenever the post is created or updated.
+// Revisions are numbered per post, starting at 1.
+// The body rendered to HTML is cached along with the key of the renderer configuration that produced it.
+// Revisions saved before the body format was recorded have an empty body format.
+type Revision struct {
+	ID         uint  `gorm:"primaryKey;autoIncrement"`
+	PostID     uint  `gorm:"uniqueIndex:idx_revision_post_number;not null"`
+	Post       Post  `gorm:"constraint:OnDelete:CASCADE;"`
+	Number     int   `gorm:"uniqueIndex:idx_revision_post_number;not null"`
+	EditorID   *uint `gorm:"index"`
+	Editor     *User `gorm:"constraint:OnDelete:SET NULL;"`
+	Title      *string
+	Summary    *string
+	Body       *string
+	BodyFormat BodyFormat `gorm:"type:varchar(16)"`
+	BodyHTML   *string    `gorm:"type:longtext"`
+	RenderKey  string     `gorm:"type:varchar(64)"`
+	CreatedAt  time.Time
+}
+
+// RevisionRepository interface defining revision-related database operations.
+type RevisionRepository interface {
+	GetRevision(postID uint, number int) (Revision, error)
+	GetRevisions(postID uint) ([]Revision, error)
+	GetLatestRevision(postID uint) (Revision, error)
+	CacheRendering(id uint, bodyHTML string, renderKey string) error
+}
+
+// revisionRepository is the concrete implementation of the RevisionRepository interface.
+type revisionRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateRevisionRepository instantiates the revisionRepository
+func CreateRevisionRepository(logger *zap.SugaredLogger, repository Repository) RevisionRepository {
+	initRevisionModel(logger, repository)
+
+	return &revisionRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initRevisionModel initializes the Revision schema in the database.
+// Posts created before the revision history existed get their current content as first revision.
+func initRevisionModel(logger *zap.SugaredLogger, repository Repository) {
+	hadTable := repository.Migrator().HasTable(&Revision{})
+
+	if err := repository.AutoMigrate(&Revision{}); err != nil {
+		logger.Errorf("failed to initialize revision model: %v", err)
+		return
+	}
+
+	if hadTable {
+		return
+	}
+
+	result := repository.Exec(
+		"INSERT INTO revisions (post_id, number, editor_id, title, summary, body, created_at) " +
+			"SELECT id, 1, author_id, title, summary, body, updated_at FROM posts",
+	)
+
+	if result.Error != nil {
+		logger.Errorf("failed to create initial revisions of existing posts: %v", result.Error)
+	}
+}
+
+// GetRevision retrieves the revision of the post with the given ID and revision number.
+func (r revisionRepository) GetRevision(postID uint, number int) (Revision, error) {
+	log := r.logger
+	repo := r.repository
+
+	revision := Revision{
+		PostID: postID,
+		Number: number,
+	}
+
+	result := repo.Preload("Editor").Where(&revision).Take(&revision)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve revision %d of post %d, error: %v", number, postID, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Revision{}, errortypes.RevisionNotFoundError{Number: number}
+		}
+		return Revision{}, result.Error
+	}
+
+	log.Debugf("retrieved revision: %v", revision)
+	return revision, nil
+}
+
+// GetRevisions retrieves every revision of the post with the given ID, most recent first.
+func (r revisionRepository) GetRevisions(postID uint) ([]Revision, error) {
+	log := r.logger
+	repo := r.repository
+
+	var revisions []Revision
+	result := repo.
+		Preload("Editor").
+		Where("post_id = ?", postID).
+		Order("number DESC").
+		Find(&revisions)
+
+	if result.Error != nil {
+		log.Debugf("error fetching revisions of post %d: %v", postID, result.Error)
+		return []Revision{}, result.Error
+	}
+
+	log.Debugf("fetched revisions of post %d: %v", postID, revisions)
+	return revisions, nil
+}
+
+// GetLatestRevision retrieves the most recent revision of the post with the given ID.
+func (r revisionRepository) GetLatestRevision(postID uint) (Revision, error) {
+	log := r.logger
+	repo := r.repository
+
+	var revision Revision
+	result := repo.
+		Where("post_id = ?", postID).
+		Order("number DESC").
+		Take(&revision)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve latest revision of post %d, error: %v", postID, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Revision{}, errortypes.RevisionNotFoundError{}
+		}
+		return Revision{}, result.Error
+	}
+
+	log.Debugf("retrieved latest revision %d of post %d", revision.Number, postID)
+	return revision, nil
+}
+
+// CacheRendering stores the rendered body of the revision with the given ID.
+// Only the cached rendering is changed, the content of the revision stays untouched.
+func (r revisionRepository) CacheRendering(id uint, bodyHTML string, renderKey string) error {
+	log := r.logger
+	repo := r.repository
+
+	result := repo.
+		Model(&Revision{ID: id}).
+		UpdateColumns(map[string]interface{}{
+			"body_html":  bodyHTML,
+			"render_key": renderKey,
+		})
+
+	if result.Error != nil {
+		log.Debugf("failed to cache rendering of revision %d, error: %v", id, result.Error)
+		return result.Error
+	}
+
+	log.Debugf("cached rendering of revision %d", id)
+	return nil
+}
+
+// addRevision saves the current content of the post with the given URL handle as its newest revision.
+// It must run in the transaction that writes the post. The post row stays locked until the transaction ends, so
+// concurrent saves of the same post get consecutive revision numbers.
+func addRevision(tx *gorm.DB, urlHandle string, editorID uint) error {
+	post := Post{URLHandle: urlHandle}
+	if result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&post).Take(&post); result.Error != nil {
+		return result.Error
+	}
+
+	var last int
+	result := tx.
+		Model(&Revision{}).
+		Select("COALESCE(MAX(number), 0)").
+		Where("post_id = ?", post.ID).
+		Scan(&last)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	revision := Revision{
+		PostID:     post.ID,
+		Number:     last + 1,
+		EditorID:   &editorID,
+		Title:      post.Title,
+		Summary:    post.Summary,
+		Body:       post.Body,
+		BodyFormat: post.BodyFormat,
+	}
+
+	return tx.Omit("Post", "Editor").Create(&revision).Error
+}
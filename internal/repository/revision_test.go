@@ -0,0 +1,143 @@
+package repository_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// revisionTestContext contains objects relevant for testing the RevisionRepository.
+type revisionTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.RevisionRepository
+}
+
+// createRevisionRepositoryContext creates the context for testing the RevisionRepository and reduces code duplication.
+func createRevisionRepositoryContext(t *testing.T) *revisionTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateRevisionRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &revisionTestContext{mock, sut}
+}
+
+// TestRevisionRepository_GetRevision tests retrieving a single revision of a post
+func TestRevisionRepository_GetRevision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `revisions` WHERE `revisions`.`post_id` = ? AND `revisions`.`number` = ? LIMIT ?")
+	editorQuery := regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`id` = ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(4, 2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "number", "editor_id", "title"}).
+			AddRow(7, 4, 2, 2, "testTitle"))
+	c.mockDb.ExpectQuery(editorQuery).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_name"}).AddRow(2, "testEditor"))
+
+	revision, err := c.sut.GetRevision(4, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, revision.Number, "incorrect revision number")
+	assert.Equal(t, "testTitle", *revision.Title, "incorrect revision title")
+	assert.Equal(t, "testEditor", revision.Editor.UserName, "incorrect editor")
+}
+
+// TestRevisionRepository_GetRevision_Not_Found tests retrieving a non-existing revision
+func TestRevisionRepository_GetRevision_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createRevisionRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `revisions` WHERE `revisions`.`post_id` = ? AND `revisions`.`number` = ? LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(4, 9, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := c.sut.GetRevision(4, 9)
+
+	assert.Equal(t, errortypes.RevisionNotFoundError{Number: 9}, err, "error doesn't match expected one")
+}
+
+// TestRevisionRepository_GetRevisions tests listing the revisions of a post, most recent first
+func TestRevisionRepository_GetRevisions(t *testing.T) {
+	t.Parallel()
+	c := createRevisionRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `revisions` WHERE post_id = ? ORDER BY number DESC")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "number"}).
+			AddRow(8, 4, 2).
+			AddRow(7, 4, 1))
+
+	revisions, err := c.sut.GetRevisions(4)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(revisions), "incorrect revision count")
+	assert.Equal(t, 2, revisions[0].Number, "revisions should be ordered by number")
+}
+
+// TestRevisionRepository_GetLatestRevision tests retrieving the most recent revision of a post
+func TestRevisionRepository_GetLatestRevision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `revisions` WHERE post_id = ? ORDER BY number DESC LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "post_id", "number"}).
+			AddRow(9, 4, 3))
+
+	revision, err := c.sut.GetLatestRevision(4)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 3, revision.Number, "incorrect revision number")
+}
+
+// TestRevisionRepository_GetLatestRevision_Record_Not_Found tests retrieving the latest revision of a post without revisions
+func TestRevisionRepository_GetLatestRevision_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createRevisionRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `revisions` WHERE post_id = ? ORDER BY number DESC LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := c.sut.GetLatestRevision(4)
+
+	assert.Equal(t, errortypes.RevisionNotFoundError{}, err, "received error should match the expected one")
+}
+
+// TestRevisionRepository_CacheRendering tests storing the rendered body of a revision
+func TestRevisionRepository_CacheRendering(t *testing.T) {
+	t.Parallel()
+	c := createRevisionRepositoryContext(t)
+
+	query := regexp.QuoteMeta("UPDATE `revisions` SET `body_html`=?,`render_key`=? WHERE `id` = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).
+		WithArgs("<p>body</p>", "testKey", 9).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.CacheRendering(9, "<p>body</p>", "testKey")
+
+	assert.Nil(t, err, "should complete without error")
+}
@@ -0,0 +1,146 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=search.go -destination=../mocks/mock_search_repository.go -package=mocks
+
+import (
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// SearchDocument DB schema. Searchable plain text content of a post, kept in sync with the post by the services.
+// The score is only set in search results.
+type SearchDocument struct {
+	PostID    uint    `gorm:"primaryKey;autoIncrement:false"`
+	Post      Post    `gorm:"constraint:OnDelete:CASCADE;"`
+	Title     string  `gorm:"type:text;index:idx_search_document_content,class:FULLTEXT"`
+	Summary   string  `gorm:"type:text;index:idx_search_document_content,class:FULLTEXT"`
+	Body      string  `gorm:"type:longtext;index:idx_search_document_content,class:FULLTEXT"`
+	Score     float64 `gorm:"->;-:migration"`
+	UpdatedAt time.Time
+}
+
+// SearchRepository interface defining search-related operations.
+// Implementations only return posts that are currently visible to anonymous readers, most relevant first.
+type SearchRepository interface {
+	IndexPost(document SearchDocument) error
+	RemovePost(postID uint) error
+	Search(query string, pageIndex int, pageSize int) ([]SearchDocument, int, error)
+}
+
+// searchRepository is the MySQL FULLTEXT implementation of the SearchRepository interface.
+type searchRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// matchCondition ranks the search documents by their relevance to the query in natural language mode
+const matchCondition = "MATCH(search_documents.title, search_documents.summary, search_documents.body) AGAINST(? IN NATURAL LANGUAGE MODE)"
+
+// CreateSearchRepository instantiates the searchRepository
+func CreateSearchRepository(logger *zap.SugaredLogger, repository Repository) SearchRepository {
+	initSearchModel(logger, repository)
+
+	return &searchRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initSearchModel initializes the SearchDocument schema in the database.
+// Posts created before the search index existed are indexed with their raw content until they are saved again.
+func initSearchModel(logger *zap.SugaredLogger, repository Repository) {
+	hadTable := repository.Migrator().HasTable(&SearchDocument{})
+
+	if err := repository.AutoMigrate(&SearchDocument{}); err != nil {
+		logger.Errorf("failed to initialize search model: %v", err)
+		return
+	}
+
+	if hadTable {
+		return
+	}
+
+	result := repository.Exec(
+		"INSERT INTO search_documents (post_id, title, summary, body, updated_at) " +
+			"SELECT id, COALESCE(title, ''), COALESCE(summary, ''), COALESCE(body, ''), updated_at FROM posts " +
+			"WHERE deleted_at IS NULL",
+	)
+
+	if result.Error != nil {
+		logger.Errorf("failed to index existing posts: %v", result.Error)
+	}
+}
+
+// IndexPost adds the search document of a post to the index or replaces the existing one.
+func (s searchRepository) IndexPost(document SearchDocument) error {
+	log := s.logger
+	repo := s.repository
+
+	result := repo.
+		Model(&SearchDocument{}).
+		Omit("Post").
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&document)
+
+	if result.Error != nil {
+		log.Debugf("failed to index post %d, error: %v", document.PostID, result.Error)
+		return result.Error
+	}
+
+	log.Debugf("indexed post: %d", document.PostID)
+	return nil
+}
+
+// RemovePost removes the search document of a post from the index. Removing a post that isn't indexed is not an error.
+func (s searchRepository) RemovePost(postID uint) error {
+	log := s.logger
+	repo := s.repository
+
+	if result := repo.Where("post_id = ?", postID).Delete(&SearchDocument{}); result.Error != nil {
+		log.Debugf("failed to remove post %d from the search index, error: %v", postID, result.Error)
+		return result.Error
+	}
+
+	log.Debugf("removed post from the search index: %d", postID)
+	return nil
+}
+
+// Search retrieves a specific page of search documents matching the query, along with their posts.
+// The second return parameter holds the overall item count.
+func (s searchRepository) Search(query string, pageIndex int, pageSize int) ([]SearchDocument, int, error) {
+	log := s.logger
+	repo := s.repository
+
+	now := time.Now()
+
+	var documents []SearchDocument
+	result := repo.
+		Select("search_documents.*, "+matchCondition+" AS score", query).
+		Preload("Post.Author").
+		Preload("Post.Tags").
+		Preload("Post.Category").
+		Joins("JOIN posts ON posts.id = search_documents.post_id AND posts.deleted_at IS NULL").
+		Where(publicPostCondition, publicPostArgs(now)...).
+		Where(matchCondition, query).
+		Order("score DESC").
+		Limit(pageSize).
+		Offset((pageIndex - 1) * pageSize).
+		Find(&documents)
+
+	if result.Error != nil {
+		log.Debugf("error searching posts for %s: %v", query, result.Error)
+		return []SearchDocument{}, -1, result.Error
+	}
+
+	var count int64
+	repo.
+		Model(&SearchDocument{}).
+		Joins("JOIN posts ON posts.id = search_documents.post_id AND posts.deleted_at IS NULL").
+		Where(publicPostCondition, publicPostArgs(now)...).
+		Where(matchCondition, query).
+		Count(&count)
+
+	log.Debugf("found %d posts for %s", count, query)
+	return documents, int(count), nil
+}
@@ -0,0 +1,124 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// searchTestContext contains objects relevant for testing the SearchRepository.
+type searchTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.SearchRepository
+}
+
+// createSearchRepositoryContext creates the context for testing the SearchRepository and reduces code duplication.
+func createSearchRepositoryContext(t *testing.T) *searchTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateSearchRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &searchTestContext{mock, sut}
+}
+
+// TestSearchRepository_IndexPost tests adding or replacing the search document of a post
+func TestSearchRepository_IndexPost(t *testing.T) {
+	t.Parallel()
+	c := createSearchRepositoryContext(t)
+
+	query := regexp.QuoteMeta("INSERT INTO `search_documents` (`post_id`,`title`,`summary`,`body`,`updated_at`) VALUES (?,?,?,?,?) ON DUPLICATE KEY UPDATE `updated_at`=?,`title`=VALUES(`title`),`summary`=VALUES(`summary`),`body`=VALUES(`body`)")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).
+		WithArgs(3, "testTitle", "testSummary", "testBody", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.IndexPost(repository.SearchDocument{PostID: 3, Title: "testTitle", Summary: "testSummary", Body: "testBody"})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "expectations were not met")
+}
+
+// TestSearchRepository_RemovePost tests removing the search document of a post
+func TestSearchRepository_RemovePost(t *testing.T) {
+	t.Parallel()
+	c := createSearchRepositoryContext(t)
+
+	query := regexp.QuoteMeta("DELETE FROM `search_documents` WHERE post_id = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).
+		WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.RemovePost(3)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "expectations were not met")
+}
+
+// TestSearchRepository_Search tests searching the published posts ranked by relevance
+func TestSearchRepository_Search(t *testing.T) {
+	t.Parallel()
+	c := createSearchRepositoryContext(t)
+
+	match := "MATCH(search_documents.title, search_documents.summary, search_documents.body) AGAINST(? IN NATURAL LANGUAGE MODE)"
+	condition := "((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND " + match
+	query := regexp.QuoteMeta("SELECT search_documents.*, " + match + " AS score FROM `search_documents` JOIN posts ON posts.id = search_documents.post_id AND posts.deleted_at IS NULL WHERE " + condition + " ORDER BY score DESC LIMIT ? OFFSET ?")
+	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`id` IN (?,?) AND `posts`.`deleted_at` IS NULL")
+	tagQuery := regexp.QuoteMeta("SELECT * FROM `post_tags` WHERE `post_tags`.`post_id` IN (?,?)")
+	countQuery := regexp.QuoteMeta("SELECT count(*) FROM `search_documents` JOIN posts ON posts.id = search_documents.post_id AND posts.deleted_at IS NULL WHERE " + condition)
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs("go", repository.PostStatusPublished, repository.PostStatusDraft, sqlmock.AnyArg(), sqlmock.AnyArg(), "go", 5, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "title", "body", "score"}).
+			AddRow(2, "Go", "body", 2.5).
+			AddRow(1, "Golang", "body", 1.5))
+	c.mockDb.ExpectQuery(postQuery).
+		WithArgs(2, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(1, "golang").
+			AddRow(2, "go"))
+	c.mockDb.ExpectQuery(tagQuery).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"post_id", "tag_id"}))
+	c.mockDb.ExpectQuery(countQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	documents, count, err := c.sut.Search("go", 2, 5)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 7, count, "incorrect item count")
+	assert.Equal(t, 2, len(documents), "didn't receive the expected number of documents")
+	assert.Equal(t, "go", documents[0].Post.URLHandle, "documents should keep their order")
+	assert.Equal(t, 2.5, documents[0].Score, "incorrect score")
+}
+
+// TestSearchRepository_Search_Unexpected_Error tests searching posts with an error
+func TestSearchRepository_Search_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createSearchRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT search_documents.*")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	documents, _, err := c.sut.Search("go", 1, 5)
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Equal(t, 0, len(documents), "shouldn't receive any documents")
+}
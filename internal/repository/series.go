@@ -0,0 +1,245 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=series.go -destination=../mocks/mock_series_repository.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/errortypes"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// Series DB schema
+type Series struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement"`
+	URLHandle   string `gorm:"type:varchar(128);unique;not null"`
+	Title       string `gorm:"not null"`
+	Description *string
+	Entries     []SeriesEntry `gorm:"constraint:OnDelete:CASCADE;"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SeriesEntry DB schema. Places a post at the given position of a series.
+// A post belongs to at most one series.
+type SeriesEntry struct {
+	ID       uint `gorm:"primaryKey;autoIncrement"`
+	SeriesID uint `gorm:"index;not null"`
+	PostID   uint `gorm:"unique;not null"`
+	Post     Post `gorm:"constraint:OnDelete:CASCADE;"`
+	Position int  `gorm:"not null"`
+}
+
+// SeriesNavigation describes where a post is located within its series.
+// It isn't stored in the database, but computed when a single post is retrieved.
+type SeriesNavigation struct {
+	Series   Series
+	Position int
+	Total    int
+	Previous *Post
+	Next     *Post
+}
+
+// SeriesRepository interface defining series-related database operations.
+type SeriesRepository interface {
+	AddSeries(series Series) (Series, error)
+	UpdateSeries(series Series) (Series, error)
+	DeleteSeries(urlHandle string) error
+	GetSeries(urlHandle string) (Series, error)
+	GetAllSeries() ([]Series, error)
+	GetSeriesOfPost(postID uint) (Series, error)
+}
+
+// seriesRepository is the concrete implementation of the SeriesRepository interface.
+type seriesRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateSeriesRepository instantiates the seriesRepository
+func CreateSeriesRepository(logger *zap.SugaredLogger, repository Repository) SeriesRepository {
+	initSeriesModel(logger, repository)
+
+	return &seriesRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initSeriesModel initializes the Series and SeriesEntry schemas in the database
+func initSeriesModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&Series{}); err != nil {
+		logger.Errorf("failed to initialize series model: %v", err)
+	}
+
+	if err := repository.AutoMigrate(&SeriesEntry{}); err != nil {
+		logger.Errorf("failed to initialize series entry model: %v", err)
+	}
+}
+
+// orderedEntries loads the entries of a series ordered by their position
+func orderedEntries(db *gorm.DB) *gorm.DB {
+	return db.Order("series_entries.position ASC")
+}
+
+// AddSeries adds a new series with its entries to the database.
+func (s seriesRepository) AddSeries(series Series) (Series, error) {
+	log := s.logger
+	repo := s.repository
+
+	if result := repo.Omit("Entries.Post").Create(&series); result.Error == nil {
+		log.Debugf("created series: %v", series)
+		return s.GetSeries(series.URLHandle)
+	} else if strings.Contains(result.Error.Error(), "1062") {
+		log.Debugf("failed to create series, duplicate key: %s, error: %v", series.URLHandle, result.Error)
+		return Series{}, errortypes.DuplicateElementError{Key: series.URLHandle}
+	} else {
+		log.Debugf("failed to create series: %v, error: %s", series, result.Error)
+		return Series{}, result.Error
+	}
+}
+
+// UpdateSeries updates the title and the description of an existing series.
+// If the entries of the updated series are not nil, they replace the current entries of the series.
+func (s seriesRepository) UpdateSeries(updatedSeries Series) (Series, error) {
+	log := s.logger
+	repo := s.repository
+
+	series := Series{
+		URLHandle: updatedSeries.URLHandle,
+	}
+
+	if result := repo.Where(&series).Take(&series); result.Error != nil {
+		log.Debugf("failed to retrieve series %s for update, error: %v", series.URLHandle, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Series{}, errortypes.SeriesNotFoundError{URLHandle: series.URLHandle}
+		}
+		return Series{}, result.Error
+	}
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Model(&series).Omit("Entries").Updates(updatedSeries); result.Error != nil {
+			return result.Error
+		}
+
+		if updatedSeries.Entries == nil {
+			return nil
+		}
+
+		if result := tx.Where("series_id = ?", series.ID).Delete(&SeriesEntry{}); result.Error != nil {
+			return result.Error
+		}
+
+		if len(updatedSeries.Entries) == 0 {
+			return nil
+		}
+
+		entries := make([]SeriesEntry, 0, len(updatedSeries.Entries))
+		for _, entry := range updatedSeries.Entries {
+			entries = append(entries, SeriesEntry{SeriesID: series.ID, PostID: entry.PostID, Position: entry.Position})
+		}
+
+		return tx.Omit("Post").Create(&entries).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to update series: %v, error: %v", updatedSeries, err)
+		return Series{}, err
+	}
+
+	log.Debugf("updated series: %v", updatedSeries)
+	return s.GetSeries(series.URLHandle)
+}
+
+// DeleteSeries deletes the series with the given URL handle from the database. The posts of the series are kept.
+func (s seriesRepository) DeleteSeries(urlHandle string) error {
+	log := s.logger
+	repo := s.repository
+
+	series := Series{
+		URLHandle: urlHandle,
+	}
+
+	if result := repo.Where(series).Delete(series); result.Error == nil {
+		if result.RowsAffected > 0 {
+			log.Debugf("deleted series: %s", urlHandle)
+			return nil
+		} else {
+			return errortypes.SeriesNotFoundError{URLHandle: urlHandle}
+		}
+	} else {
+		log.Debugf("failed to delete series: %v, error: %s", series, result.Error)
+		return result.Error
+	}
+}
+
+// GetSeries retrieves the series with the given URL handle together with its ordered entries.
+func (s seriesRepository) GetSeries(urlHandle string) (Series, error) {
+	log := s.logger
+	repo := s.repository
+
+	series := Series{
+		URLHandle: urlHandle,
+	}
+
+	result := repo.
+		Preload("Entries", orderedEntries).
+		Preload("Entries.Post").
+		Preload("Entries.Post.Author").
+		Where(&series).
+		Take(&series)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve series with handle: %s, error: %v", urlHandle, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Series{}, errortypes.SeriesNotFoundError{URLHandle: urlHandle}
+		}
+		return Series{}, result.Error
+	}
+
+	log.Debugf("retrieved series: %v", series)
+	return series, nil
+}
+
+// GetAllSeries retrieves every series without its entries, most recent first.
+func (s seriesRepository) GetAllSeries() ([]Series, error) {
+	log := s.logger
+	repo := s.repository
+
+	var series []Series
+	result := repo.Order("created_at DESC").Find(&series)
+
+	if result.Error != nil {
+		log.Debugf("error fetching series: %v", result.Error)
+		return []Series{}, result.Error
+	}
+
+	log.Debugf("fetched series: %v", series)
+	return series, nil
+}
+
+// GetSeriesOfPost retrieves the series the post with the given ID belongs to together with its ordered entries.
+func (s seriesRepository) GetSeriesOfPost(postID uint) (Series, error) {
+	log := s.logger
+	repo := s.repository
+
+	var series Series
+	result := repo.
+		Preload("Entries", orderedEntries).
+		Preload("Entries.Post").
+		Joins("JOIN series_entries ON series_entries.series_id = series.id").
+		Where("series_entries.post_id = ?", postID).
+		Take(&series)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve series of post %d, error: %v", postID, result.Error)
+		if result.Error.Error() == "record not found" {
+			return Series{}, errortypes.SeriesNotFoundError{}
+		}
+		return Series{}, result.Error
+	}
+
+	log.Debugf("retrieved series of post %d: %v", postID, series)
+	return series, nil
+}
@@ -0,0 +1,231 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// seriesTestContext contains objects relevant for testing the SeriesRepository.
+type seriesTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.SeriesRepository
+}
+
+// createSeriesRepositoryContext creates the context for testing the SeriesRepository and reduces code duplication.
+func createSeriesRepositoryContext(t *testing.T) *seriesTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateSeriesRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &seriesTestContext{mock, sut}
+}
+
+// TestSeriesRepository_AddSeries tests adding a new series with its entries to the system
+func TestSeriesRepository_AddSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	inputSeries := repository.Series{
+		URLHandle: "go-tutorial",
+		Title:     "Go Tutorial",
+		Entries:   []repository.SeriesEntry{{PostID: 4, Position: 1}},
+	}
+
+	insertQuery := regexp.QuoteMeta("INSERT INTO `series` (`url_handle`,`title`,`description`,`created_at`,`updated_at`) VALUES (?,?,?,?,?)")
+	entryQuery := regexp.QuoteMeta("INSERT INTO `series_entries` (`series_id`,`post_id`,`position`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `series_id`=VALUES(`series_id`)")
+	query := regexp.QuoteMeta("SELECT * FROM `series` WHERE `series`.`url_handle` = ? LIMIT ?")
+	entriesQuery := regexp.QuoteMeta("SELECT * FROM `series_entries` WHERE `series_entries`.`series_id` = ? ORDER BY series_entries.position ASC")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(2, 1))
+	c.mockDb.ExpectExec(entryQuery).WithArgs(2, 4, 1).WillReturnResult(sqlmock.NewResult(1, 1))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WithArgs(inputSeries.URLHandle, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "title"}).
+			AddRow(2, inputSeries.URLHandle, inputSeries.Title))
+	c.mockDb.ExpectQuery(entriesQuery).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "post_id", "position"}))
+
+	series, err := c.sut.AddSeries(inputSeries)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, inputSeries.URLHandle, series.URLHandle, "incorrect series handle")
+	assert.Equal(t, 0, len(series.Entries), "series shouldn't have entries")
+}
+
+// TestSeriesRepository_AddSeries_Duplicate_Series tests adding a series with an existing URL handle
+func TestSeriesRepository_AddSeries_Duplicate_Series(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	insertQuery := regexp.QuoteMeta("INSERT INTO `series`")
+	expectedError := errortypes.DuplicateElementError{Key: "go-tutorial"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(insertQuery).WillReturnError(fmt.Errorf("Error 1062 (23000): Duplicate entry"))
+	c.mockDb.ExpectRollback()
+
+	_, err := c.sut.AddSeries(repository.Series{URLHandle: "go-tutorial", Title: "Go Tutorial"})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestSeriesRepository_UpdateSeries tests replacing the entries of a series
+func TestSeriesRepository_UpdateSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	updatedSeries := repository.Series{
+		URLHandle: "go-tutorial",
+		Entries:   []repository.SeriesEntry{{PostID: 5, Position: 1}, {PostID: 4, Position: 2}},
+	}
+
+	query := regexp.QuoteMeta("SELECT * FROM `series` WHERE `series`.`url_handle` = ? LIMIT ?")
+	updateQuery := regexp.QuoteMeta("UPDATE `series` SET `url_handle`=?,`updated_at`=? WHERE `id` = ?")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `series_entries` WHERE series_id = ?")
+	insertQuery := regexp.QuoteMeta("INSERT INTO `series_entries` (`series_id`,`post_id`,`position`) VALUES (?,?,?),(?,?,?)")
+	entriesQuery := regexp.QuoteMeta("SELECT * FROM `series_entries` WHERE `series_entries`.`series_id` = ? ORDER BY series_entries.position ASC")
+
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).AddRow(2, "go-tutorial"))
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(deleteQuery).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(insertQuery).WithArgs(2, 5, 1, 2, 4, 2).WillReturnResult(sqlmock.NewResult(1, 2))
+	c.mockDb.ExpectCommit()
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).AddRow(2, "go-tutorial"))
+	c.mockDb.ExpectQuery(entriesQuery).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "post_id", "position"}))
+
+	_, err := c.sut.UpdateSeries(updatedSeries)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "every query should be executed")
+}
+
+// TestSeriesRepository_UpdateSeries_Record_Not_Found tests updating a non-existing series
+func TestSeriesRepository_UpdateSeries_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `series` WHERE `series`.`url_handle` = ? LIMIT ?")
+	expectedError := errortypes.SeriesNotFoundError{URLHandle: "go-tutorial"}
+
+	c.mockDb.ExpectQuery(query).WillReturnError(fmt.Errorf("record not found"))
+
+	_, err := c.sut.UpdateSeries(repository.Series{URLHandle: "go-tutorial"})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestSeriesRepository_DeleteSeries tests deleting a series
+func TestSeriesRepository_DeleteSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `series` WHERE `series`.`url_handle` = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(deleteQuery).WithArgs("go-tutorial").WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DeleteSeries("go-tutorial")
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestSeriesRepository_DeleteSeries_Record_Not_Found tests deleting a non-existing series
+func TestSeriesRepository_DeleteSeries_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `series` WHERE `series`.`url_handle` = ?")
+	expectedError := errortypes.SeriesNotFoundError{URLHandle: "go-tutorial"}
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(deleteQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DeleteSeries("go-tutorial")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestSeriesRepository_GetAllSeries tests retrieving every series
+func TestSeriesRepository_GetAllSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `series` ORDER BY created_at DESC")
+
+	c.mockDb.ExpectQuery(query).
+		WillReturnRows(sqlmock.NewRows([]string{"url_handle", "title"}).
+			AddRow("go-tutorial", "Go Tutorial"))
+
+	series, err := c.sut.GetAllSeries()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, []repository.Series{{URLHandle: "go-tutorial", Title: "Go Tutorial"}}, series, "series don't match the expected ones")
+}
+
+// TestSeriesRepository_GetSeriesOfPost tests retrieving the series a post belongs to
+func TestSeriesRepository_GetSeriesOfPost(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT `series`.`id`,`series`.`url_handle`,`series`.`title`,`series`.`description`,`series`.`created_at`,`series`.`updated_at` FROM `series` JOIN series_entries ON series_entries.series_id = series.id WHERE series_entries.post_id = ? LIMIT ?")
+	entriesQuery := regexp.QuoteMeta("SELECT * FROM `series_entries` WHERE `series_entries`.`series_id` = ? ORDER BY series_entries.position ASC")
+	postsQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`id` IN (?,?)")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs(4, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle", "title"}).
+			AddRow(2, "go-tutorial", "Go Tutorial"))
+	c.mockDb.ExpectQuery(entriesQuery).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "series_id", "post_id", "position"}).
+			AddRow(1, 2, 5, 1).
+			AddRow(2, 2, 4, 2))
+	c.mockDb.ExpectQuery(postsQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url_handle"}).
+			AddRow(4, "part-2").
+			AddRow(5, "part-1"))
+
+	series, err := c.sut.GetSeriesOfPost(4)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 2, len(series.Entries), "series should have two entries")
+	assert.Equal(t, "part-1", series.Entries[0].Post.URLHandle, "entries should be ordered by position")
+	assert.Equal(t, "part-2", series.Entries[1].Post.URLHandle, "entries should be ordered by position")
+}
+
+// TestSeriesRepository_GetSeriesOfPost_Record_Not_Found tests retrieving the series of a post without series
+func TestSeriesRepository_GetSeriesOfPost_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createSeriesRepositoryContext(t)
+
+	query := regexp.QuoteMeta("FROM `series` JOIN series_entries ON series_entries.series_id = series.id")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(fmt.Errorf("record not found"))
+
+	_, err := c.sut.GetSeriesOfPost(4)
+
+	assert.Equal(t, errortypes.SeriesNotFoundError{}, err, "error doesn't match expected one")
+}
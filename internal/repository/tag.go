@@ -0,0 +1,78 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=tag.go -destination=../mocks/mock_tag_repository.go -package=mocks
+
+import (
+	"go.uber.org/zap"
+	"time"
+)
+
+// Tag DB schema
+type Tag struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	Name      string `gorm:"type:varchar(64);unique;not null"`
+	Posts     []Post `gorm:"many2many:post_tags;"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TagCount holds the name of a tag and the number of public posts it is attached to
+type TagCount struct {
+	Name      string
+	PostCount int
+}
+
+// TagRepository interface defining tag-related database operations.
+type TagRepository interface {
+	GetTags() ([]TagCount, error)
+}
+
+// tagRepository is the concrete implementation of the TagRepository interface.
+type tagRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateTagRepository instantiates the tagRepository
+func CreateTagRepository(logger *zap.SugaredLogger, repository Repository) TagRepository {
+	initTagModel(logger, repository)
+
+	return &tagRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initTagModel initializes the Tag schema in the database
+func initTagModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&Tag{}); err != nil {
+		logger.Errorf("failed to initialize tag model: %v", err)
+	}
+}
+
+// GetTags retrieves every tag attached to at least one public post together with the number of such posts.
+// The most used tags come first.
+func (t tagRepository) GetTags() ([]TagCount, error) {
+	log := t.logger
+	repo := t.repository
+
+	var tags []TagCount
+	result := repo.
+		Model(&Tag{}).
+		Select("tags.name AS name, COUNT(posts.id) AS post_count").
+		Joins("JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id").
+		Where(publicPostCondition, publicPostArgs(time.Now())...).
+		Where("posts.deleted_at IS NULL").
+		Group("tags.name").
+		Order("post_count DESC, tags.name ASC").
+		Scan(&tags)
+
+	if result.Error != nil {
+		log.Debugf("error fetching tags: %v", result.Error)
+		return []TagCount{}, result.Error
+	}
+
+	log.Debugf("fetched tags: %v", tags)
+	return tags, nil
+}
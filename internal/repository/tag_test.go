@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// tagTestContext contains objects relevant for testing the TagRepository.
+type tagTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.TagRepository
+}
+
+// createTagRepositoryContext creates the context for testing the TagRepository and reduces code duplication.
+func createTagRepositoryContext(t *testing.T) *tagTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateTagRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &tagTestContext{mock, sut}
+}
+
+// TestTagRepository_GetTags tests retrieving every tag with its post count
+func TestTagRepository_GetTags(t *testing.T) {
+	t.Parallel()
+	c := createTagRepositoryContext(t)
+
+	expectedTags := []repository.TagCount{
+		{Name: "go", PostCount: 3},
+		{Name: "backend", PostCount: 1},
+	}
+
+	query := regexp.QuoteMeta("SELECT tags.name AS name, COUNT(posts.id) AS post_count FROM `tags` JOIN post_tags ON post_tags.tag_id = tags.id JOIN posts ON posts.id = post_tags.post_id WHERE")
+	groupQuery := regexp.QuoteMeta("GROUP BY `tags`.`name` ORDER BY post_count DESC, tags.name ASC")
+
+	c.mockDb.ExpectQuery(query + ".*" + groupQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "post_count"}).
+			AddRow(expectedTags[0].Name, expectedTags[0].PostCount).
+			AddRow(expectedTags[1].Name, expectedTags[1].PostCount))
+
+	tags, err := c.sut.GetTags()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedTags, tags, "received tags should match the expected ones")
+}
+
+// TestTagRepository_GetTags_Unexpected_Error tests retrieving every tag with an error
+func TestTagRepository_GetTags_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createTagRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT tags.name AS name, COUNT(posts.id) AS post_count FROM `tags`")
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	tags, err := c.sut.GetTags()
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+	assert.Equal(t, 0, len(tags), "shouldn't receive any tags")
+}
@@ -0,0 +1,198 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=token.go -destination=../mocks/mock_refresh_token_repository.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/errortypes"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RefreshToken DB schema. Only the hash of the token is stored.
+// Every login starts a session, and every refresh replaces the used token with a new one of the same session.
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement"`
+	TokenHash string    `gorm:"size:64;unique;not null"`
+	SessionID string    `gorm:"size:32;index;not null"`
+	UserID    uint      `gorm:"index;not null"`
+	User      User      `gorm:"constraint:OnDelete:CASCADE;"`
+	ExpiresAt time.Time `gorm:"not null"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// RefreshTokenRepository interface defining refresh token-related database operations.
+type RefreshTokenRepository interface {
+	AddRefreshToken(token RefreshToken) (RefreshToken, error)
+	GetRefreshToken(tokenHash string) (RefreshToken, error)
+	RotateRefreshToken(oldToken RefreshToken, newToken RefreshToken) (RefreshToken, error)
+	RevokeSession(sessionID string) error
+	RevokeUserSessions(userID uint) error
+	IsSessionActive(sessionID string, now time.Time) (bool, error)
+	PurgeExpiredRefreshTokens(expiredBefore time.Time) (int, error)
+}
+
+// refreshTokenRepository is the concrete implementation of the RefreshTokenRepository interface.
+type refreshTokenRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateRefreshTokenRepository instantiates the refreshTokenRepository
+func CreateRefreshTokenRepository(logger *zap.SugaredLogger, repository Repository) RefreshTokenRepository {
+	initRefreshTokenModel(logger, repository)
+
+	return &refreshTokenRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initRefreshTokenModel initializes the RefreshToken schema in the database.
+func initRefreshTokenModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&RefreshToken{}); err != nil {
+		logger.Errorf("failed to initialize refresh token model: %v", err)
+	}
+}
+
+// AddRefreshToken stores a new refresh token.
+func (r refreshTokenRepository) AddRefreshToken(token RefreshToken) (RefreshToken, error) {
+	log := r.logger
+	repo := r.repository
+
+	if result := repo.Omit("User").Create(&token); result.Error != nil {
+		log.Debugf("failed to store refresh token of session %s, error: %v", token.SessionID, result.Error)
+		return RefreshToken{}, result.Error
+	}
+
+	log.Debugf("stored refresh token of session %s", token.SessionID)
+	return token, nil
+}
+
+// GetRefreshToken retrieves the refresh token with the given hash along with its user.
+// Revoked and expired tokens are returned as well, so that the reuse of a revoked token can be detected.
+func (r refreshTokenRepository) GetRefreshToken(tokenHash string) (RefreshToken, error) {
+	log := r.logger
+	repo := r.repository
+
+	var token RefreshToken
+	result := repo.Preload("User").Where("token_hash = ?", tokenHash).Take(&token)
+
+	if result.Error != nil {
+		log.Debugf("failed to retrieve refresh token, error: %v", result.Error)
+		if result.Error.Error() == "record not found" {
+			return RefreshToken{}, errortypes.InvalidRefreshTokenError{}
+		}
+		return RefreshToken{}, result.Error
+	}
+
+	log.Debugf("retrieved refresh token of session %s", token.SessionID)
+	return token, nil
+}
+
+// RotateRefreshToken revokes the old token and stores the new one in a single transaction.
+// If the old token has already been revoked in the meantime, nothing is stored.
+func (r refreshTokenRepository) RotateRefreshToken(oldToken RefreshToken, newToken RefreshToken) (RefreshToken, error) {
+	log := r.logger
+	repo := r.repository
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", oldToken.ID).
+			Update("revoked_at", time.Now())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errortypes.InvalidRefreshTokenError{}
+		}
+
+		return tx.Omit("User").Create(&newToken).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to rotate refresh token of session %s, error: %v", oldToken.SessionID, err)
+		return RefreshToken{}, err
+	}
+
+	log.Debugf("rotated refresh token of session %s", oldToken.SessionID)
+	return newToken, nil
+}
+
+// RevokeSession revokes every refresh token of the session.
+func (r refreshTokenRepository) RevokeSession(sessionID string) error {
+	log := r.logger
+	repo := r.repository
+
+	result := repo.
+		Model(&RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		log.Debugf("failed to revoke session %s, error: %v", sessionID, result.Error)
+		return result.Error
+	}
+
+	log.Debugf("revoked session %s", sessionID)
+	return nil
+}
+
+// RevokeUserSessions revokes every refresh token of the user.
+func (r refreshTokenRepository) RevokeUserSessions(userID uint) error {
+	log := r.logger
+	repo := r.repository
+
+	result := repo.
+		Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		log.Debugf("failed to revoke sessions of user %d, error: %v", userID, result.Error)
+		return result.Error
+	}
+
+	log.Debugf("revoked %d refresh tokens of user %d", result.RowsAffected, userID)
+	return nil
+}
+
+// IsSessionActive checks whether the session still has a refresh token that is neither revoked nor expired.
+func (r refreshTokenRepository) IsSessionActive(sessionID string, now time.Time) (bool, error) {
+	log := r.logger
+	repo := r.repository
+
+	var count int64
+	result := repo.
+		Model(&RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL AND expires_at > ?", sessionID, now).
+		Count(&count)
+
+	if result.Error != nil {
+		log.Debugf("failed to check session %s, error: %v", sessionID, result.Error)
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
+
+// PurgeExpiredRefreshTokens permanently deletes every refresh token that expired before the given time.
+// The number of deleted tokens is returned.
+func (r refreshTokenRepository) PurgeExpiredRefreshTokens(expiredBefore time.Time) (int, error) {
+	log := r.logger
+	repo := r.repository
+
+	result := repo.Where("expires_at < ?", expiredBefore).Delete(&RefreshToken{})
+
+	if result.Error != nil {
+		log.Debugf("failed to purge expired refresh tokens, error: %v", result.Error)
+		return 0, result.Error
+	}
+
+	log.Debugf("purged %d expired refresh tokens", result.RowsAffected)
+	return int(result.RowsAffected), nil
+}
@@ -0,0 +1,173 @@
+package repository_test
+
+import (
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// refreshTokenTestContext contains objects relevant for testing the RefreshTokenRepository.
+type refreshTokenTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.RefreshTokenRepository
+}
+
+// createRefreshTokenRepositoryContext creates the context for testing the RefreshTokenRepository and reduces code duplication.
+func createRefreshTokenRepositoryContext(t *testing.T) *refreshTokenTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateRefreshTokenRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &refreshTokenTestContext{mock, sut}
+}
+
+// TestRefreshTokenRepository_AddRefreshToken tests storing a new refresh token.
+func TestRefreshTokenRepository_AddRefreshToken(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	token := repository.RefreshToken{
+		TokenHash: "hash",
+		SessionID: "session",
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	query := regexp.QuoteMeta("INSERT INTO `refresh_tokens` (`token_hash`,`session_id`,`user_id`,`expires_at`,`revoked_at`,`created_at`) VALUES (?,?,?,?,?,?)")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WillReturnResult(sqlmock.NewResult(1, 1))
+	c.mockDb.ExpectCommit()
+
+	result, err := c.sut.AddRefreshToken(token)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, uint(1), result.ID, "stored token should have an ID")
+	assert.Equal(t, token.TokenHash, result.TokenHash, "received token should match the expected one")
+}
+
+// TestRefreshTokenRepository_GetRefreshToken_Not_Found tests retrieving a refresh token that does not exist.
+func TestRefreshTokenRepository_GetRefreshToken_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `refresh_tokens` WHERE token_hash = ? LIMIT ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs("hash", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	token, err := c.sut.GetRefreshToken("hash")
+
+	assert.Equal(t, repository.RefreshToken{}, token, "should not return a token")
+	assert.Equal(t, errortypes.InvalidRefreshTokenError{}, err, "received error should match the expected one")
+}
+
+// TestRefreshTokenRepository_RotateRefreshToken tests replacing a refresh token with a new one.
+func TestRefreshTokenRepository_RotateRefreshToken(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	oldToken := repository.RefreshToken{ID: 1, SessionID: "session"}
+	newToken := repository.RefreshToken{TokenHash: "new", SessionID: "session", UserID: 1}
+
+	revokeQuery := regexp.QuoteMeta("UPDATE `refresh_tokens` SET `revoked_at`=? WHERE id = ? AND revoked_at IS NULL")
+	insertQuery := regexp.QuoteMeta("INSERT INTO `refresh_tokens`")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(revokeQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(insertQuery).WillReturnResult(sqlmock.NewResult(2, 1))
+	c.mockDb.ExpectCommit()
+
+	result, err := c.sut.RotateRefreshToken(oldToken, newToken)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "new", result.TokenHash, "received token should match the expected one")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "every query should be executed")
+}
+
+// TestRefreshTokenRepository_RotateRefreshToken_Already_Revoked tests rotating a refresh token that has been revoked in the meantime.
+func TestRefreshTokenRepository_RotateRefreshToken_Already_Revoked(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	oldToken := repository.RefreshToken{ID: 1, SessionID: "session"}
+	newToken := repository.RefreshToken{TokenHash: "new", SessionID: "session", UserID: 1}
+
+	revokeQuery := regexp.QuoteMeta("UPDATE `refresh_tokens` SET `revoked_at`=? WHERE id = ? AND revoked_at IS NULL")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(revokeQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectRollback()
+
+	result, err := c.sut.RotateRefreshToken(oldToken, newToken)
+
+	assert.Equal(t, repository.RefreshToken{}, result, "should not return a token")
+	assert.Equal(t, errortypes.InvalidRefreshTokenError{}, err, "received error should match the expected one")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "the new token should not be stored")
+}
+
+// TestRefreshTokenRepository_IsSessionActive tests checking whether a session has a valid refresh token.
+func TestRefreshTokenRepository_IsSessionActive(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	now := time.Now()
+	query := regexp.QuoteMeta("SELECT count(*) FROM `refresh_tokens` WHERE session_id = ? AND revoked_at IS NULL AND expires_at > ?")
+
+	c.mockDb.ExpectQuery(query).
+		WithArgs("session", now).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	active, err := c.sut.IsSessionActive("session", now)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.True(t, active, "session should be active")
+}
+
+// TestRefreshTokenRepository_IsSessionActive_Unexpected_Error tests checking a session while encountering an unexpected error.
+func TestRefreshTokenRepository_IsSessionActive_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+	query := regexp.QuoteMeta("SELECT count(*) FROM `refresh_tokens`")
+
+	c.mockDb.ExpectQuery(query).WillReturnError(expectedError)
+
+	active, err := c.sut.IsSessionActive("session", time.Now())
+
+	assert.False(t, active, "session should not be active")
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestRefreshTokenRepository_PurgeExpiredRefreshTokens tests deleting expired refresh tokens.
+func TestRefreshTokenRepository_PurgeExpiredRefreshTokens(t *testing.T) {
+	t.Parallel()
+	c := createRefreshTokenRepositoryContext(t)
+
+	now := time.Now()
+	query := regexp.QuoteMeta("DELETE FROM `refresh_tokens` WHERE expires_at < ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(now).WillReturnResult(sqlmock.NewResult(0, 3))
+	c.mockDb.ExpectCommit()
+
+	count, err := c.sut.PurgeExpiredRefreshTokens(now)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 3, count, "incorrect number of purged tokens")
+}
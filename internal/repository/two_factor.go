@@ -0,0 +1,190 @@
+package repository
+
+//go:generate mockgen-v0.4.0 -source=two_factor.go -destination=../mocks/mock_two_factor_repository.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/errortypes"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// RecoveryCode DB schema. Recovery codes replace TOTP codes once each, and only their hash is stored.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	UserID    uint   `gorm:"index;not null"`
+	User      User   `gorm:"constraint:OnDelete:CASCADE;"`
+	CodeHash  string `gorm:"size:64;unique;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TwoFactorRepository interface defining two-factor authentication-related database operations.
+type TwoFactorRepository interface {
+	SetPendingTOTPSecret(userID uint, secret string) error
+	ActivateTOTPSecret(userID uint, secret string, step int64, codeHashes []string) error
+	DisableTOTP(userID uint) error
+	UseTOTPStep(userID uint, step int64) (bool, error)
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+}
+
+// twoFactorRepository is the concrete implementation of the TwoFactorRepository interface.
+type twoFactorRepository struct {
+	logger     *zap.SugaredLogger
+	repository Repository
+}
+
+// CreateTwoFactorRepository instantiates the twoFactorRepository
+func CreateTwoFactorRepository(logger *zap.SugaredLogger, repository Repository) TwoFactorRepository {
+	initRecoveryCodeModel(logger, repository)
+
+	return &twoFactorRepository{
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// initRecoveryCodeModel initializes the RecoveryCode schema in the database.
+func initRecoveryCodeModel(logger *zap.SugaredLogger, repository Repository) {
+	if err := repository.AutoMigrate(&RecoveryCode{}); err != nil {
+		logger.Errorf("failed to initialize recovery code model: %v", err)
+	}
+}
+
+// SetPendingTOTPSecret stores a new TOTP secret of the user, which is only used once its first code is confirmed.
+// An already enabled secret stays in use until then.
+func (t twoFactorRepository) SetPendingTOTPSecret(userID uint, secret string) error {
+	log := t.logger
+	repo := t.repository
+
+	result := repo.Model(&User{}).Where("id = ?", userID).Update("totp_pending_secret", secret)
+
+	if result.Error != nil {
+		log.Debugf("failed to store pending TOTP secret of user %d, error: %v", userID, result.Error)
+		return result.Error
+	}
+
+	log.Debugf("stored pending TOTP secret of user %d", userID)
+	return nil
+}
+
+// ActivateTOTPSecret enables two-factor authentication with the pending secret of the user.
+// The step of the confirmed code is marked as used and the previous recovery codes are replaced.
+// If the pending secret changed in the meantime, nothing is modified.
+func (t twoFactorRepository) ActivateTOTPSecret(userID uint, secret string, step int64, codeHashes []string) error {
+	log := t.logger
+	repo := t.repository
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&User{}).
+			Where("id = ? AND totp_pending_secret = ?", userID, secret).
+			Updates(map[string]interface{}{
+				"totp_secret":         secret,
+				"totp_pending_secret": nil,
+				"totp_last_step":      step,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errortypes.TwoFactorNotPendingError{}
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]RecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			codes = append(codes, RecoveryCode{UserID: userID, CodeHash: codeHash})
+		}
+
+		return tx.Omit("User").Create(&codes).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to activate TOTP secret of user %d, error: %v", userID, err)
+		return err
+	}
+
+	log.Debugf("activated TOTP secret of user %d", userID)
+	return nil
+}
+
+// DisableTOTP removes the TOTP secrets and the recovery codes of the user.
+func (t twoFactorRepository) DisableTOTP(userID uint) error {
+	log := t.logger
+	repo := t.repository
+
+	err := repo.Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Model(&User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"totp_secret":         nil,
+				"totp_pending_secret": nil,
+				"totp_last_step":      0,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+	})
+
+	if err != nil {
+		log.Debugf("failed to disable TOTP of user %d, error: %v", userID, err)
+		return err
+	}
+
+	log.Debugf("disabled TOTP of user %d", userID)
+	return nil
+}
+
+// UseTOTPStep records the period of a TOTP code used by the user.
+// Returns false if a code of the same or a later period has already been used.
+func (t twoFactorRepository) UseTOTPStep(userID uint, step int64) (bool, error) {
+	log := t.logger
+	repo := t.repository
+
+	result := repo.
+		Model(&User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+
+	if result.Error != nil {
+		log.Debugf("failed to record TOTP period of user %d, error: %v", userID, result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UseRecoveryCode marks the recovery code of the user as used.
+// Returns false if the code doesn't exist or has already been used.
+func (t twoFactorRepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	log := t.logger
+	repo := t.repository
+
+	result := repo.
+		Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		log.Debugf("failed to use recovery code of user %d, error: %v", userID, result.Error)
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Debugf("recovery code of user %d not found or already used", userID)
+		return false, nil
+	}
+
+	log.Debugf("used recovery code of user %d", userID)
+	return true, nil
+}
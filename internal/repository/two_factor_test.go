@@ -0,0 +1,177 @@
+package repository_test
+
+import (
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/repository"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"regexp"
+	"testing"
+)
+
+// twoFactorTestContext contains objects relevant for testing the TwoFactorRepository.
+type twoFactorTestContext struct {
+	mockDb sqlmock.Sqlmock
+	sut    repository.TwoFactorRepository
+}
+
+// createTwoFactorRepositoryContext creates the context for testing the TwoFactorRepository and reduces code duplication.
+func createTwoFactorRepositoryContext(t *testing.T) *twoFactorTestContext {
+	t.Helper()
+
+	db, mock, _ := sqlmock.New()
+	gormDb, _ := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}))
+
+	sut := repository.CreateTwoFactorRepository(logger.CreateLogger(), repository.CreateRepository(gormDb))
+	return &twoFactorTestContext{mock, sut}
+}
+
+// TestTwoFactorRepository_SetPendingTOTPSecret tests storing a TOTP secret awaiting confirmation.
+func TestTwoFactorRepository_SetPendingTOTPSecret(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	query := regexp.QuoteMeta("UPDATE `users` SET `totp_pending_secret`=?,`updated_at`=? WHERE id = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs("SECRET", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.SetPendingTOTPSecret(1, "SECRET")
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestTwoFactorRepository_ActivateTOTPSecret tests enabling the pending TOTP secret and replacing the recovery codes.
+func TestTwoFactorRepository_ActivateTOTPSecret(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	updateQuery := regexp.QuoteMeta("UPDATE `users` SET `totp_last_step`=?,`totp_pending_secret`=?,`totp_secret`=?,`updated_at`=? WHERE id = ? AND totp_pending_secret = ?")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `recovery_codes` WHERE user_id = ?")
+	insertQuery := regexp.QuoteMeta("INSERT INTO `recovery_codes` (`user_id`,`code_hash`,`used_at`,`created_at`) VALUES (?,?,?,?),(?,?,?,?)")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).
+		WithArgs(42, nil, "SECRET", sqlmock.AnyArg(), 1, "SECRET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(deleteQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 10))
+	c.mockDb.ExpectExec(insertQuery).
+		WithArgs(1, "hash1", nil, sqlmock.AnyArg(), 1, "hash2", nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.ActivateTOTPSecret(1, "SECRET", 42, []string{"hash1", "hash2"})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "every query should be executed")
+}
+
+// TestTwoFactorRepository_ActivateTOTPSecret_Not_Pending tests enabling a TOTP secret that isn't pending anymore.
+func TestTwoFactorRepository_ActivateTOTPSecret_Not_Pending(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	updateQuery := regexp.QuoteMeta("UPDATE `users` SET `totp_last_step`=?,`totp_pending_secret`=?,`totp_secret`=?,`updated_at`=? WHERE id = ? AND totp_pending_secret = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectRollback()
+
+	err := c.sut.ActivateTOTPSecret(1, "SECRET", 42, []string{"hash1"})
+
+	assert.Equal(t, errortypes.TwoFactorNotPendingError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorRepository_DisableTOTP tests removing the TOTP secrets and recovery codes of a user.
+func TestTwoFactorRepository_DisableTOTP(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	updateQuery := regexp.QuoteMeta("UPDATE `users` SET `totp_last_step`=?,`totp_pending_secret`=?,`totp_secret`=?,`updated_at`=? WHERE id = ?")
+	deleteQuery := regexp.QuoteMeta("DELETE FROM `recovery_codes` WHERE user_id = ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).WithArgs(0, nil, nil, sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectExec(deleteQuery).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 10))
+	c.mockDb.ExpectCommit()
+
+	err := c.sut.DisableTOTP(1)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Nil(t, c.mockDb.ExpectationsWereMet(), "every query should be executed")
+}
+
+// TestTwoFactorRepository_UseTOTPStep tests recording the period of a used TOTP code.
+func TestTwoFactorRepository_UseTOTPStep(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	query := regexp.QuoteMeta("UPDATE `users` SET `totp_last_step`=?,`updated_at`=? WHERE id = ? AND totp_last_step < ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(42, sqlmock.AnyArg(), 1, 42).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	used, err := c.sut.UseTOTPStep(1, 42)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.True(t, used, "period should be recorded")
+}
+
+// TestTwoFactorRepository_UseTOTPStep_Replayed tests using a TOTP code of a period that has already been used.
+func TestTwoFactorRepository_UseTOTPStep_Replayed(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	query := regexp.QuoteMeta("UPDATE `users` SET `totp_last_step`=?,`updated_at`=? WHERE id = ? AND totp_last_step < ?")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(42, sqlmock.AnyArg(), 1, 42).WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	used, err := c.sut.UseTOTPStep(1, 42)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.False(t, used, "period should not be used twice")
+}
+
+// TestTwoFactorRepository_UseRecoveryCode tests using a recovery code.
+func TestTwoFactorRepository_UseRecoveryCode(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	query := regexp.QuoteMeta("UPDATE `recovery_codes` SET `used_at`=? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(sqlmock.AnyArg(), 1, "hash").WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	used, err := c.sut.UseRecoveryCode(1, "hash")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.True(t, used, "recovery code should be used")
+}
+
+// TestTwoFactorRepository_UseRecoveryCode_Already_Used tests using a recovery code twice.
+func TestTwoFactorRepository_UseRecoveryCode_Already_Used(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorRepositoryContext(t)
+
+	query := regexp.QuoteMeta("UPDATE `recovery_codes` SET `used_at`=? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL")
+
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(query).WithArgs(sqlmock.AnyArg(), 1, "hash").WillReturnResult(sqlmock.NewResult(0, 0))
+	c.mockDb.ExpectCommit()
+
+	used, err := c.sut.UseRecoveryCode(1, "hash")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.False(t, used, "recovery code should not be used twice")
+}
@@ -5,24 +5,54 @@ package repository
 import (
 	"github.com/wlachs/blog/internal/errortypes"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"strings"
 	"time"
 )
 
-// User DB schema
+// Role of a user, determining which operations they are allowed to perform.
+type Role string
+
+const (
+	// RoleAdmin users manage other users and may modify any post.
+	RoleAdmin Role = "admin"
+	// RoleEditor users may modify any post.
+	RoleEditor Role = "editor"
+	// RoleAuthor users may only modify their own posts.
+	RoleAuthor Role = "author"
+)
+
+// Valid checks whether the role is one of the supported roles.
+func (r Role) Valid() bool {
+	return r == RoleAdmin || r == RoleEditor || r == RoleAuthor
+}
+
+// User DB schema.
+// The TOTP secret is only set once two-factor authentication is enabled, while a new secret is pending until
+// its first code is confirmed. The last used TOTP period prevents replaying codes.
 type User struct {
-	ID           uint   `gorm:"primaryKey;autoIncrement"`
-	UserName     string `gorm:"unique;not null"`
-	PasswordHash string `gorm:"not null"`
-	Posts        []Post `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID                uint    `gorm:"primaryKey;autoIncrement"`
+	UserName          string  `gorm:"unique;not null"`
+	PasswordHash      string  `gorm:"not null"`
+	Role              Role    `gorm:"type:varchar(16);not null;default:author"`
+	TOTPSecret        *string `gorm:"size:64"`
+	TOTPPendingSecret *string `gorm:"size:64"`
+	TOTPLastStep      int64   `gorm:"not null;default:0"`
+	Posts             []Post  `gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// TwoFactorEnabled reports whether the user has to provide a TOTP or recovery code upon login.
+func (u User) TwoFactorEnabled() bool {
+	return u.TOTPSecret != nil
 }
 
 // UserRepository interface defining user-related database operations.
 type UserRepository interface {
 	AddUser(user User) (User, error)
 	UpdateUser(user User) (User, error)
+	UpdateUserRole(userName string, role Role) (User, error)
 	DeleteUser(userName string) error
 	GetUser(userName string) (User, error)
 	GetUsers(pageIndex int, pageSize int) ([]User, int, error)
@@ -44,10 +74,27 @@ func CreateUserRepository(logger *zap.SugaredLogger, repository Repository) User
 	}
 }
 
-// initUserModel initializes the User schema in the database
+// initUserModel initializes the User schema in the database.
+// Users created before the role column existed had unrestricted access, so they are marked as admins.
 func initUserModel(logger *zap.SugaredLogger, repository Repository) {
+	hadRole := repository.Migrator().HasColumn(&User{}, "Role")
+
 	if err := repository.AutoMigrate(&User{}); err != nil {
 		logger.Errorf("failed to initialize user model: %v", err)
+		return
+	}
+
+	if hadRole {
+		return
+	}
+
+	result := repository.
+		Model(&User{}).
+		Where("1 = 1").
+		Update("role", RoleAdmin)
+
+	if result.Error != nil {
+		logger.Errorf("failed to mark existing users as admins: %v", result.Error)
 	}
 }
 
@@ -91,6 +138,27 @@ func (u userRepository) UpdateUser(user User) (User, error) {
 	return userToUpdate, nil
 }
 
+// UpdateUserRole changes the role of an existing user.
+func (u userRepository) UpdateUserRole(userName string, role Role) (User, error) {
+	log := u.logger
+	repo := u.repository
+
+	user, err := u.GetUser(userName)
+	if err != nil {
+		return User{}, err
+	}
+
+	if result := repo.Model(&User{}).Where("id = ?", user.ID).Update("role", role); result.Error != nil {
+		log.Debugf("failed to update role of user %s, error: %v", userName, result.Error)
+		return User{}, result.Error
+	}
+
+	user.Role = role
+
+	log.Debugf("updated role of user %s to %s", userName, role)
+	return user, nil
+}
+
 // DeleteUser deletes a user from the database.
 func (u userRepository) DeleteUser(userName string) error {
 	log := u.logger
@@ -121,7 +189,7 @@ func (u userRepository) GetUser(userName string) (User, error) {
 		UserName: userName,
 	}
 
-	result := repo.Preload("Posts").Where(&user).Take(&user)
+	result := repo.Preload("Posts", publicPosts).Where(&user).Take(&user)
 
 	if result.Error != nil {
 		log.Debugf("failed to retrieve user: %v, error: %v", user, result.Error)
@@ -144,7 +212,7 @@ func (u userRepository) GetUsers(pageIndex int, pageSize int) ([]User, int, erro
 	repo := u.repository
 
 	var users []User
-	result := repo.Preload("Posts").
+	result := repo.Preload("Posts", publicPosts).
 		Order("user_name ASC").
 		Limit(pageSize).
 		Offset((pageIndex - 1) * pageSize).
@@ -164,6 +232,11 @@ func (u userRepository) GetUsers(pageIndex int, pageSize int) ([]User, int, erro
 	return users, int(count), nil
 }
 
+// publicPosts restricts preloaded posts to the ones visible to anonymous readers
+func publicPosts(db *gorm.DB) *gorm.DB {
+	return db.Where(publicPostCondition, publicPostArgs(time.Now())...)
+}
+
 // populateUserAsAuthorOfPosts manually sets user model for contained posts
 func populateUserAsAuthorOfPosts(user *User) {
 	for i := range user.Posts {
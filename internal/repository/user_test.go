@@ -42,7 +42,7 @@ func TestUserRepository_AddUser(t *testing.T) {
 		UserName: "testUser",
 	}
 
-	userQuery := regexp.QuoteMeta("INSERT INTO `users` (`user_name`,`password_hash`,`created_at`,`updated_at`) VALUES (?,?,?,?)")
+	userQuery := regexp.QuoteMeta("INSERT INTO `users` (`user_name`,`password_hash`,`role`,`totp_secret`,`totp_pending_secret`,`totp_last_step`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?)")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(userQuery).WillReturnResult(sqlmock.NewResult(0, 1))
@@ -66,7 +66,7 @@ func TestUserRepository_AddUser_Duplicate_User(t *testing.T) {
 	dbErr := fmt.Errorf("error 1062 (23000): duplicate entry")
 	expectedError := errortypes.DuplicateElementError{Key: author.UserName}
 
-	userQuery := regexp.QuoteMeta("INSERT INTO `users` (`user_name`,`password_hash`,`created_at`,`updated_at`) VALUES (?,?,?,?)")
+	userQuery := regexp.QuoteMeta("INSERT INTO `users` (`user_name`,`password_hash`,`role`,`totp_secret`,`totp_pending_secret`,`totp_last_step`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?)")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(userQuery).WillReturnError(dbErr)
@@ -89,7 +89,7 @@ func TestUserRepository_AddUser_Unexpected_Error(t *testing.T) {
 
 	expectedError := fmt.Errorf("unexpected error")
 
-	userQuery := regexp.QuoteMeta("INSERT INTO `users` (`user_name`,`password_hash`,`created_at`,`updated_at`) VALUES (?,?,?,?)")
+	userQuery := regexp.QuoteMeta("INSERT INTO `users` (`user_name`,`password_hash`,`role`,`totp_secret`,`totp_pending_secret`,`totp_last_step`,`created_at`,`updated_at`) VALUES (?,?,?,?,?,?,?,?)")
 
 	c.mockDb.ExpectBegin()
 	c.mockDb.ExpectExec(userQuery).WillReturnError(expectedError)
@@ -123,6 +123,44 @@ func TestUserRepository_GetUser(t *testing.T) {
 	assert.Equal(t, expectedUser, post, "received user should match the expected one")
 }
 
+// TestUserRepository_UpdateUserRole tests changing the role of an existing user.
+func TestUserRepository_UpdateUserRole(t *testing.T) {
+	t.Parallel()
+	c := createUserRepositoryContext(t)
+
+	selectQuery := regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`user_name` = ? LIMIT ?")
+	updateQuery := regexp.QuoteMeta("UPDATE `users` SET `role`=?,`updated_at`=? WHERE id = ?")
+
+	c.mockDb.ExpectQuery(selectQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "role"}).
+			AddRow(1, "testUser", "author"))
+	c.mockDb.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `posts`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	c.mockDb.ExpectBegin()
+	c.mockDb.ExpectExec(updateQuery).WillReturnResult(sqlmock.NewResult(0, 1))
+	c.mockDb.ExpectCommit()
+
+	user, err := c.sut.UpdateUserRole("testUser", repository.RoleEditor)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, repository.RoleEditor, user.Role, "role should be updated")
+}
+
+// TestUserRepository_UpdateUserRole_Record_Not_Found tests changing the role of a non-existent user.
+func TestUserRepository_UpdateUserRole_Record_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createUserRepositoryContext(t)
+
+	query := regexp.QuoteMeta("SELECT * FROM `users` WHERE `users`.`user_name` = ? LIMIT ?")
+	expectedError := errortypes.UserNotFoundError{UserName: "testUser"}
+
+	c.mockDb.ExpectQuery(query).WillReturnError(fmt.Errorf("record not found"))
+
+	_, err := c.sut.UpdateUserRole("testUser", repository.RoleEditor)
+
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
 // TestUserRepository_GetUser_Record_Not_Found tests retrieving a non-existent user from the database
 func TestUserRepository_GetUser_Record_Not_Found(t *testing.T) {
 	t.Parallel()
@@ -164,7 +202,7 @@ func TestUserRepository_GetUsers(t *testing.T) {
 	c := createUserRepositoryContext(t)
 
 	userQuery := regexp.QuoteMeta("SELECT * FROM `users` ORDER BY user_name ASC LIMIT ? OFFSET ?")
-	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE `posts`.`author_id` IN (?,?)")
+	postQuery := regexp.QuoteMeta("SELECT * FROM `posts` WHERE ((posts.status = ? OR (posts.status = ? AND posts.publish_at <= ?)) AND (posts.unpublish_at IS NULL OR posts.unpublish_at > ?)) AND `posts`.`author_id` IN (?,?)")
 
 	c.mockDb.ExpectQuery(userQuery).
 		WithArgs(3, 3).
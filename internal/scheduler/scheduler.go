@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/services"
+	"os"
+	"sync"
+	"time"
+)
+
+// Scheduler interface. Runs the periodic background jobs of the blog.
+type Scheduler interface {
+	Start()
+	Stop()
+}
+
+// job is a named task executed periodically by the scheduler.
+type job struct {
+	name     string
+	interval time.Duration
+	run      func() error
+}
+
+// scheduler is the concrete implementation of the Scheduler interface.
+type scheduler struct {
+	cont container.Container
+	jobs []job
+	done chan struct{}
+	once sync.Once
+}
+
+// defaultPostScheduleInterval sets how often post schedules are checked if SCHEDULER_INTERVAL is not set
+const defaultPostScheduleInterval = time.Minute
+
+// defaultTrashPurgeInterval sets how often the trash is purged if TRASH_PURGE_INTERVAL is not set
+const defaultTrashPurgeInterval = time.Hour
+
+// defaultRelatedPostsInterval sets how often related posts are recomputed if RELATED_POSTS_INTERVAL is not set
+const defaultRelatedPostsInterval = time.Hour
+
+// defaultTokenPurgeInterval sets how often expired refresh tokens are purged if TOKEN_PURGE_INTERVAL is not set
+const defaultTokenPurgeInterval = time.Hour
+
+// CreateScheduler instantiates the scheduler using the application container and registers the background jobs.
+func CreateScheduler(
+	cont container.Container,
+	postService services.PostService,
+	relatedPostService services.RelatedPostService,
+	tokenService services.TokenService,
+) Scheduler {
+	s := &scheduler{
+		cont: cont,
+		done: make(chan struct{}),
+	}
+
+	s.jobs = []job{
+		{
+			name:     "post schedules",
+			interval: readInterval(cont, "SCHEDULER_INTERVAL", defaultPostScheduleInterval),
+			run:      postService.ApplyPostSchedules,
+		},
+		{
+			name:     "trash purge",
+			interval: readInterval(cont, "TRASH_PURGE_INTERVAL", defaultTrashPurgeInterval),
+			run:      postService.PurgeTrash,
+		},
+		{
+			name:     "related posts",
+			interval: readInterval(cont, "RELATED_POSTS_INTERVAL", defaultRelatedPostsInterval),
+			run:      relatedPostService.UpdateRelatedPosts,
+		},
+		{
+			name:     "token purge",
+			interval: readInterval(cont, "TOKEN_PURGE_INTERVAL", defaultTokenPurgeInterval),
+			run:      tokenService.PurgeExpiredTokens,
+		},
+	}
+
+	return s
+}
+
+// readInterval reads a duration such as "30s" or "5m" from the given environment variable.
+// If the variable is missing or invalid, the fallback is used.
+func readInterval(cont container.Container, key string, fallback time.Duration) time.Duration {
+	log := cont.GetLogger()
+
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Errorf("invalid %s value \"%s\", falling back to %v", key, value, fallback)
+		return fallback
+	}
+
+	return interval
+}
+
+// Start launches every job in the background. Each job runs once immediately and then periodically.
+func (s *scheduler) Start() {
+	log := s.cont.GetLogger()
+
+	for _, j := range s.jobs {
+		log.Infof("starting background job \"%s\" with interval %v", j.name, j.interval)
+		go s.loop(j)
+	}
+}
+
+// Stop terminates every running job. Calling it more than once has no effect.
+func (s *scheduler) Stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+// loop executes the job until the scheduler is stopped.
+func (s *scheduler) loop(j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	s.execute(j)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.execute(j)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// execute runs the job once and logs its failure.
+func (s *scheduler) execute(j job) {
+	log := s.cont.GetLogger()
+
+	if err := j.run(); err != nil {
+		log.Errorf("background job \"%s\" failed: %v", j.name, err)
+	}
+}
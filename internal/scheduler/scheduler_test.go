@@ -0,0 +1,157 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/scheduler"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// schedulerTestContext contains objects relevant for testing the Scheduler.
+type schedulerTestContext struct {
+	mockPostService        *mocks.MockPostService
+	mockRelatedPostService *mocks.MockRelatedPostService
+	mockTokenService       *mocks.MockTokenService
+	sut                    scheduler.Scheduler
+}
+
+// createSchedulerContext creates the context for testing the Scheduler and reduces code duplication.
+func createSchedulerContext(t *testing.T) *schedulerTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostService := mocks.NewMockPostService(mockCtrl)
+	mockRelatedPostService := mocks.NewMockRelatedPostService(mockCtrl)
+	mockTokenService := mocks.NewMockTokenService(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := scheduler.CreateScheduler(cont, mockPostService, mockRelatedPostService, mockTokenService)
+
+	return &schedulerTestContext{mockPostService, mockRelatedPostService, mockTokenService, sut}
+}
+
+// TestScheduler_Start tests that post schedules are applied as soon as the scheduler starts.
+func TestScheduler_Start(t *testing.T) {
+	t.Parallel()
+	c := createSchedulerContext(t)
+
+	called := make(chan struct{})
+	c.mockTokenService.EXPECT().PurgeExpiredTokens().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().PurgeTrash().Return(nil).AnyTimes()
+	c.mockRelatedPostService.EXPECT().UpdateRelatedPosts().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().ApplyPostSchedules().DoAndReturn(func() error {
+		close(called)
+		return nil
+	})
+
+	c.sut.Start()
+	defer c.sut.Stop()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		assert.Fail(t, "post schedules weren't applied")
+	}
+}
+
+// TestScheduler_Start_Failing_Job tests that a failing job doesn't bring down the scheduler.
+func TestScheduler_Start_Failing_Job(t *testing.T) {
+	t.Parallel()
+	c := createSchedulerContext(t)
+
+	called := make(chan struct{})
+	c.mockTokenService.EXPECT().PurgeExpiredTokens().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().PurgeTrash().Return(nil).AnyTimes()
+	c.mockRelatedPostService.EXPECT().UpdateRelatedPosts().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().ApplyPostSchedules().DoAndReturn(func() error {
+		close(called)
+		return fmt.Errorf("unexpected error")
+	})
+
+	c.sut.Start()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		assert.Fail(t, "post schedules weren't applied")
+	}
+
+	c.sut.Stop()
+	c.sut.Stop()
+}
+
+// TestScheduler_Start_Trash_Purge tests that the trash is purged as soon as the scheduler starts.
+func TestScheduler_Start_Trash_Purge(t *testing.T) {
+	t.Parallel()
+	c := createSchedulerContext(t)
+
+	called := make(chan struct{})
+	c.mockTokenService.EXPECT().PurgeExpiredTokens().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().ApplyPostSchedules().Return(nil).AnyTimes()
+	c.mockRelatedPostService.EXPECT().UpdateRelatedPosts().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().PurgeTrash().DoAndReturn(func() error {
+		close(called)
+		return nil
+	})
+
+	c.sut.Start()
+	defer c.sut.Stop()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		assert.Fail(t, "trash wasn't purged")
+	}
+}
+
+// TestScheduler_Start_Related_Posts tests that related posts are computed as soon as the scheduler starts.
+func TestScheduler_Start_Related_Posts(t *testing.T) {
+	t.Parallel()
+	c := createSchedulerContext(t)
+
+	called := make(chan struct{})
+	c.mockTokenService.EXPECT().PurgeExpiredTokens().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().ApplyPostSchedules().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().PurgeTrash().Return(nil).AnyTimes()
+	c.mockRelatedPostService.EXPECT().UpdateRelatedPosts().DoAndReturn(func() error {
+		close(called)
+		return nil
+	})
+
+	c.sut.Start()
+	defer c.sut.Stop()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		assert.Fail(t, "related posts weren't computed")
+	}
+}
+
+// TestScheduler_Start_Token_Purge tests that expired refresh tokens are purged as soon as the scheduler starts.
+func TestScheduler_Start_Token_Purge(t *testing.T) {
+	t.Parallel()
+	c := createSchedulerContext(t)
+
+	called := make(chan struct{})
+	c.mockPostService.EXPECT().ApplyPostSchedules().Return(nil).AnyTimes()
+	c.mockPostService.EXPECT().PurgeTrash().Return(nil).AnyTimes()
+	c.mockRelatedPostService.EXPECT().UpdateRelatedPosts().Return(nil).AnyTimes()
+	c.mockTokenService.EXPECT().PurgeExpiredTokens().DoAndReturn(func() error {
+		close(called)
+		return nil
+	})
+
+	c.sut.Start()
+	defer c.sut.Stop()
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		assert.Fail(t, "expired tokens weren't purged")
+	}
+}
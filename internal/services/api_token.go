@@ -0,0 +1,166 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=api_token.go -destination=../mocks/mock_api_token_service.go -package=mocks
+
+import (
+	"encoding/base64"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// APITokenPrefix starts every personal API token, distinguishing them from access tokens.
+const APITokenPrefix = "blog_pat_"
+
+// maxAPITokenNameLength is the longest name a personal API token may have
+const maxAPITokenNameLength = 64
+
+// lastUsedPrecision sets how often the last use of a personal API token is recorded at most
+const lastUsedPrecision = time.Minute
+
+// APITokenService interface. Defines the lifecycle of personal API tokens.
+type APITokenService interface {
+	CreateAPIToken(userID string, name string, scopes []repository.Scope) (repository.APIToken, string, error)
+	GetAPITokens(userID string) ([]repository.APIToken, error)
+	RevokeAPIToken(userID string, tokenID uint) error
+	ValidateAPIToken(apiToken string) (repository.APIToken, error)
+}
+
+// apiTokenService is the concrete implementation of the APITokenService interface.
+type apiTokenService struct {
+	cont container.Container
+}
+
+// CreateAPITokenService instantiates the apiTokenService using the application container.
+func CreateAPITokenService(cont container.Container) APITokenService {
+	return &apiTokenService{cont}
+}
+
+// CreateAPIToken issues a new personal API token with the given name and scopes for the user.
+// The token itself is only returned here, later on only its hash is known.
+func (a apiTokenService) CreateAPIToken(userID string, name string, scopes []repository.Scope) (repository.APIToken, string, error) {
+	log := a.cont.GetLogger()
+	userRepository := a.cont.GetUserRepository()
+	apiTokenRepository := a.cont.GetAPITokenRepository()
+
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPITokenNameLength {
+		return repository.APIToken{}, "", errortypes.InvalidAPITokenNameError{Name: name}
+	}
+
+	scopes, err := validateScopes(scopes)
+	if err != nil {
+		return repository.APIToken{}, "", err
+	}
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return repository.APIToken{}, "", err
+	}
+
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		log.Errorf("failed to generate API token: %v", err)
+		return repository.APIToken{}, "", errortypes.UnexpectedAPITokenError{}
+	}
+
+	token := APITokenPrefix + secret
+	model, err := apiTokenRepository.AddAPIToken(repository.APIToken{
+		Name:      name,
+		TokenHash: hashToken(token),
+		Scopes:    scopes,
+		UserID:    user.ID,
+	})
+
+	if err != nil {
+		return repository.APIToken{}, "", err
+	}
+
+	log.Infof("user %s created API token \"%s\" with scopes %v", userID, name, scopes)
+	return model, token, nil
+}
+
+// GetAPITokens lists the personal API tokens of the user.
+func (a apiTokenService) GetAPITokens(userID string) ([]repository.APIToken, error) {
+	userRepository := a.cont.GetUserRepository()
+	apiTokenRepository := a.cont.GetAPITokenRepository()
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return []repository.APIToken{}, err
+	}
+
+	return apiTokenRepository.GetAPITokens(user.ID)
+}
+
+// RevokeAPIToken revokes a personal API token of the user. Tokens of other users cannot be revoked.
+func (a apiTokenService) RevokeAPIToken(userID string, tokenID uint) error {
+	log := a.cont.GetLogger()
+	userRepository := a.cont.GetUserRepository()
+	apiTokenRepository := a.cont.GetAPITokenRepository()
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err = apiTokenRepository.DeleteAPIToken(user.ID, tokenID); err != nil {
+		return err
+	}
+
+	log.Infof("user %s revoked API token %d", userID, tokenID)
+	return nil
+}
+
+// ValidateAPIToken looks up the personal API token along with its user and records its use.
+func (a apiTokenService) ValidateAPIToken(apiToken string) (repository.APIToken, error) {
+	log := a.cont.GetLogger()
+	apiTokenRepository := a.cont.GetAPITokenRepository()
+
+	if !strings.HasPrefix(apiToken, APITokenPrefix) {
+		return repository.APIToken{}, errortypes.InvalidAuthTokenError{}
+	}
+
+	token, err := apiTokenRepository.GetAPITokenByHash(hashToken(apiToken))
+	if err != nil {
+		return repository.APIToken{}, err
+	}
+
+	// Recording every single use would write to the database on each request
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= lastUsedPrecision {
+		if err = apiTokenRepository.UpdateAPITokenLastUsed(token.ID, now); err != nil {
+			log.Errorf("failed to record use of API token %d: %v", token.ID, err)
+		} else {
+			token.LastUsedAt = &now
+		}
+	}
+
+	return token, nil
+}
+
+// validateScopes makes sure at least one scope is given and all of them are supported.
+// Duplicates are removed.
+func validateScopes(scopes []repository.Scope) ([]repository.Scope, error) {
+	if len(scopes) == 0 {
+		return nil, errortypes.InvalidScopeError{}
+	}
+
+	unique := make([]repository.Scope, 0, len(scopes))
+	seen := map[repository.Scope]bool{}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, errortypes.InvalidScopeError{Scope: string(scope)}
+		}
+
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+
+	return unique, nil
+}
@@ -0,0 +1,176 @@
+package services_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apiTokenTestContext contains objects relevant for testing the APITokenService.
+type apiTokenTestContext struct {
+	mockUserRepository     *mocks.MockUserRepository
+	mockAPITokenRepository *mocks.MockAPITokenRepository
+	sut                    services.APITokenService
+}
+
+// createAPITokenServiceContext creates the context for testing the APITokenService and reduces code duplication.
+func createAPITokenServiceContext(t *testing.T) *apiTokenTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
+	mockAPITokenRepository := mocks.NewMockAPITokenRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, mockUserRepository, nil, nil, nil, nil, nil, nil, nil, mockAPITokenRepository, nil, nil)
+	sut := services.CreateAPITokenService(cont)
+
+	return &apiTokenTestContext{mockUserRepository, mockAPITokenRepository, sut}
+}
+
+// TestAPITokenService_CreateAPIToken tests creating a personal API token.
+func TestAPITokenService_CreateAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	var stored repository.APIToken
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, UserName: "testUser"}, nil)
+	c.mockAPITokenRepository.EXPECT().AddAPIToken(gomock.Any()).DoAndReturn(func(token repository.APIToken) (repository.APIToken, error) {
+		stored = token
+		return token, nil
+	})
+
+	scopes := []repository.Scope{repository.ScopePostsWrite, repository.ScopePostsRead, repository.ScopePostsWrite}
+	token, secret, err := c.sut.CreateAPIToken("testUser", " CI ", scopes)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.True(t, strings.HasPrefix(secret, services.APITokenPrefix), "token should be recognizable")
+	assert.Equal(t, sha256Hex(secret), stored.TokenHash, "only the hash of the token should be stored")
+	assert.Equal(t, "CI", token.Name, "name should be trimmed")
+	assert.Equal(t, uint(2), token.UserID, "token should belong to the user")
+	assert.Equal(t, []repository.Scope{repository.ScopePostsWrite, repository.ScopePostsRead}, token.Scopes, "duplicate scopes should be removed")
+}
+
+// TestAPITokenService_CreateAPIToken_Invalid_Scope tests creating a personal API token with an unknown scope.
+func TestAPITokenService_CreateAPIToken_Invalid_Scope(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	_, _, err := c.sut.CreateAPIToken("testUser", "CI", []repository.Scope{repository.ScopePostsWrite, "everything"})
+
+	assert.Equal(t, errortypes.InvalidScopeError{Scope: "everything"}, err, "incorrect error type")
+}
+
+// TestAPITokenService_CreateAPIToken_Missing_Scopes tests creating a personal API token without scopes.
+func TestAPITokenService_CreateAPIToken_Missing_Scopes(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	_, _, err := c.sut.CreateAPIToken("testUser", "CI", nil)
+
+	assert.Equal(t, errortypes.InvalidScopeError{}, err, "incorrect error type")
+}
+
+// TestAPITokenService_CreateAPIToken_Invalid_Name tests creating a personal API token without name.
+func TestAPITokenService_CreateAPIToken_Invalid_Name(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	_, _, err := c.sut.CreateAPIToken("testUser", "  ", []repository.Scope{repository.ScopePostsWrite})
+
+	assert.Equal(t, errortypes.InvalidAPITokenNameError{Name: ""}, err, "incorrect error type")
+}
+
+// TestAPITokenService_GetAPITokens tests listing the personal API tokens of a user.
+func TestAPITokenService_GetAPITokens(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	tokens := []repository.APIToken{{ID: 1, Name: "CI"}}
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, UserName: "testUser"}, nil)
+	c.mockAPITokenRepository.EXPECT().GetAPITokens(uint(2)).Return(tokens, nil)
+
+	result, err := c.sut.GetAPITokens("testUser")
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, tokens, result, "incorrect tokens")
+}
+
+// TestAPITokenService_RevokeAPIToken tests revoking a personal API token of a user.
+func TestAPITokenService_RevokeAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, UserName: "testUser"}, nil)
+	c.mockAPITokenRepository.EXPECT().DeleteAPIToken(uint(2), uint(1)).Return(errortypes.APITokenNotFoundError{ID: 1})
+
+	err := c.sut.RevokeAPIToken("testUser", 1)
+
+	assert.Equal(t, errortypes.APITokenNotFoundError{ID: 1}, err, "incorrect error type")
+}
+
+// TestAPITokenService_ValidateAPIToken tests validating a personal API token and recording its use.
+func TestAPITokenService_ValidateAPIToken(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	token := services.APITokenPrefix + "secret"
+	stored := repository.APIToken{ID: 1, User: repository.User{UserName: "testUser"}}
+	c.mockAPITokenRepository.EXPECT().GetAPITokenByHash(sha256Hex(token)).Return(stored, nil)
+	c.mockAPITokenRepository.EXPECT().UpdateAPITokenLastUsed(uint(1), gomock.Any()).Return(nil)
+
+	result, err := c.sut.ValidateAPIToken(token)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.NotNil(t, result.LastUsedAt, "last use should be recorded")
+}
+
+// TestAPITokenService_ValidateAPIToken_Recently_Used tests that uses within a minute are not recorded again.
+func TestAPITokenService_ValidateAPIToken_Recently_Used(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	token := services.APITokenPrefix + "secret"
+	lastUsedAt := time.Now().Add(-10 * time.Second)
+	stored := repository.APIToken{ID: 1, LastUsedAt: &lastUsedAt}
+	c.mockAPITokenRepository.EXPECT().GetAPITokenByHash(sha256Hex(token)).Return(stored, nil)
+
+	result, err := c.sut.ValidateAPIToken(token)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, &lastUsedAt, result.LastUsedAt, "last use should not be updated")
+}
+
+// TestAPITokenService_ValidateAPIToken_Failed_Recording tests that failing to record the use doesn't reject the token.
+func TestAPITokenService_ValidateAPIToken_Failed_Recording(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	token := services.APITokenPrefix + "secret"
+	c.mockAPITokenRepository.EXPECT().GetAPITokenByHash(sha256Hex(token)).Return(repository.APIToken{ID: 1}, nil)
+	c.mockAPITokenRepository.EXPECT().UpdateAPITokenLastUsed(uint(1), gomock.Any()).Return(fmt.Errorf("unexpected error"))
+
+	_, err := c.sut.ValidateAPIToken(token)
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestAPITokenService_ValidateAPIToken_Unknown tests validating a personal API token that doesn't exist.
+func TestAPITokenService_ValidateAPIToken_Unknown(t *testing.T) {
+	t.Parallel()
+	c := createAPITokenServiceContext(t)
+
+	token := services.APITokenPrefix + "secret"
+	c.mockAPITokenRepository.EXPECT().GetAPITokenByHash(sha256Hex(token)).Return(repository.APIToken{}, errortypes.InvalidAuthTokenError{})
+
+	_, err := c.sut.ValidateAPIToken(token)
+
+	assert.Equal(t, errortypes.InvalidAuthTokenError{}, err, "incorrect error type")
+}
@@ -0,0 +1,84 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=archive.go -destination=../mocks/mock_archive_service.go -package=mocks
+
+import (
+	"fmt"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"math"
+	"time"
+)
+
+// ArchiveService interface. Defines the business logic of the date-based post archive.
+// A month of 0 stands for the whole year.
+type ArchiveService interface {
+	GetArchive() ([]repository.ArchiveMonth, error)
+	GetArchivePosts(year int, month int) ([]repository.Post, int, error)
+	GetArchivePostsPage(year int, month int, page int) ([]repository.Post, int, error)
+}
+
+// archiveService is the concrete implementation of the ArchiveService interface.
+type archiveService struct {
+	cont container.Container
+}
+
+// CreateArchiveService instantiates the archiveService using the application container.
+func CreateArchiveService(cont container.Container) ArchiveService {
+	return &archiveService{cont}
+}
+
+// GetArchive retrieves the number of published posts per month, most recent month first.
+func (a archiveService) GetArchive() ([]repository.ArchiveMonth, error) {
+	postRepository := a.cont.GetPostRepository()
+	return postRepository.GetArchiveMonths()
+}
+
+// GetArchivePosts retrieves the first page of published posts created in the given period.
+func (a archiveService) GetArchivePosts(year int, month int) ([]repository.Post, int, error) {
+	return a.GetArchivePostsPage(year, month, 1)
+}
+
+// GetArchivePostsPage retrieves one page of published posts created in the given period, in chronologically
+// reversed order. The second return parameter holds the number of pages.
+func (a archiveService) GetArchivePostsPage(year int, month int, page int) ([]repository.Post, int, error) {
+	log := a.cont.GetLogger()
+	postRepository := a.cont.GetPostRepository()
+
+	from, before, err := archivePeriod(year, month)
+	if err != nil {
+		log.Errorf("invalid archive period: %v", err)
+		return nil, -1, err
+	}
+
+	if page < 1 {
+		log.Errorf("invalid archive page number %d", page)
+		return nil, -1, errortypes.InvalidPostPageError{Page: page}
+	}
+
+	filter := repository.PostFilter{CreatedFrom: from, CreatedBefore: before}
+	posts, count, err := postRepository.GetPosts(filter, page, postPageSize)
+	pages := int(math.Ceil(float64(count) / float64(postPageSize)))
+
+	return posts, pages, err
+}
+
+// archivePeriod returns the start and the exclusive end of the given year or month in the time zone of the database.
+func archivePeriod(year int, month int) (time.Time, time.Time, error) {
+	if year < 1 || year > 9999 {
+		return time.Time{}, time.Time{}, errortypes.InvalidArchivePeriodError{Period: fmt.Sprint(year)}
+	}
+
+	if month < 0 || month > 12 {
+		return time.Time{}, time.Time{}, errortypes.InvalidArchivePeriodError{Period: fmt.Sprintf("%d/%d", year, month)}
+	}
+
+	if month == 0 {
+		from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+		return from, from.AddDate(1, 0, 0), nil
+	}
+
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	return from, from.AddDate(0, 1, 0), nil
+}
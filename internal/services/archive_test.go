@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// archiveTestContext contains objects relevant for testing the ArchiveService.
+type archiveTestContext struct {
+	mockPostRepository *mocks.MockPostRepository
+	sut                services.ArchiveService
+}
+
+// createArchiveServiceContext creates the context for testing the ArchiveService and reduces code duplication.
+func createArchiveServiceContext(t *testing.T) *archiveTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), mockPostRepository, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := services.CreateArchiveService(cont)
+
+	return &archiveTestContext{mockPostRepository, sut}
+}
+
+// TestArchiveService_GetArchive tests counting the published posts by month.
+func TestArchiveService_GetArchive(t *testing.T) {
+	t.Parallel()
+	c := createArchiveServiceContext(t)
+
+	months := []repository.ArchiveMonth{{Year: 2024, Month: 3, Count: 2}}
+
+	c.mockPostRepository.EXPECT().GetArchiveMonths().Return(months, nil)
+
+	m, err := c.sut.GetArchive()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, months, m, "incorrect archive months")
+}
+
+// TestArchiveService_GetArchivePosts_Year tests getting the posts created in a year.
+func TestArchiveService_GetArchivePosts_Year(t *testing.T) {
+	t.Parallel()
+	c := createArchiveServiceContext(t)
+
+	filter := repository.PostFilter{
+		CreatedFrom:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local),
+		CreatedBefore: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.Local),
+	}
+	posts := []repository.Post{{URLHandle: "testUrlHandle"}}
+
+	c.mockPostRepository.EXPECT().GetPosts(filter, 1, 5).Return(posts, 6, nil)
+
+	p, pages, err := c.sut.GetArchivePosts(2024, 0)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, posts, p, "incorrect posts")
+	assert.Equal(t, 2, pages, "incorrect page count")
+}
+
+// TestArchiveService_GetArchivePostsPage_Month tests getting a page of the posts created in a month.
+func TestArchiveService_GetArchivePostsPage_Month(t *testing.T) {
+	t.Parallel()
+	c := createArchiveServiceContext(t)
+
+	filter := repository.PostFilter{
+		CreatedFrom:   time.Date(2023, time.December, 1, 0, 0, 0, 0, time.Local),
+		CreatedBefore: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.Local),
+	}
+
+	c.mockPostRepository.EXPECT().GetPosts(filter, 2, 5).Return([]repository.Post{}, 5, nil)
+
+	_, pages, err := c.sut.GetArchivePostsPage(2023, 12, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 1, pages, "incorrect page count")
+}
+
+// TestArchiveService_GetArchivePostsPage_Invalid_Period tests getting the posts of an invalid period.
+func TestArchiveService_GetArchivePostsPage_Invalid_Period(t *testing.T) {
+	t.Parallel()
+	c := createArchiveServiceContext(t)
+
+	_, _, err := c.sut.GetArchivePostsPage(0, 0, 1)
+	assert.Equal(t, errortypes.InvalidArchivePeriodError{Period: "0"}, err, "incorrect error type")
+
+	_, _, err = c.sut.GetArchivePostsPage(2024, 13, 1)
+	assert.Equal(t, errortypes.InvalidArchivePeriodError{Period: "2024/13"}, err, "incorrect error type")
+}
+
+// TestArchiveService_GetArchivePostsPage_Invalid_Page tests getting an invalid page of the posts of a period.
+func TestArchiveService_GetArchivePostsPage_Invalid_Page(t *testing.T) {
+	t.Parallel()
+	c := createArchiveServiceContext(t)
+
+	_, _, err := c.sut.GetArchivePostsPage(2024, 3, 0)
+
+	assert.Equal(t, errortypes.InvalidPostPageError{Page: 0}, err, "incorrect error type")
+}
@@ -0,0 +1,192 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=category.go -destination=../mocks/mock_category_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+)
+
+// CategoryService interface. Defines category-related business logic.
+type CategoryService interface {
+	AddCategory(newCategory repository.Category) (repository.Category, error)
+	UpdateCategory(updatedCategory repository.Category) (repository.Category, error)
+	DeleteCategory(urlHandle string) error
+	GetCategory(urlHandle string) (repository.Category, error)
+	GetCategories() ([]repository.Category, error)
+}
+
+// categoryService is the concrete implementation of the CategoryService interface.
+type categoryService struct {
+	cont container.Container
+}
+
+// CreateCategoryService instantiates the categoryService using the application container.
+func CreateCategoryService(cont container.Container) CategoryService {
+	return &categoryService{cont}
+}
+
+// AddCategory adds a new category to the blog.
+// If the parent of the new category is set, only its URL handle is considered.
+func (s categoryService) AddCategory(newCategory repository.Category) (repository.Category, error) {
+	log := s.cont.GetLogger()
+	categoryRepository := s.cont.GetCategoryRepository()
+
+	if newCategory.Name == "" {
+		log.Errorf("missing name of category %s", newCategory.URLHandle)
+		return repository.Category{}, errortypes.MissingCategoryNameError{}
+	}
+
+	if newCategory.Parent != nil {
+		parent, err := categoryRepository.GetCategory(newCategory.Parent.URLHandle)
+		if err != nil {
+			log.Errorf("failed to get parent %s of category %s", newCategory.Parent.URLHandle, newCategory.URLHandle)
+			return repository.Category{}, err
+		}
+		newCategory.ParentID = &parent.ID
+		newCategory.Parent = nil
+	}
+
+	log.Infof("adding new category %v", newCategory)
+	return categoryRepository.AddCategory(newCategory)
+}
+
+// UpdateCategory updates the name or the parent of an existing category.
+// An empty name leaves the name unchanged. A nil parent leaves the parent unchanged,
+// while a parent with an empty URL handle moves the category to the top level.
+func (s categoryService) UpdateCategory(updatedCategory repository.Category) (repository.Category, error) {
+	log := s.cont.GetLogger()
+	categoryRepository := s.cont.GetCategoryRepository()
+
+	category, err := categoryRepository.GetCategory(updatedCategory.URLHandle)
+	if err != nil {
+		return repository.Category{}, err
+	}
+
+	if updatedCategory.Name != "" {
+		category.Name = updatedCategory.Name
+	}
+
+	if updatedCategory.Parent != nil {
+		parentID, err := s.resolveParent(category, updatedCategory.Parent.URLHandle)
+		if err != nil {
+			return repository.Category{}, err
+		}
+		category.ParentID = parentID
+	}
+
+	log.Infof("updating category %v", category)
+	return categoryRepository.UpdateCategory(repository.Category{
+		URLHandle: category.URLHandle,
+		Name:      category.Name,
+		ParentID:  category.ParentID,
+	})
+}
+
+// resolveParent returns the ID of the new parent of the category.
+// The parent must not be the category itself or one of its descendants.
+func (s categoryService) resolveParent(category repository.Category, parentHandle string) (*uint, error) {
+	categoryRepository := s.cont.GetCategoryRepository()
+
+	if parentHandle == "" {
+		return nil, nil
+	}
+
+	categories, err := categoryRepository.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]repository.Category, len(categories))
+	var parent *repository.Category
+
+	for i := range categories {
+		byID[categories[i].ID] = categories[i]
+		if categories[i].URLHandle == parentHandle {
+			parent = &categories[i]
+		}
+	}
+
+	if parent == nil {
+		return nil, errortypes.CategoryNotFoundError{URLHandle: parentHandle}
+	}
+
+	// Walk up from the new parent, the category must not be among its ancestors
+	for ancestor := parent; ancestor != nil; {
+		if ancestor.ID == category.ID {
+			return nil, errortypes.InvalidCategoryParentError{URLHandle: category.URLHandle, Parent: parentHandle}
+		}
+
+		next, found := byID[derefID(ancestor.ParentID)]
+		if !found {
+			break
+		}
+		ancestor = &next
+	}
+
+	return &parent.ID, nil
+}
+
+// derefID returns the ID the pointer points to or 0 if it is nil.
+func derefID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+// DeleteCategory deletes a category of the blog. Categories with subcategories can't be deleted.
+func (s categoryService) DeleteCategory(urlHandle string) error {
+	log := s.cont.GetLogger()
+	categoryRepository := s.cont.GetCategoryRepository()
+
+	category, err := categoryRepository.GetCategory(urlHandle)
+	if err != nil {
+		return err
+	}
+
+	if len(category.Children) > 0 {
+		log.Errorf("category %s still has %d subcategories", urlHandle, len(category.Children))
+		return errortypes.CategoryNotEmptyError{URLHandle: urlHandle}
+	}
+
+	log.Infof("deleting category %s", urlHandle)
+	return categoryRepository.DeleteCategory(urlHandle)
+}
+
+// GetCategory retrieves the category with the given URL handle together with its parent and its direct children.
+func (s categoryService) GetCategory(urlHandle string) (repository.Category, error) {
+	categoryRepository := s.cont.GetCategoryRepository()
+	return categoryRepository.GetCategory(urlHandle)
+}
+
+// GetCategories retrieves the category tree of the blog.
+// The top level categories are returned, each of them holding its subcategories recursively.
+func (s categoryService) GetCategories() ([]repository.Category, error) {
+	categoryRepository := s.cont.GetCategoryRepository()
+
+	categories, err := categoryRepository.GetCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]repository.Category, len(categories))
+	for _, category := range categories {
+		children[derefID(category.ParentID)] = append(children[derefID(category.ParentID)], category)
+	}
+
+	return buildCategoryTree(children, 0), nil
+}
+
+// buildCategoryTree assembles the subtree below the category with the given ID. ID 0 stands for the top level.
+func buildCategoryTree(children map[uint][]repository.Category, parentID uint) []repository.Category {
+	tree := make([]repository.Category, 0, len(children[parentID]))
+
+	for _, category := range children[parentID] {
+		category.Children = buildCategoryTree(children, category.ID)
+		tree = append(tree, category)
+	}
+
+	return tree
+}
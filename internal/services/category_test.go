@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// categoryTestContext contains objects relevant for testing the CategoryService.
+type categoryTestContext struct {
+	mockCategoryRepository *mocks.MockCategoryRepository
+	sut                    services.CategoryService
+}
+
+// createCategoryServiceContext creates the context for testing the CategoryService and reduces code duplication.
+func createCategoryServiceContext(t *testing.T) *categoryTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockCategoryRepository := mocks.NewMockCategoryRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, mockCategoryRepository, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := services.CreateCategoryService(cont)
+
+	return &categoryTestContext{mockCategoryRepository, sut}
+}
+
+// categoryTree returns the categories Engineering > Go > Performance as they are stored in the database.
+func categoryTree() []repository.Category {
+	engineeringID := uint(1)
+	goID := uint(2)
+
+	return []repository.Category{
+		{ID: 1, URLHandle: "engineering", Name: "Engineering"},
+		{ID: 2, URLHandle: "go", Name: "Go", ParentID: &engineeringID},
+		{ID: 3, URLHandle: "performance", Name: "Performance", ParentID: &goID},
+	}
+}
+
+// TestCategoryService_AddCategory tests adding a new category below an existing parent.
+func TestCategoryService_AddCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	parentID := uint(1)
+	newCategory := repository.Category{
+		URLHandle: "go",
+		Name:      "Go",
+		Parent:    &repository.Category{URLHandle: "engineering"},
+	}
+	expectedCategory := repository.Category{
+		URLHandle: "go",
+		Name:      "Go",
+		ParentID:  &parentID,
+	}
+
+	c.mockCategoryRepository.EXPECT().GetCategory("engineering").Return(repository.Category{ID: parentID}, nil)
+	c.mockCategoryRepository.EXPECT().AddCategory(expectedCategory).Return(expectedCategory, nil)
+
+	category, err := c.sut.AddCategory(newCategory)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedCategory, category, "category doesn't match the expected one")
+}
+
+// TestCategoryService_AddCategory_Missing_Name tests adding a category without a name.
+func TestCategoryService_AddCategory_Missing_Name(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	_, err := c.sut.AddCategory(repository.Category{URLHandle: "go"})
+
+	assert.Equal(t, errortypes.MissingCategoryNameError{}, err, "error doesn't match expected one")
+}
+
+// TestCategoryService_UpdateCategory tests moving a category to the top level.
+func TestCategoryService_UpdateCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	current := categoryTree()[1]
+	expectedCategory := repository.Category{URLHandle: "go", Name: "Golang"}
+
+	c.mockCategoryRepository.EXPECT().GetCategory("go").Return(current, nil)
+	c.mockCategoryRepository.EXPECT().UpdateCategory(expectedCategory).Return(expectedCategory, nil)
+
+	_, err := c.sut.UpdateCategory(repository.Category{
+		URLHandle: "go",
+		Name:      "Golang",
+		Parent:    &repository.Category{},
+	})
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestCategoryService_UpdateCategory_Descendant_Parent tests moving a category below its own descendant.
+func TestCategoryService_UpdateCategory_Descendant_Parent(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	categories := categoryTree()
+	expectedError := errortypes.InvalidCategoryParentError{URLHandle: "engineering", Parent: "performance"}
+
+	c.mockCategoryRepository.EXPECT().GetCategory("engineering").Return(categories[0], nil)
+	c.mockCategoryRepository.EXPECT().GetCategories().Return(categories, nil)
+
+	_, err := c.sut.UpdateCategory(repository.Category{
+		URLHandle: "engineering",
+		Parent:    &repository.Category{URLHandle: "performance"},
+	})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestCategoryService_UpdateCategory_Missing_Parent tests moving a category below a non-existing parent.
+func TestCategoryService_UpdateCategory_Missing_Parent(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	categories := categoryTree()
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "rust"}
+
+	c.mockCategoryRepository.EXPECT().GetCategory("go").Return(categories[1], nil)
+	c.mockCategoryRepository.EXPECT().GetCategories().Return(categories, nil)
+
+	_, err := c.sut.UpdateCategory(repository.Category{
+		URLHandle: "go",
+		Parent:    &repository.Category{URLHandle: "rust"},
+	})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestCategoryService_DeleteCategory tests deleting a category without subcategories.
+func TestCategoryService_DeleteCategory(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	c.mockCategoryRepository.EXPECT().GetCategory("performance").Return(categoryTree()[2], nil)
+	c.mockCategoryRepository.EXPECT().DeleteCategory("performance").Return(nil)
+
+	err := c.sut.DeleteCategory("performance")
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestCategoryService_DeleteCategory_Not_Empty tests deleting a category with subcategories.
+func TestCategoryService_DeleteCategory_Not_Empty(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	category := categoryTree()[0]
+	category.Children = []repository.Category{categoryTree()[1]}
+
+	c.mockCategoryRepository.EXPECT().GetCategory("engineering").Return(category, nil)
+
+	err := c.sut.DeleteCategory("engineering")
+
+	assert.Equal(t, errortypes.CategoryNotEmptyError{URLHandle: "engineering"}, err, "error doesn't match expected one")
+}
+
+// TestCategoryService_GetCategories tests building the category tree.
+func TestCategoryService_GetCategories(t *testing.T) {
+	t.Parallel()
+	c := createCategoryServiceContext(t)
+
+	categories := categoryTree()
+
+	c.mockCategoryRepository.EXPECT().GetCategories().Return(categories, nil)
+
+	tree, err := c.sut.GetCategories()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 1, len(tree), "expected a single top level category")
+	assert.Equal(t, "engineering", tree[0].URLHandle, "incorrect top level category")
+	assert.Equal(t, "go", tree[0].Children[0].URLHandle, "incorrect subcategory")
+	assert.Equal(t, "performance", tree[0].Children[0].Children[0].URLHandle, "incorrect nested subcategory")
+	assert.Equal(t, 0, len(tree[0].Children[0].Children[0].Children), "leaf category shouldn't have children")
+}
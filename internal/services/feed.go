@@ -0,0 +1,179 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=feed.go -destination=../mocks/mock_feed_service.go -package=mocks
+
+import (
+	"fmt"
+	"github.com/gorilla/feeds"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"os"
+	"strconv"
+)
+
+// FeedContent selects how much of the posts is included in feeds.
+type FeedContent string
+
+const (
+	// FeedContentFull includes the whole body of the posts rendered to HTML
+	FeedContentFull FeedContent = "full"
+	// FeedContentSummary only includes the summary or the excerpt of the posts
+	FeedContentSummary FeedContent = "summary"
+)
+
+// FeedOptions selects the posts of a feed. Empty fields don't restrict the posts, an empty content falls back to
+// the configured content.
+type FeedOptions struct {
+	Author  string
+	Tag     string
+	Content FeedContent
+}
+
+// FeedService interface. Defines the business logic of the post feeds.
+type FeedService interface {
+	GetFeed(options FeedOptions) (*feeds.Feed, error)
+}
+
+// feedService is the concrete implementation of the FeedService interface.
+type feedService struct {
+	cont     container.Container
+	site     Site
+	renderer render.Renderer
+	size     int
+	content  FeedContent
+}
+
+// defaultFeedSize sets the number of posts in feeds if FEED_SIZE is not set
+const defaultFeedSize = 20
+
+// defaultFeedContent sets how much of the posts is included in feeds if FEED_CONTENT is not set
+const defaultFeedContent = FeedContentFull
+
+// CreateFeedService instantiates the feedService using the application container.
+func CreateFeedService(cont container.Container) FeedService {
+	return &feedService{cont, loadSite(cont), loadRenderer(cont), loadFeedSize(cont), loadFeedContent(cont)}
+}
+
+// loadFeedSize reads the number of posts in feeds from the FEED_SIZE environment variable.
+// If the variable is missing or invalid, the default size is used.
+func loadFeedSize(cont container.Container) int {
+	log := cont.GetLogger()
+
+	value := os.Getenv("FEED_SIZE")
+	if value == "" {
+		return defaultFeedSize
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 {
+		log.Errorf("invalid FEED_SIZE value \"%s\", falling back to %d", value, defaultFeedSize)
+		return defaultFeedSize
+	}
+
+	return size
+}
+
+// loadFeedContent reads how much of the posts is included in feeds from the FEED_CONTENT environment variable.
+// If the variable is missing or invalid, the default content is used.
+func loadFeedContent(cont container.Container) FeedContent {
+	log := cont.GetLogger()
+
+	value := FeedContent(os.Getenv("FEED_CONTENT"))
+	switch value {
+	case FeedContentFull, FeedContentSummary:
+		return value
+	case "":
+		return defaultFeedContent
+	default:
+		log.Errorf("invalid FEED_CONTENT value \"%s\", falling back to %s", value, defaultFeedContent)
+		return defaultFeedContent
+	}
+}
+
+// GetFeed builds the feed of the most recently published posts, optionally restricted to an author or a tag.
+// The feed is updated when its most recently updated post was.
+func (f feedService) GetFeed(options FeedOptions) (*feeds.Feed, error) {
+	log := f.cont.GetLogger()
+	postRepository := f.cont.GetPostRepository()
+	userRepository := f.cont.GetUserRepository()
+
+	content := options.Content
+	if content == "" {
+		content = f.content
+	}
+
+	if content != FeedContentFull && content != FeedContentSummary {
+		log.Errorf("invalid feed content %s", content)
+		return nil, errortypes.InvalidFeedContentError{Content: string(content)}
+	}
+
+	feed := &feeds.Feed{
+		Title:       f.site.Title,
+		Link:        &feeds.Link{Href: f.site.URL},
+		Description: f.site.Description,
+	}
+	filter := repository.PostFilter{Tag: NormalizeTagName(options.Tag)}
+
+	if options.Author != "" {
+		author, err := userRepository.GetUser(options.Author)
+		if err != nil {
+			return nil, err
+		}
+
+		filter.AuthorID = author.ID
+		feed.Title = fmt.Sprintf("%s - %s", feed.Title, author.UserName)
+	}
+
+	if filter.Tag != "" {
+		feed.Title = fmt.Sprintf("%s - #%s", feed.Title, filter.Tag)
+	}
+
+	posts, err := postRepository.GetLatestPosts(filter, f.size)
+	if err != nil {
+		log.Errorf("failed to get posts of feed: %v", err)
+		return nil, err
+	}
+
+	for _, post := range posts {
+		feed.Add(f.feedItem(post, content))
+
+		if post.UpdatedAt.After(feed.Updated) {
+			feed.Updated = post.UpdatedAt
+		}
+	}
+
+	return feed, nil
+}
+
+// feedItem maps a post to a feed item. Full content items contain the body of the post rendered to HTML.
+func (f feedService) feedItem(post repository.Post, content FeedContent) *feeds.Item {
+	link := f.site.PostURL(post.URLHandle)
+
+	item := &feeds.Item{
+		Id:      link,
+		Title:   derefString(post.Title),
+		Link:    &feeds.Link{Href: link},
+		Author:  &feeds.Author{Name: post.Author.UserName},
+		Created: post.CreatedAt,
+		Updated: post.UpdatedAt,
+	}
+
+	if post.PublishedAt != nil {
+		item.Created = *post.PublishedAt
+	}
+
+	if post.Summary != nil && *post.Summary != "" {
+		item.Description = *post.Summary
+	} else {
+		item.Description = derefString(post.Excerpt)
+	}
+
+	if content == FeedContentFull {
+		renderPost(f.cont, f.renderer, &post)
+		item.Content = derefString(post.BodyHTML)
+	}
+
+	return item
+}
@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// feedTestContext contains objects relevant for testing the FeedService.
+type feedTestContext struct {
+	mockPostRepository     *mocks.MockPostRepository
+	mockUserRepository     *mocks.MockUserRepository
+	mockRevisionRepository *mocks.MockRevisionRepository
+	sut                    services.FeedService
+}
+
+// createFeedServiceContext creates the context for testing the FeedService and reduces code duplication.
+func createFeedServiceContext(t *testing.T) *feedTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
+	mockRevisionRepository := mocks.NewMockRevisionRepository(mockCtrl)
+	cont := container.CreateContainer(
+		logger.CreateLogger(),
+		mockPostRepository,
+		mockUserRepository,
+		nil,
+		nil,
+		nil,
+		mockRevisionRepository,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	sut := services.CreateFeedService(cont)
+
+	return &feedTestContext{mockPostRepository, mockUserRepository, mockRevisionRepository, sut}
+}
+
+// TestFeedService_GetFeed tests building the feed of the most recent posts with their full content.
+func TestFeedService_GetFeed(t *testing.T) {
+	t.Parallel()
+	c := createFeedServiceContext(t)
+
+	title := "testTitle"
+	body := "*testBody*"
+	excerpt := "testBody"
+	publishedAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	posts := []repository.Post{
+		{
+			ID:          1,
+			URLHandle:   "first post",
+			Title:       &title,
+			Body:        &body,
+			Excerpt:     &excerpt,
+			Author:      repository.User{UserName: "testAuthor"},
+			PublishedAt: &publishedAt,
+			UpdatedAt:   updatedAt,
+		},
+		{ID: 2, URLHandle: "second", Title: &title, UpdatedAt: publishedAt},
+	}
+
+	c.mockPostRepository.EXPECT().GetLatestPosts(repository.PostFilter{}, 20).Return(posts, nil)
+	c.mockRevisionRepository.EXPECT().GetLatestRevision(uint(1)).Return(repository.Revision{}, fmt.Errorf("error"))
+
+	feed, err := c.sut.GetFeed(services.FeedOptions{})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "Blog", feed.Title, "incorrect feed title")
+	assert.Equal(t, updatedAt, feed.Updated, "feed should be updated with its latest post")
+	assert.Equal(t, 2, len(feed.Items), "incorrect number of items")
+	assert.Equal(t, "http://localhost:8080/posts/first%20post", feed.Items[0].Link.Href, "incorrect item link")
+	assert.Equal(t, "testAuthor", feed.Items[0].Author.Name, "incorrect item author")
+	assert.Equal(t, publishedAt, feed.Items[0].Created, "items should be dated by their publication")
+	assert.Equal(t, updatedAt, feed.Items[0].Updated, "incorrect item update time")
+	assert.Equal(t, excerpt, feed.Items[0].Description, "items without summary should be described by their excerpt")
+	assert.Equal(t, "<p><em>testBody</em></p>\n", feed.Items[0].Content, "incorrect item content")
+}
+
+// TestFeedService_GetFeed_Summary tests building a feed of an author and a tag without the content of the posts.
+func TestFeedService_GetFeed_Summary(t *testing.T) {
+	t.Parallel()
+	c := createFeedServiceContext(t)
+
+	summary := "testSummary"
+	body := "testBody"
+	author := repository.User{ID: 3, UserName: "testAuthor"}
+	posts := []repository.Post{{ID: 1, Summary: &summary, Body: &body}}
+
+	c.mockUserRepository.EXPECT().GetUser("testAuthor").Return(author, nil)
+	c.mockPostRepository.EXPECT().GetLatestPosts(repository.PostFilter{AuthorID: 3, Tag: "go"}, 20).Return(posts, nil)
+
+	feed, err := c.sut.GetFeed(services.FeedOptions{Author: "testAuthor", Tag: "Go", Content: services.FeedContentSummary})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "Blog - testAuthor - #go", feed.Title, "incorrect feed title")
+	assert.Equal(t, summary, feed.Items[0].Description, "items should be described by their summary")
+	assert.Empty(t, feed.Items[0].Content, "summary feeds shouldn't contain the body")
+}
+
+// TestFeedService_GetFeed_Missing_Author tests building the feed of an author that doesn't exist.
+func TestFeedService_GetFeed_Missing_Author(t *testing.T) {
+	t.Parallel()
+	c := createFeedServiceContext(t)
+
+	expectedError := errortypes.UserNotFoundError{UserName: "testAuthor"}
+
+	c.mockUserRepository.EXPECT().GetUser("testAuthor").Return(repository.User{}, expectedError)
+
+	_, err := c.sut.GetFeed(services.FeedOptions{Author: "testAuthor"})
+
+	assert.Equal(t, expectedError, err, "incorrect error type")
+}
+
+// TestFeedService_GetFeed_Invalid_Content tests building a feed with an unknown content mode.
+func TestFeedService_GetFeed_Invalid_Content(t *testing.T) {
+	t.Parallel()
+	c := createFeedServiceContext(t)
+
+	_, err := c.sut.GetFeed(services.FeedOptions{Content: "partial"})
+
+	assert.Equal(t, errortypes.InvalidFeedContentError{Content: "partial"}, err, "incorrect error type")
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"fmt"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MetaType is the type of a custom post metadata field
+type MetaType string
+
+const (
+	// MetaTypeString accepts any text
+	MetaTypeString MetaType = "string"
+	// MetaTypeNumber accepts integer and floating point numbers
+	MetaTypeNumber MetaType = "number"
+	// MetaTypeBool accepts true and false
+	MetaTypeBool MetaType = "bool"
+	// MetaTypeDate accepts dates such as "2023-11-21" and timestamps in RFC 3339 format
+	MetaTypeDate MetaType = "date"
+	// MetaTypeURL accepts absolute http and https URLs
+	MetaTypeURL MetaType = "url"
+)
+
+// MetaSchema maps the names of the allowed post metadata fields to their types.
+type MetaSchema map[string]MetaType
+
+// defaultMetaSchema is used if POST_META_SCHEMA is not set
+const defaultMetaSchema = "canonicalUrl:url,coverImage:url,license:string,originallyPublishedAt:date"
+
+// metaKeyPattern restricts the names of the metadata fields
+var metaKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,63}$`)
+
+// ParseMetaSchema parses a comma-separated list of name:type pairs such as "license:string,featured:bool".
+func ParseMetaSchema(definition string) (MetaSchema, error) {
+	schema := MetaSchema{}
+
+	for _, entry := range strings.Split(definition, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, metaType, found := strings.Cut(entry, ":")
+		key = strings.TrimSpace(key)
+		metaType = strings.TrimSpace(metaType)
+
+		if !found || !metaKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid metadata field definition \"%s\"", entry)
+		}
+
+		switch t := MetaType(metaType); t {
+		case MetaTypeString, MetaTypeNumber, MetaTypeBool, MetaTypeDate, MetaTypeURL:
+			schema[key] = t
+		default:
+			return nil, fmt.Errorf("unknown type \"%s\" of metadata field \"%s\"", metaType, key)
+		}
+	}
+
+	return schema, nil
+}
+
+// loadMetaSchema reads the metadata schema from the POST_META_SCHEMA environment variable.
+// If the variable is missing or invalid, the default schema is used.
+func loadMetaSchema(cont container.Container) MetaSchema {
+	log := cont.GetLogger()
+
+	definition, found := os.LookupEnv("POST_META_SCHEMA")
+	if !found {
+		definition = defaultMetaSchema
+	}
+
+	schema, err := ParseMetaSchema(definition)
+	if err != nil {
+		log.Errorf("invalid POST_META_SCHEMA value \"%s\", falling back to the default schema: %v", definition, err)
+		schema, _ = ParseMetaSchema(defaultMetaSchema)
+	}
+
+	return schema
+}
+
+// Validate checks the metadata of a post against the schema and returns it in canonical form.
+// Fields with null values are dropped. A nil map is returned unchanged, so that the metadata of the post stays untouched.
+func (s MetaSchema) Validate(meta map[string]interface{}) (map[string]interface{}, error) {
+	if meta == nil {
+		return nil, nil
+	}
+
+	validated := make(map[string]interface{}, len(meta))
+
+	for key, value := range meta {
+		if value == nil {
+			continue
+		}
+
+		metaType, found := s[key]
+		if !found {
+			return nil, errortypes.InvalidPostMetaError{Key: key, Reason: "unknown field"}
+		}
+
+		v, err := metaType.canonicalValue(value)
+		if err != nil {
+			return nil, errortypes.InvalidPostMetaError{Key: key, Reason: err.Error()}
+		}
+
+		validated[key] = v
+	}
+
+	return validated, nil
+}
+
+// NormalizeFilter checks the metadata filter of a post query against the schema.
+// The filter values are converted to the text form the stored values are compared with.
+func (s MetaSchema) NormalizeFilter(filter map[string]string) (map[string]string, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+
+	normalized := make(map[string]string, len(filter))
+
+	for key, value := range filter {
+		metaType, found := s[key]
+		if !found {
+			return nil, errortypes.InvalidPostMetaError{Key: key, Reason: "unknown field"}
+		}
+
+		var parsed interface{} = value
+
+		switch metaType {
+		case MetaTypeNumber:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, errortypes.InvalidPostMetaError{Key: key, Reason: "expected a number"}
+			}
+			parsed = n
+		case MetaTypeBool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, errortypes.InvalidPostMetaError{Key: key, Reason: "expected a boolean"}
+			}
+			parsed = b
+		}
+
+		v, err := metaType.canonicalValue(parsed)
+		if err != nil {
+			return nil, errortypes.InvalidPostMetaError{Key: key, Reason: err.Error()}
+		}
+
+		// Large numbers are written in full, as fmt would switch to exponent notation, e.g. 1e+06
+		if n, ok := v.(float64); ok {
+			normalized[key] = strconv.FormatFloat(n, 'f', -1, 64)
+		} else {
+			normalized[key] = fmt.Sprint(v)
+		}
+	}
+
+	return normalized, nil
+}
+
+// canonicalValue checks whether the value matches the type and brings it to its canonical form.
+func (t MetaType) canonicalValue(value interface{}) (interface{}, error) {
+	switch t {
+	case MetaTypeNumber:
+		switch n := value.(type) {
+		case float64:
+			return n, nil
+		case int:
+			return float64(n), nil
+		}
+		return nil, fmt.Errorf("expected a number")
+	case MetaTypeBool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected a boolean")
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string")
+	}
+
+	switch t {
+	case MetaTypeDate:
+		if date, err := time.Parse(time.DateOnly, text); err == nil {
+			return date.Format(time.DateOnly), nil
+		}
+		if timestamp, err := time.Parse(time.RFC3339, text); err == nil {
+			return timestamp.UTC().Format(time.RFC3339), nil
+		}
+		return nil, fmt.Errorf("expected a date such as \"2023-11-21\" or an RFC 3339 timestamp")
+	case MetaTypeURL:
+		u, err := url.Parse(text)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("expected an absolute http or https URL")
+		}
+		return text, nil
+	}
+
+	return text, nil
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/services"
+	"testing"
+)
+
+// TestParseMetaSchema tests parsing a metadata schema definition.
+func TestParseMetaSchema(t *testing.T) {
+	t.Parallel()
+
+	expectedSchema := services.MetaSchema{
+		"license":  services.MetaTypeString,
+		"featured": services.MetaTypeBool,
+		"rating":   services.MetaTypeNumber,
+	}
+
+	schema, err := services.ParseMetaSchema(" license:string, featured:bool,rating:number,")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedSchema, schema, "schema doesn't match the expected one")
+}
+
+// TestParseMetaSchema_Invalid_Definition tests parsing invalid metadata schema definitions.
+func TestParseMetaSchema_Invalid_Definition(t *testing.T) {
+	t.Parallel()
+
+	_, err := services.ParseMetaSchema("license")
+	assert.NotNil(t, err, "field without type should be rejected")
+
+	_, err = services.ParseMetaSchema("license:text")
+	assert.NotNil(t, err, "unknown type should be rejected")
+
+	_, err = services.ParseMetaSchema("$license:string")
+	assert.NotNil(t, err, "invalid field name should be rejected")
+}
+
+// TestMetaSchema_Validate tests bringing valid metadata to its canonical form.
+func TestMetaSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	schema := services.MetaSchema{
+		"license":     services.MetaTypeString,
+		"featured":    services.MetaTypeBool,
+		"rating":      services.MetaTypeNumber,
+		"publishedAt": services.MetaTypeDate,
+		"updatedAt":   services.MetaTypeDate,
+		"canonical":   services.MetaTypeURL,
+	}
+	meta := map[string]interface{}{
+		"license":     "cc-by",
+		"featured":    true,
+		"rating":      4.5,
+		"publishedAt": "2023-11-21",
+		"updatedAt":   "2023-11-21T10:00:00+02:00",
+		"canonical":   "https://example.com/post",
+		"removed":     nil,
+	}
+	expectedMeta := map[string]interface{}{
+		"license":     "cc-by",
+		"featured":    true,
+		"rating":      4.5,
+		"publishedAt": "2023-11-21",
+		"updatedAt":   "2023-11-21T08:00:00Z",
+		"canonical":   "https://example.com/post",
+	}
+
+	result, err := schema.Validate(meta)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedMeta, result, "metadata doesn't match the expected one")
+}
+
+// TestMetaSchema_Validate_Invalid_Values tests rejecting metadata that doesn't match the schema.
+func TestMetaSchema_Validate_Invalid_Values(t *testing.T) {
+	t.Parallel()
+
+	schema := services.MetaSchema{
+		"featured":  services.MetaTypeBool,
+		"rating":    services.MetaTypeNumber,
+		"published": services.MetaTypeDate,
+		"canonical": services.MetaTypeURL,
+	}
+
+	invalidMeta := []map[string]interface{}{
+		{"unknown": "value"},
+		{"featured": "yes"},
+		{"rating": "5"},
+		{"published": "yesterday"},
+		{"canonical": "/relative/path"},
+		{"canonical": 42.0},
+	}
+
+	for _, meta := range invalidMeta {
+		_, err := schema.Validate(meta)
+		assert.IsType(t, errortypes.InvalidPostMetaError{}, err, "invalid metadata %v should be rejected", meta)
+	}
+}
+
+// TestMetaSchema_NormalizeFilter tests converting metadata filter values to their stored text form.
+func TestMetaSchema_NormalizeFilter(t *testing.T) {
+	t.Parallel()
+
+	schema := services.MetaSchema{
+		"license":  services.MetaTypeString,
+		"featured": services.MetaTypeBool,
+		"rating":   services.MetaTypeNumber,
+	}
+	expectedFilter := map[string]string{
+		"license":  "cc-by",
+		"featured": "true",
+		"rating":   "4",
+	}
+
+	filter, err := schema.NormalizeFilter(map[string]string{"license": "cc-by", "featured": "1", "rating": "4.0"})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedFilter, filter, "filter doesn't match the expected one")
+
+	_, err = schema.NormalizeFilter(map[string]string{"rating": "high"})
+	assert.Equal(t, errortypes.InvalidPostMetaError{Key: "rating", Reason: "expected a number"}, err, "error doesn't match expected one")
+}
+
+// TestMetaSchema_NormalizeFilter_Large_Number tests that large numbers are not converted to exponent notation.
+func TestMetaSchema_NormalizeFilter_Large_Number(t *testing.T) {
+	t.Parallel()
+
+	schema := services.MetaSchema{"views": services.MetaTypeNumber}
+
+	filter, err := schema.NormalizeFilter(map[string]string{"views": "1000000"})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, map[string]string{"views": "1000000"}, filter, "filter doesn't match the expected one")
+
+	filter, err = schema.NormalizeFilter(map[string]string{"views": "1e21"})
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, map[string]string{"views": "1000000000000000000000"}, filter, "filter doesn't match the expected one")
+}
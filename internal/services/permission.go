@@ -0,0 +1,119 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=permission.go -destination=../mocks/mock_permission_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+)
+
+// Actor is the authenticated user performing an operation, along with the role carried by their token.
+type Actor struct {
+	UserName string
+	Role     repository.Role
+}
+
+// PermissionService interface. Decides whether an authenticated user is allowed to perform an operation.
+// Admins may do anything, editors may modify any post, category and series, and authors may only modify their own posts.
+type PermissionService interface {
+	CheckUserManagement(actor Actor) error
+	CheckUserUpdate(actor Actor, userID string) error
+	CheckTwoFactorEnrolment(actor Actor, userID string) error
+	CheckPostManagement(actor Actor) error
+	CheckTaxonomyManagement(actor Actor) error
+	CheckPostModification(actor Actor, postID string) error
+}
+
+// permissionService is the concrete implementation of the PermissionService interface.
+type permissionService struct {
+	cont container.Container
+}
+
+// CreatePermissionService instantiates the permissionService using the application container.
+func CreatePermissionService(cont container.Container) PermissionService {
+	return &permissionService{cont}
+}
+
+// CheckUserManagement makes sure the actor is allowed to add, delete or change the role of users.
+func (p permissionService) CheckUserManagement(actor Actor) error {
+	log := p.cont.GetLogger()
+
+	if actor.Role != repository.RoleAdmin {
+		log.Debugf("user %s with role \"%s\" is not allowed to manage users", actor.UserName, actor.Role)
+		return errortypes.PermissionDeniedError{UserName: actor.UserName}
+	}
+
+	return nil
+}
+
+// CheckUserUpdate makes sure the actor is allowed to update the given user.
+// Users may update themselves, while admins may update anyone.
+func (p permissionService) CheckUserUpdate(actor Actor, userID string) error {
+	if actor.UserName == userID {
+		return nil
+	}
+
+	return p.CheckUserManagement(actor)
+}
+
+// CheckTwoFactorEnrolment makes sure the actor is allowed to enrol the given user in two-factor authentication.
+// The TOTP secret is only meant for the user, so not even admins may enrol others.
+func (p permissionService) CheckTwoFactorEnrolment(actor Actor, userID string) error {
+	log := p.cont.GetLogger()
+
+	if actor.UserName != userID {
+		log.Debugf("user %s is not allowed to enrol user %s in two-factor authentication", actor.UserName, userID)
+		return errortypes.PermissionDeniedError{UserName: actor.UserName}
+	}
+
+	return nil
+}
+
+// CheckPostManagement makes sure the actor is allowed to modify the posts of every author.
+func (p permissionService) CheckPostManagement(actor Actor) error {
+	log := p.cont.GetLogger()
+
+	if actor.Role != repository.RoleAdmin && actor.Role != repository.RoleEditor {
+		log.Debugf("user %s with role \"%s\" is not allowed to manage the posts of others", actor.UserName, actor.Role)
+		return errortypes.PermissionDeniedError{UserName: actor.UserName}
+	}
+
+	return nil
+}
+
+// CheckTaxonomyManagement makes sure the actor is allowed to add, modify or delete categories and series.
+// They are shared by every post, so only admins and editors may change them.
+func (p permissionService) CheckTaxonomyManagement(actor Actor) error {
+	log := p.cont.GetLogger()
+
+	if actor.Role != repository.RoleAdmin && actor.Role != repository.RoleEditor {
+		log.Debugf("user %s with role \"%s\" is not allowed to manage categories and series", actor.UserName, actor.Role)
+		return errortypes.PermissionDeniedError{UserName: actor.UserName}
+	}
+
+	return nil
+}
+
+// CheckPostModification makes sure the actor is allowed to modify the given post.
+// Admins and editors may modify any post, while authors are restricted to the ones they wrote.
+func (p permissionService) CheckPostModification(actor Actor, postID string) error {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	if actor.Role == repository.RoleAdmin || actor.Role == repository.RoleEditor {
+		return nil
+	}
+
+	author, err := postRepository.GetPostAuthor(postID)
+	if err != nil {
+		return err
+	}
+
+	if author.UserName != actor.UserName {
+		log.Debugf("user %s is not allowed to modify post %s of user %s", actor.UserName, postID, author.UserName)
+		return errortypes.PermissionDeniedError{UserName: actor.UserName}
+	}
+
+	return nil
+}
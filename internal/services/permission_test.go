@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// permissionTestContext contains objects relevant for testing the PermissionService.
+type permissionTestContext struct {
+	mockPostRepository *mocks.MockPostRepository
+	sut                services.PermissionService
+}
+
+// createPermissionServiceContext creates the context for testing the PermissionService and reduces code duplication.
+func createPermissionServiceContext(t *testing.T) *permissionTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), mockPostRepository, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := services.CreatePermissionService(cont)
+
+	return &permissionTestContext{mockPostRepository, sut}
+}
+
+// TestPermissionService_CheckUserManagement tests that only admins may manage users.
+func TestPermissionService_CheckUserManagement(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	admin := services.Actor{UserName: "admin", Role: repository.RoleAdmin}
+	editor := services.Actor{UserName: "editor", Role: repository.RoleEditor}
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	assert.Nil(t, c.sut.CheckUserManagement(admin), "admins should be allowed to manage users")
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "editor"}, c.sut.CheckUserManagement(editor))
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "author"}, c.sut.CheckUserManagement(author))
+}
+
+// TestPermissionService_CheckUserUpdate tests that users may update themselves but not others.
+func TestPermissionService_CheckUserUpdate(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	admin := services.Actor{UserName: "admin", Role: repository.RoleAdmin}
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	assert.Nil(t, c.sut.CheckUserUpdate(author, "author"), "users should be allowed to update themselves")
+	assert.Nil(t, c.sut.CheckUserUpdate(admin, "author"), "admins should be allowed to update anyone")
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "author"}, c.sut.CheckUserUpdate(author, "admin"))
+}
+
+// TestPermissionService_CheckTwoFactorEnrolment tests that users may only enrol themselves, even if they are admins.
+func TestPermissionService_CheckTwoFactorEnrolment(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	admin := services.Actor{UserName: "admin", Role: repository.RoleAdmin}
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	assert.Nil(t, c.sut.CheckTwoFactorEnrolment(author, "author"), "users should be allowed to enrol themselves")
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "admin"}, c.sut.CheckTwoFactorEnrolment(admin, "author"))
+}
+
+// TestPermissionService_CheckPostManagement tests that only admins and editors may manage the posts of others.
+func TestPermissionService_CheckPostManagement(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	admin := services.Actor{UserName: "admin", Role: repository.RoleAdmin}
+	editor := services.Actor{UserName: "editor", Role: repository.RoleEditor}
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	assert.Nil(t, c.sut.CheckPostManagement(admin), "admins should be allowed to manage posts")
+	assert.Nil(t, c.sut.CheckPostManagement(editor), "editors should be allowed to manage posts")
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "author"}, c.sut.CheckPostManagement(author))
+}
+
+// TestPermissionService_CheckTaxonomyManagement tests that only admins and editors may manage categories and series.
+func TestPermissionService_CheckTaxonomyManagement(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	admin := services.Actor{UserName: "admin", Role: repository.RoleAdmin}
+	editor := services.Actor{UserName: "editor", Role: repository.RoleEditor}
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	assert.Nil(t, c.sut.CheckTaxonomyManagement(admin), "admins should be allowed to manage categories")
+	assert.Nil(t, c.sut.CheckTaxonomyManagement(editor), "editors should be allowed to manage categories")
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "author"}, c.sut.CheckTaxonomyManagement(author))
+}
+
+// TestPermissionService_CheckPostModification_Editor tests that editors may modify any post without a lookup.
+func TestPermissionService_CheckPostModification_Editor(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	editor := services.Actor{UserName: "editor", Role: repository.RoleEditor}
+
+	err := c.sut.CheckPostModification(editor, "testUrlHandle")
+
+	assert.Nil(t, err, "editors should be allowed to modify any post")
+}
+
+// TestPermissionService_CheckPostModification_Own_Post tests that authors may modify their own posts.
+func TestPermissionService_CheckPostModification_Own_Post(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	c.mockPostRepository.EXPECT().GetPostAuthor("testUrlHandle").Return(repository.User{UserName: "author"}, nil)
+
+	err := c.sut.CheckPostModification(author, "testUrlHandle")
+
+	assert.Nil(t, err, "authors should be allowed to modify their own posts")
+}
+
+// TestPermissionService_CheckPostModification_Foreign_Post tests that authors may not modify posts of others.
+func TestPermissionService_CheckPostModification_Foreign_Post(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+
+	c.mockPostRepository.EXPECT().GetPostAuthor("testUrlHandle").Return(repository.User{UserName: "other"}, nil)
+
+	err := c.sut.CheckPostModification(author, "testUrlHandle")
+
+	assert.Equal(t, errortypes.PermissionDeniedError{UserName: "author"}, err, "incorrect error type")
+}
+
+// TestPermissionService_CheckPostModification_Not_Found tests checking the permissions of a missing post.
+func TestPermissionService_CheckPostModification_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPermissionServiceContext(t)
+
+	author := services.Actor{UserName: "author", Role: repository.RoleAuthor}
+	expectedError := errortypes.PostNotFoundError{URLHandle: "testUrlHandle"}
+
+	c.mockPostRepository.EXPECT().GetPostAuthor("testUrlHandle").Return(repository.User{}, expectedError)
+
+	err := c.sut.CheckPostModification(author, "testUrlHandle")
+
+	assert.Equal(t, expectedError, err, "incorrect error type")
+}
@@ -3,36 +3,84 @@ package services
 //go:generate mockgen-v0.4.0 -source=post.go -destination=../mocks/mock_post_service.go -package=mocks
 
 import (
+	"fmt"
 	"github.com/wlachs/blog/internal/container"
 	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/render"
 	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/slug"
 	"math"
+	"os"
+	"strings"
+	"time"
 )
 
 // PostService interface. Defines post-related business logic.
 type PostService interface {
 	AddPost(newPost repository.Post, authorName string) (repository.Post, error)
-	UpdatePost(updatedPost repository.Post) (repository.Post, error)
+	UpdatePost(updatedPost repository.Post, editorName string) (repository.Post, error)
 	DeletePost(id string) error
+	RestorePost(id string) (repository.Post, error)
+	RenamePost(id string, newID string) (repository.Post, error)
+	GetTrash(authorName string) ([]repository.Post, int, error)
+	GetTrashPage(authorName string, page int) ([]repository.Post, int, error)
+	PurgeTrash() error
 	GetPost(id string) (repository.Post, error)
-	GetPosts() ([]repository.Post, int, error)
-	GetPostsPage(page int) ([]repository.Post, int, error)
+	GetPosts(filter repository.PostFilter) ([]repository.Post, int, error)
+	GetPostsPage(filter repository.PostFilter, page int) ([]repository.Post, int, error)
+	GetDraft(id string, authorName string) (repository.Post, error)
+	GetDrafts(authorName string) ([]repository.Post, int, error)
+	GetDraftsPage(authorName string, page int) ([]repository.Post, int, error)
+	PublishPost(id string) (repository.Post, error)
+	UnpublishPost(id string) (repository.Post, error)
+	ArchivePost(id string) (repository.Post, error)
+	ApplyPostSchedules() error
 }
 
 // postService is the concrete implementation of the PostService interface.
 type postService struct {
-	cont container.Container
+	cont           container.Container
+	metaSchema     MetaSchema
+	trashRetention time.Duration
+	renderer       render.Renderer
+	excerptLength  int
 }
 
 // postPageSize sets the pagination page size
 const postPageSize = 5
 
+// urlHandleAttempts limits how many generated URL handles are tried when adding a post
+const urlHandleAttempts = 3
+
+// defaultTrashRetention sets how long trashed posts are kept if TRASH_RETENTION is not set
+const defaultTrashRetention = 30 * 24 * time.Hour
+
 // CreatePostService instantiates the postService using the application container.
 func CreatePostService(cont container.Container) PostService {
-	return &postService{cont}
+	return &postService{cont, loadMetaSchema(cont), loadTrashRetention(cont), loadRenderer(cont), loadExcerptLength(cont)}
+}
+
+// loadTrashRetention reads how long trashed posts are kept, such as "720h", from the TRASH_RETENTION environment variable.
+// If the variable is missing or invalid, the default retention is used.
+func loadTrashRetention(cont container.Container) time.Duration {
+	log := cont.GetLogger()
+
+	value := os.Getenv("TRASH_RETENTION")
+	if value == "" {
+		return defaultTrashRetention
+	}
+
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention < 0 {
+		log.Errorf("invalid TRASH_RETENTION value \"%s\", falling back to %v", value, defaultTrashRetention)
+		return defaultTrashRetention
+	}
+
+	return retention
 }
 
 // AddPost adds a new post to the blog.
+// If the post has no URL handle, one is generated from its title.
 func (p postService) AddPost(newPost repository.Post, authorName string) (repository.Post, error) {
 	log := p.cont.GetLogger()
 	postRepository := p.cont.GetPostRepository()
@@ -47,41 +95,441 @@ func (p postService) AddPost(newPost repository.Post, authorName string) (reposi
 
 	newPost.AuthorID = author.ID
 
+	if newPost.Title == nil || strings.TrimSpace(*newPost.Title) == "" {
+		log.Errorf("missing title for post %v", newPost)
+		return repository.Post{}, errortypes.MissingTitleError{URLHandle: newPost.URLHandle}
+	}
+
+	if newPost.Tags, err = normalizeTags(newPost.Tags); err != nil {
+		log.Errorf("invalid tags for post %v: %v", newPost, err)
+		return repository.Post{}, err
+	}
+
+	if err := validatePostSchedule(newPost); err != nil {
+		log.Errorf("invalid schedule for post %v", newPost)
+		return repository.Post{}, err
+	}
+
+	if err := validateBodyFormat(newPost.BodyFormat); err != nil {
+		log.Errorf("invalid body format for post %v", newPost)
+		return repository.Post{}, err
+	}
+
+	if err := analyzePost(p.renderer, p.excerptLength, &newPost, newPost.BodyFormat); err != nil {
+		log.Errorf("failed to analyze body of post %v: %v", newPost, err)
+		return repository.Post{}, err
+	}
+
+	if newPost.Meta, err = p.metaSchema.Validate(newPost.Meta); err != nil {
+		log.Errorf("invalid metadata for post %v: %v", newPost, err)
+		return repository.Post{}, err
+	}
+
+	if err := p.resolveCategory(&newPost); err != nil {
+		return repository.Post{}, err
+	}
+
 	log.Infof("adding new post %v with author %s", newPost, authorName)
-	return postRepository.AddPost(newPost)
+	var post repository.Post
+	if newPost.URLHandle != "" {
+		post, err = postRepository.AddPost(newPost)
+	} else {
+		post, err = p.addPostWithGeneratedURLHandle(newPost)
+	}
+
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	indexPost(p.cont, p.renderer, post)
+	return post, nil
+}
+
+// addPostWithGeneratedURLHandle adds a new post with a URL handle generated from its title.
+// If the URL handle is already taken, a numeric suffix is appended. Since another post might take the same URL handle
+// concurrently, adding the post is retried a few times.
+func (p postService) addPostWithGeneratedURLHandle(newPost repository.Post) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	base := slug.Make(derefString(newPost.Title))
+
+	var err error
+	for attempt := 0; attempt < urlHandleAttempts; attempt++ {
+		var reserved []string
+		if reserved, err = postRepository.GetReservedURLHandles(base); err != nil {
+			log.Errorf("failed to get reserved URL handles like %s: %v", base, err)
+			return repository.Post{}, err
+		}
+
+		newPost.URLHandle = freeURLHandle(base, reserved)
+
+		var post repository.Post
+		post, err = postRepository.AddPost(newPost)
+		if _, duplicate := err.(errortypes.DuplicateElementError); !duplicate {
+			return post, err
+		}
+
+		log.Infof("generated URL handle %s was taken concurrently, retrying", newPost.URLHandle)
+	}
+
+	return repository.Post{}, err
+}
+
+// freeURLHandle returns the base if it isn't reserved, otherwise the base with the lowest free numeric suffix.
+func freeURLHandle(base string, reserved []string) string {
+	taken := make(map[string]bool, len(reserved))
+	for _, handle := range reserved {
+		taken[handle] = true
+	}
+
+	handle := base
+	for i := 2; taken[handle]; i++ {
+		handle = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	return handle
 }
 
 // UpdatePost updates an existing post in the blog.
-func (p postService) UpdatePost(updatedPost repository.Post) (repository.Post, error) {
+// The updated content is saved as a new revision of the post, attributed to the given editor.
+func (p postService) UpdatePost(updatedPost repository.Post, editorName string) (repository.Post, error) {
 	log := p.cont.GetLogger()
 	postRepository := p.cont.GetPostRepository()
+	userRepository := p.cont.GetUserRepository()
+
+	editor, err := userRepository.GetUser(editorName)
+	if err != nil {
+		log.Errorf("failed to get editor %s of post %s", editorName, updatedPost.URLHandle)
+		return repository.Post{}, err
+	}
+
+	if err := p.validateUpdatedPostSchedule(updatedPost); err != nil {
+		log.Errorf("invalid schedule for post %v", updatedPost)
+		return repository.Post{}, err
+	}
+
+	if err := validateBodyFormat(updatedPost.BodyFormat); err != nil {
+		log.Errorf("invalid body format for post %v", updatedPost)
+		return repository.Post{}, err
+	}
+
+	if err := p.analyzeUpdatedPost(&updatedPost); err != nil {
+		return repository.Post{}, err
+	}
+
+	meta, err := p.metaSchema.Validate(updatedPost.Meta)
+	if err != nil {
+		log.Errorf("invalid metadata for post %v: %v", updatedPost, err)
+		return repository.Post{}, err
+	}
+
+	if err := p.resolveCategory(&updatedPost); err != nil {
+		return repository.Post{}, err
+	}
+
+	if updatedPost.Tags, err = normalizeTags(updatedPost.Tags); err != nil {
+		log.Errorf("invalid tags for post %v: %v", updatedPost, err)
+		return repository.Post{}, err
+	}
+
+	updatedPost.Meta = meta
 
 	log.Infof("updating post %v", updatedPost)
-	return postRepository.UpdatePost(updatedPost)
+	post, err := postRepository.UpdatePost(updatedPost, editor.ID)
+	if err != nil {
+		return post, err
+	}
+
+	indexPost(p.cont, p.renderer, post)
+	return post, nil
 }
 
-// DeletePost deletes a post from the blog.
+// analyzeUpdatedPost recomputes the body statistics of the post if its body or its body format changes.
+// The missing one of the two is taken from the current post.
+func (p postService) analyzeUpdatedPost(updatedPost *repository.Post) error {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	if updatedPost.Body == nil && updatedPost.BodyFormat == "" {
+		return nil
+	}
+
+	post := repository.Post{Body: updatedPost.Body}
+	format := updatedPost.BodyFormat
+
+	if post.Body == nil || format == "" {
+		current, err := postRepository.GetPost(updatedPost.URLHandle)
+		if err != nil {
+			return err
+		}
+
+		if post.Body == nil {
+			post.Body = current.Body
+		}
+		if format == "" {
+			format = current.BodyFormat
+		}
+	}
+
+	if err := analyzePost(p.renderer, p.excerptLength, &post, format); err != nil {
+		log.Errorf("failed to analyze body of post %s: %v", updatedPost.URLHandle, err)
+		return err
+	}
+
+	updatedPost.Excerpt = post.Excerpt
+	updatedPost.WordCount = post.WordCount
+	updatedPost.ReadingTime = post.ReadingTime
+	return nil
+}
+
+// validateUpdatedPostSchedule validates the schedule of an updated post. Since missing fields of the update keep their
+// current values, a schedule given only partly is completed with the current schedule of the post first.
+func (p postService) validateUpdatedPostSchedule(updatedPost repository.Post) error {
+	postRepository := p.cont.GetPostRepository()
+
+	schedule := repository.Post{
+		URLHandle:   updatedPost.URLHandle,
+		PublishAt:   updatedPost.PublishAt,
+		UnpublishAt: updatedPost.UnpublishAt,
+	}
+
+	if (schedule.PublishAt == nil) != (schedule.UnpublishAt == nil) {
+		current, err := postRepository.GetPost(updatedPost.URLHandle)
+		if err != nil {
+			return err
+		}
+
+		if schedule.PublishAt == nil {
+			schedule.PublishAt = current.PublishAt
+		}
+		if schedule.UnpublishAt == nil {
+			schedule.UnpublishAt = current.UnpublishAt
+		}
+	}
+
+	return validatePostSchedule(schedule)
+}
+
+// validatePostSchedule makes sure that a post isn't scheduled to be unpublished before it gets published.
+func validatePostSchedule(post repository.Post) error {
+	if post.PublishAt != nil && post.UnpublishAt != nil && !post.UnpublishAt.After(*post.PublishAt) {
+		return errortypes.InvalidPostScheduleError{URLHandle: post.URLHandle}
+	}
+	return nil
+}
+
+// validateBodyFormat makes sure that the body format of a post is supported.
+// An empty format is valid, it keeps the current format or selects the default format for new posts.
+func validateBodyFormat(format repository.BodyFormat) error {
+	switch format {
+	case "", repository.BodyFormatMarkdown, repository.BodyFormatHTML, repository.BodyFormatText:
+		return nil
+	default:
+		return errortypes.InvalidBodyFormatError{Format: string(format)}
+	}
+}
+
+// resolveCategory replaces the category of the post, identified by its URL handle, with the category ID.
+func (p postService) resolveCategory(post *repository.Post) error {
+	log := p.cont.GetLogger()
+	categoryRepository := p.cont.GetCategoryRepository()
+
+	if post.Category == nil {
+		return nil
+	}
+
+	category, err := categoryRepository.GetCategory(post.Category.URLHandle)
+	if err != nil {
+		log.Errorf("failed to get category %s of post %s", post.Category.URLHandle, post.URLHandle)
+		return err
+	}
+
+	post.CategoryID = &category.ID
+	post.Category = nil
+	return nil
+}
+
+// DeletePost moves a post of the blog to the trash, from where it can be restored until it is purged.
+// Trashed posts are removed from the search index.
 func (p postService) DeletePost(urlHandle string) error {
 	log := p.cont.GetLogger()
 	postRepository := p.cont.GetPostRepository()
 
-	log.Infof("deleting post %s", urlHandle)
-	return postRepository.DeletePost(urlHandle)
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("moving post %s to trash", urlHandle)
+	if err := postRepository.DeletePost(urlHandle); err != nil {
+		return err
+	}
+
+	removeFromIndex(p.cont, post)
+	return nil
+}
+
+// RestorePost moves a trashed post back to the blog with the status it had before it was deleted.
+func (p postService) RestorePost(urlHandle string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	log.Infof("restoring post %s from trash", urlHandle)
+	post, err := postRepository.RestorePost(urlHandle)
+	if err != nil {
+		return post, err
+	}
+
+	indexPost(p.cont, p.renderer, post)
+	return post, nil
 }
 
-// GetPost retrieves the post with the given URL handle.
+// RenamePost changes the URL handle of a post. The former URL handle keeps redirecting to the post.
+func (p postService) RenamePost(urlHandle string, newURLHandle string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	if newURLHandle == "" {
+		log.Errorf("missing new URL handle of post %s", urlHandle)
+		return repository.Post{}, errortypes.MissingUrlHandleError{}
+	}
+
+	if newURLHandle == urlHandle {
+		return postRepository.GetPost(urlHandle)
+	}
+
+	log.Infof("renaming post %s to %s", urlHandle, newURLHandle)
+	return postRepository.RenamePost(urlHandle, newURLHandle)
+}
+
+// GetTrash retrieves the first page of trashed posts.
+func (p postService) GetTrash(authorName string) ([]repository.Post, int, error) {
+	return p.GetTrashPage(authorName, 1)
+}
+
+// GetTrashPage retrieves one page of trashed posts, most recently deleted first.
+// If an author is given, only their posts are listed, otherwise the posts of every author.
+func (p postService) GetTrashPage(authorName string, page int) ([]repository.Post, int, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+	userRepository := p.cont.GetUserRepository()
+
+	if page < 1 {
+		log.Errorf("invalid trash page number %d", page)
+		return nil, -1, errortypes.InvalidPostPageError{Page: page}
+	}
+
+	var filter repository.PostFilter
+	if authorName != "" {
+		author, err := userRepository.GetUser(authorName)
+		if err != nil {
+			log.Errorf("failed to get author %s of trashed posts", authorName)
+			return nil, -1, err
+		}
+
+		filter.AuthorID = author.ID
+	}
+
+	posts, count, err := postRepository.GetTrashedPosts(filter, page, postPageSize)
+	pages := int(math.Ceil(float64(count) / float64(postPageSize)))
+
+	return posts, pages, err
+}
+
+// PurgeTrash permanently deletes every post that has been in the trash for longer than the retention period.
+// Purging the same posts from several instances at once is harmless, the posts are simply deleted only once.
+func (p postService) PurgeTrash() error {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	purged, err := postRepository.PurgeTrashedPosts(time.Now().Add(-p.trashRetention))
+	if err != nil {
+		log.Errorf("failed to purge trashed posts: %v", err)
+		return err
+	}
+
+	if purged > 0 {
+		log.Infof("purged %d posts trashed more than %v ago", purged, p.trashRetention)
+	}
+
+	return nil
+}
+
+// GetPost retrieves the published post with the given URL handle.
+// Posts that are not published are reported as missing.
+// If the URL handle belonged to a published post before it was renamed, the new URL handle is reported instead.
+// If the post belongs to a series, its position within the series is attached.
+// The older and newer published posts are attached as well, following the order of GetPosts.
 func (p postService) GetPost(urlHandle string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+	seriesRepository := p.cont.GetSeriesRepository()
+
+	post, err := postRepository.GetPost(urlHandle)
+	switch err.(type) {
+	case nil:
+	case errortypes.PostNotFoundError:
+		return repository.Post{}, p.resolveRedirect(urlHandle, err)
+	default:
+		return repository.Post{}, err
+	}
+
+	if !post.IsPublished() {
+		log.Debugf("post %s is not published, status: %s", urlHandle, post.Status)
+		return repository.Post{}, errortypes.PostNotFoundError{URLHandle: urlHandle}
+	}
+
+	series, err := seriesRepository.GetSeriesOfPost(post.ID)
+	switch err.(type) {
+	case nil:
+		post.Series = seriesNavigation(series, post)
+	case errortypes.SeriesNotFoundError:
+	default:
+		log.Errorf("failed to get series of post %s: %v", urlHandle, err)
+		return repository.Post{}, err
+	}
+
+	if post.Older, post.Newer, err = postRepository.GetAdjacentPosts(post); err != nil {
+		log.Errorf("failed to get neighbours of post %s: %v", urlHandle, err)
+		return repository.Post{}, err
+	}
+
+	renderPost(p.cont, p.renderer, &post)
+	return post, nil
+}
+
+// resolveRedirect looks up the post that used the URL handle before it was renamed.
+// If there is no such published post, the original error is returned.
+func (p postService) resolveRedirect(urlHandle string, notFoundErr error) error {
+	log := p.cont.GetLogger()
 	postRepository := p.cont.GetPostRepository()
-	return postRepository.GetPost(urlHandle)
+
+	post, err := postRepository.GetRedirectedPost(urlHandle)
+	switch err.(type) {
+	case nil:
+	case errortypes.PostNotFoundError:
+		return notFoundErr
+	default:
+		log.Errorf("failed to resolve redirect of post %s: %v", urlHandle, err)
+		return err
+	}
+
+	if !post.IsPublished() {
+		log.Debugf("redirect target %s of post %s is not published", post.URLHandle, urlHandle)
+		return notFoundErr
+	}
+
+	return errortypes.PostMovedError{URLHandle: urlHandle, NewURLHandle: post.URLHandle}
 }
 
-// GetPosts retrieves the first page of posts of the blog.
-func (p postService) GetPosts() ([]repository.Post, int, error) {
-	return p.GetPostsPage(1)
+// GetPosts retrieves the first page of posts of the blog matching the filter.
+func (p postService) GetPosts(filter repository.PostFilter) ([]repository.Post, int, error) {
+	return p.GetPostsPage(filter, 1)
 }
 
-// GetPostsPage retrieves one page of posts of the blog.
-func (p postService) GetPostsPage(page int) ([]repository.Post, int, error) {
+// GetPostsPage retrieves one page of posts of the blog matching the filter.
+func (p postService) GetPostsPage(filter repository.PostFilter, page int) ([]repository.Post, int, error) {
 	log := p.cont.GetLogger()
 	postRepository := p.cont.GetPostRepository()
 
@@ -90,8 +538,150 @@ func (p postService) GetPostsPage(page int) ([]repository.Post, int, error) {
 		return nil, -1, errortypes.InvalidPostPageError{Page: page}
 	}
 
-	posts, count, err := postRepository.GetPosts(page, postPageSize)
+	filter.Tag = NormalizeTagName(filter.Tag)
+
+	meta, err := p.metaSchema.NormalizeFilter(filter.Meta)
+	if err != nil {
+		log.Errorf("invalid metadata filter %v: %v", filter.Meta, err)
+		return nil, -1, err
+	}
+	filter.Meta = meta
+
+	posts, count, err := postRepository.GetPosts(filter, page, postPageSize)
+	pages := int(math.Ceil(float64(count) / float64(postPageSize)))
+
+	return posts, pages, err
+}
+
+// GetDraft retrieves the draft with the given URL handle if it was written by the given author.
+func (p postService) GetDraft(urlHandle string, authorName string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	if post.Status != repository.PostStatusDraft || post.Author.UserName != authorName {
+		log.Debugf("post %s is not a draft of %s", urlHandle, authorName)
+		return repository.Post{}, errortypes.PostNotFoundError{URLHandle: urlHandle}
+	}
+
+	renderPost(p.cont, p.renderer, &post)
+	return post, nil
+}
+
+// GetDrafts retrieves the first page of drafts written by the given author.
+func (p postService) GetDrafts(authorName string) ([]repository.Post, int, error) {
+	return p.GetDraftsPage(authorName, 1)
+}
+
+// GetDraftsPage retrieves one page of drafts written by the given author.
+func (p postService) GetDraftsPage(authorName string, page int) ([]repository.Post, int, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+	userRepository := p.cont.GetUserRepository()
+
+	if page < 1 {
+		log.Errorf("invalid draft page number %d", page)
+		return nil, -1, errortypes.InvalidPostPageError{Page: page}
+	}
+
+	author, err := userRepository.GetUser(authorName)
+	if err != nil {
+		log.Errorf("failed to get author %s of drafts", authorName)
+		return nil, -1, err
+	}
+
+	posts, count, err := postRepository.GetPostsByAuthor(author.ID, repository.PostStatusDraft, page, postPageSize)
 	pages := int(math.Ceil(float64(count) / float64(postPageSize)))
 
 	return posts, pages, err
 }
+
+// PublishPost makes the post visible to everyone.
+// Publishing an already published post keeps its original publication time.
+// Like every manual status change, it cancels the publication schedule of the post.
+func (p postService) PublishPost(urlHandle string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	if post.Status == repository.PostStatusPublished {
+		log.Debugf("post %s is already published", urlHandle)
+		return post, nil
+	}
+
+	now := time.Now()
+
+	log.Infof("publishing post %s", urlHandle)
+	return postRepository.UpdatePostStatus(urlHandle, repository.PostStatusPublished, &now)
+}
+
+// UnpublishPost turns the post back into a draft.
+func (p postService) UnpublishPost(urlHandle string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	log.Infof("unpublishing post %s", urlHandle)
+	return postRepository.UpdatePostStatus(urlHandle, repository.PostStatusDraft, nil)
+}
+
+// ArchivePost hides the post from readers while keeping its publication time.
+func (p postService) ArchivePost(urlHandle string) (repository.Post, error) {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	log.Infof("archiving post %s", urlHandle)
+	return postRepository.UpdatePostStatus(urlHandle, repository.PostStatusArchived, post.PublishedAt)
+}
+
+// ApplyPostSchedules publishes and unpublishes every post whose scheduled time has passed.
+// The repository applies each transition with a conditional update,
+// so only the instance that actually changed a post logs the transition.
+func (p postService) ApplyPostSchedules() error {
+	log := p.cont.GetLogger()
+	postRepository := p.cont.GetPostRepository()
+
+	now := time.Now()
+	posts, err := postRepository.GetDuePosts(now)
+	if err != nil {
+		log.Errorf("failed to get posts with due schedules: %v", err)
+		return err
+	}
+
+	var firstErr error
+	for _, post := range posts {
+		var changed bool
+		var transition string
+
+		if post.Status == repository.PostStatusDraft {
+			transition = "publication"
+			changed, err = postRepository.PublishDuePost(post.ID, now)
+		} else {
+			transition = "unpublication"
+			changed, err = postRepository.UnpublishDuePost(post.ID, now)
+		}
+
+		if err != nil {
+			log.Errorf("failed to apply scheduled %s of post %s: %v", transition, post.URLHandle, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		} else if changed {
+			log.Infof("applied scheduled %s of post %s", transition, post.URLHandle)
+		}
+	}
+
+	return firstErr
+}
@@ -7,18 +7,24 @@ import (
 	"github.com/wlachs/blog/internal/errortypes"
 	"github.com/wlachs/blog/internal/logger"
 	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/render"
 	"github.com/wlachs/blog/internal/repository"
 	"github.com/wlachs/blog/internal/services"
 	"go.uber.org/mock/gomock"
+	"strings"
 	"testing"
 	"time"
 )
 
 // postTestContext contains objects relevant for testing the PostService.
 type postTestContext struct {
-	mostPostRepository *mocks.MockPostRepository
-	mostUserRepository *mocks.MockUserRepository
-	sut                services.PostService
+	mostPostRepository     *mocks.MockPostRepository
+	mostUserRepository     *mocks.MockUserRepository
+	mockCategoryRepository *mocks.MockCategoryRepository
+	mockSeriesRepository   *mocks.MockSeriesRepository
+	mockRevisionRepository *mocks.MockRevisionRepository
+	mockSearchRepository   *mocks.MockSearchRepository
+	sut                    services.PostService
 }
 
 // createPostServiceContext creates the context for testing the PostService and reduces code duplication.
@@ -28,10 +34,36 @@ func createPostServiceContext(t *testing.T) *postTestContext {
 	mockCtrl := gomock.NewController(t)
 	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
 	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
-	cont := container.CreateContainer(logger.CreateLogger(), mockPostRepository, mockUserRepository, nil)
+	mockCategoryRepository := mocks.NewMockCategoryRepository(mockCtrl)
+	mockSeriesRepository := mocks.NewMockSeriesRepository(mockCtrl)
+	mockRevisionRepository := mocks.NewMockRevisionRepository(mockCtrl)
+	mockSearchRepository := mocks.NewMockSearchRepository(mockCtrl)
+	cont := container.CreateContainer(
+		logger.CreateLogger(),
+		mockPostRepository,
+		mockUserRepository,
+		nil,
+		mockCategoryRepository,
+		mockSeriesRepository,
+		mockRevisionRepository,
+		mockSearchRepository,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
 	sut := services.CreatePostService(cont)
 
-	return &postTestContext{mockPostRepository, mockUserRepository, sut}
+	return &postTestContext{
+		mockPostRepository,
+		mockUserRepository,
+		mockCategoryRepository,
+		mockSeriesRepository,
+		mockRevisionRepository,
+		mockSearchRepository,
+		sut,
+	}
 }
 
 // TestPostService_AddPost tests adding a new post to the blog.
@@ -42,6 +74,8 @@ func TestPostService_AddPost(t *testing.T) {
 	title := "testTitle"
 	summary := "testSummary"
 	body := "testBody"
+	words := 1
+	minutes := 1
 	userModel := repository.User{
 		ID:       0,
 		UserName: "testAuthor",
@@ -66,8 +100,14 @@ func TestPostService_AddPost(t *testing.T) {
 		Body:      postModel.Body,
 	}
 
+	expectedPost := newPost
+	expectedPost.Excerpt = &body
+	expectedPost.WordCount = &words
+	expectedPost.ReadingTime = &minutes
+
 	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
-	c.mostPostRepository.EXPECT().AddPost(newPost).Return(postModel, nil)
+	c.mostPostRepository.EXPECT().AddPost(expectedPost).Return(postModel, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(repository.SearchDocument{Title: title, Summary: summary, Body: body}).Return(nil)
 
 	p, err := c.sut.AddPost(newPost, userModel.UserName)
 
@@ -133,7 +173,7 @@ func TestPostService_AddPost_Duplicate_Post(t *testing.T) {
 	expectedError := errortypes.DuplicateElementError{Key: postModel.URLHandle}
 
 	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
-	c.mostPostRepository.EXPECT().AddPost(newPost).Return(repository.Post{}, expectedError)
+	c.mostPostRepository.EXPECT().AddPost(gomock.Any()).Return(repository.Post{}, expectedError)
 
 	p, err := c.sut.AddPost(newPost, userModel.UserName)
 
@@ -149,6 +189,8 @@ func TestPostService_UpdatePost(t *testing.T) {
 	title := "testTitle"
 	summary := "testSummary"
 	body := "testBody"
+	words := 1
+	minutes := 1
 	userModel := repository.User{
 		ID:       0,
 		UserName: "testAuthor",
@@ -174,9 +216,16 @@ func TestPostService_UpdatePost(t *testing.T) {
 	}
 	dbErr := fmt.Errorf("error")
 
-	c.mostPostRepository.EXPECT().UpdatePost(updatedPost).Return(postModel, dbErr)
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	expectedPost := updatedPost
+	expectedPost.Excerpt = &body
+	expectedPost.WordCount = &words
+	expectedPost.ReadingTime = &minutes
 
-	p, err := c.sut.UpdatePost(updatedPost)
+	c.mostPostRepository.EXPECT().GetPost(updatedPost.URLHandle).Return(postModel, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(expectedPost, uint(2)).Return(postModel, dbErr)
+
+	p, err := c.sut.UpdatePost(updatedPost, "testEditor")
 
 	assert.Equal(t, dbErr, err, "should forward DB error to controller")
 	assert.Equal(t, postModel, p, "added post doesn't match the input")
@@ -190,6 +239,7 @@ func TestPostService_DeletePost(t *testing.T) {
 	urlHandle := "testUrlHandle"
 	dbErr := fmt.Errorf("error")
 
+	c.mostPostRepository.EXPECT().GetPost(urlHandle).Return(repository.Post{ID: 3, URLHandle: urlHandle}, nil)
 	c.mostPostRepository.EXPECT().DeletePost(urlHandle).Return(dbErr)
 
 	err := c.sut.DeletePost(urlHandle)
@@ -197,6 +247,37 @@ func TestPostService_DeletePost(t *testing.T) {
 	assert.Equal(t, dbErr, err, "should forward DB error to controller")
 }
 
+// TestPostService_DeletePost_Search_Index tests that deleted posts are removed from the search index.
+func TestPostService_DeletePost_Search_Index(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	urlHandle := "testUrlHandle"
+
+	c.mostPostRepository.EXPECT().GetPost(urlHandle).Return(repository.Post{ID: 3, URLHandle: urlHandle}, nil)
+	c.mostPostRepository.EXPECT().DeletePost(urlHandle).Return(nil)
+	c.mockSearchRepository.EXPECT().RemovePost(uint(3)).Return(nil)
+
+	err := c.sut.DeletePost(urlHandle)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_DeletePost_Not_Found tests deleting a post that doesn't exist.
+func TestPostService_DeletePost_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	urlHandle := "testUrlHandle"
+	expectedError := errortypes.PostNotFoundError{URLHandle: urlHandle}
+
+	c.mostPostRepository.EXPECT().GetPost(urlHandle).Return(repository.Post{}, expectedError)
+
+	err := c.sut.DeletePost(urlHandle)
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
 // TestPostService_GetPost tests getting a post from the blog.
 func TestPostService_GetPost(t *testing.T) {
 	t.Parallel()
@@ -205,6 +286,7 @@ func TestPostService_GetPost(t *testing.T) {
 	title := "testTitle"
 	summary := "testSummary"
 	body := "testBody"
+	bodyHTML := "<p>testBody</p>\n"
 	userModel := repository.User{
 		ID:       0,
 		UserName: "testAuthor",
@@ -218,6 +300,7 @@ func TestPostService_GetPost(t *testing.T) {
 		Title:     &title,
 		Summary:   &summary,
 		Body:      &body,
+		Status:    repository.PostStatusPublished,
 		CreatedAt: time.Time{}.Local(),
 		UpdatedAt: time.Time{}.Local(),
 	}
@@ -227,11 +310,20 @@ func TestPostService_GetPost(t *testing.T) {
 		Author:    userModel,
 		Summary:   postModel.Summary,
 		Body:      postModel.Body,
+		Status:    postModel.Status,
 		CreatedAt: postModel.CreatedAt,
 		UpdatedAt: postModel.UpdatedAt,
+		BodyHTML:  &bodyHTML,
+		Older:     &repository.Post{URLHandle: "older"},
+		Newer:     &repository.Post{URLHandle: "newer"},
 	}
+	revision := repository.Revision{ID: 3, Number: 1, Body: &body}
 
 	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(postModel.ID).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mostPostRepository.EXPECT().GetAdjacentPosts(postModel).Return(post.Older, post.Newer, nil)
+	c.mockRevisionRepository.EXPECT().GetLatestRevision(postModel.ID).Return(revision, nil)
+	c.mockRevisionRepository.EXPECT().CacheRendering(revision.ID, bodyHTML, gomock.Any()).Return(nil)
 
 	p, err := c.sut.GetPost(postModel.URLHandle)
 
@@ -239,6 +331,42 @@ func TestPostService_GetPost(t *testing.T) {
 	assert.Equal(t, post, p, "post doesn't match the expected output")
 }
 
+// TestPostService_GetPost_Adjacent_Posts_Error tests getting a post whose neighbours can't be retrieved.
+func TestPostService_GetPost_Adjacent_Posts_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{ID: 4, URLHandle: "testUrlHandle", Status: repository.PostStatusPublished}
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(postModel.ID).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mostPostRepository.EXPECT().GetAdjacentPosts(postModel).Return(nil, nil, expectedError)
+
+	_, err := c.sut.GetPost(postModel.URLHandle)
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+}
+
+// TestPostService_GetPost_Draft tests getting a post that is not published yet.
+func TestPostService_GetPost_Draft(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Status:    repository.PostStatusDraft,
+	}
+	expectedError := errortypes.PostNotFoundError{URLHandle: postModel.URLHandle}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	p, err := c.sut.GetPost(postModel.URLHandle)
+
+	assert.Equal(t, expectedError, err, "drafts should not be visible")
+	assert.Equal(t, repository.Post{}, p, "should not return a post")
+}
+
 // TestPostService_GetPost_Unexpected_Error tests handling an unexpected error while getting a post.
 func TestPostService_GetPost_Unexpected_Error(t *testing.T) {
 	t.Parallel()
@@ -289,9 +417,9 @@ func TestPostService_GetPosts(t *testing.T) {
 		},
 	}
 
-	c.mostPostRepository.EXPECT().GetPosts(1, 5).Return(postModels, 5, nil)
+	c.mostPostRepository.EXPECT().GetPosts(repository.PostFilter{}, 1, 5).Return(postModels, 5, nil)
 
-	p, pages, err := c.sut.GetPosts()
+	p, pages, err := c.sut.GetPosts(repository.PostFilter{})
 
 	assert.Nil(t, err, "should complete without error")
 	assert.Equal(t, posts, p, "post doesn't match the expected output")
@@ -303,8 +431,8 @@ func TestPostService_GetPosts_Unexpected_Error(t *testing.T) {
 	t.Parallel()
 	c := createPostServiceContext(t)
 
-	c.mostPostRepository.EXPECT().GetPosts(1, 5).Return(nil, -1, fmt.Errorf("error"))
-	_, _, err := c.sut.GetPosts()
+	c.mostPostRepository.EXPECT().GetPosts(repository.PostFilter{}, 1, 5).Return(nil, -1, fmt.Errorf("error"))
+	_, _, err := c.sut.GetPosts(repository.PostFilter{})
 
 	assert.NotNil(t, err, "expected error")
 }
@@ -348,9 +476,9 @@ func TestPostService_GetPostsPage(t *testing.T) {
 		},
 	}
 
-	c.mostPostRepository.EXPECT().GetPosts(2, 5).Return(postModels, 6, nil)
+	c.mostPostRepository.EXPECT().GetPosts(repository.PostFilter{}, 2, 5).Return(postModels, 6, nil)
 
-	p, pages, err := c.sut.GetPostsPage(2)
+	p, pages, err := c.sut.GetPostsPage(repository.PostFilter{}, 2)
 
 	assert.Nil(t, err, "should complete without error")
 	assert.Equal(t, posts, p, "post doesn't match the expected output")
@@ -363,7 +491,976 @@ func TestPostService_GetPostsPage_Invalid_Page(t *testing.T) {
 	c := createPostServiceContext(t)
 
 	expectedError := errortypes.InvalidPostPageError{Page: -2}
-	_, _, err := c.sut.GetPostsPage(-2)
+	_, _, err := c.sut.GetPostsPage(repository.PostFilter{}, -2)
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_GetDraft tests getting a draft of the current author.
+func TestPostService_GetDraft(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Author:    repository.User{UserName: "testAuthor"},
+		Status:    repository.PostStatusDraft,
+	}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	p, err := c.sut.GetDraft(postModel.URLHandle, "testAuthor")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, postModel, p, "draft doesn't match the expected output")
+}
+
+// TestPostService_GetDraft_Other_Author tests getting a draft written by someone else.
+func TestPostService_GetDraft_Other_Author(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{
+		URLHandle: "testUrlHandle",
+		Author:    repository.User{UserName: "otherAuthor"},
+		Status:    repository.PostStatusDraft,
+	}
+	expectedError := errortypes.PostNotFoundError{URLHandle: postModel.URLHandle}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	_, err := c.sut.GetDraft(postModel.URLHandle, "testAuthor")
+
+	assert.Equal(t, expectedError, err, "drafts of other authors should not be visible")
+}
+
+// TestPostService_GetDrafts tests getting the drafts of an author.
+func TestPostService_GetDrafts(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	userModel := repository.User{ID: 3, UserName: "testAuthor"}
+	posts := []repository.Post{
+		{URLHandle: "draft1", Status: repository.PostStatusDraft},
+		{URLHandle: "draft2", Status: repository.PostStatusDraft},
+	}
+
+	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
+	c.mostPostRepository.EXPECT().GetPostsByAuthor(userModel.ID, repository.PostStatusDraft, 1, 5).Return(posts, 7, nil)
+
+	p, pages, err := c.sut.GetDrafts(userModel.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, posts, p, "drafts don't match the expected output")
+	assert.Equal(t, 2, pages, "incorrect page count")
+}
+
+// TestPostService_GetDraftsPage_Invalid_Page tests getting drafts with an invalid page number.
+func TestPostService_GetDraftsPage_Invalid_Page(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	_, _, err := c.sut.GetDraftsPage("testAuthor", 0)
+
+	assert.Equal(t, errortypes.InvalidPostPageError{Page: 0}, err, "error doesn't match expected one")
+}
+
+// TestPostService_PublishPost tests publishing a draft.
+func TestPostService_PublishPost(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	draft := repository.Post{URLHandle: "testUrlHandle", Status: repository.PostStatusDraft}
+	published := repository.Post{URLHandle: draft.URLHandle, Status: repository.PostStatusPublished}
+
+	c.mostPostRepository.EXPECT().GetPost(draft.URLHandle).Return(draft, nil)
+	c.mostPostRepository.EXPECT().
+		UpdatePostStatus(draft.URLHandle, repository.PostStatusPublished, gomock.Not(gomock.Nil())).
+		Return(published, nil)
+
+	p, err := c.sut.PublishPost(draft.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, published, p, "post doesn't match the expected output")
+}
+
+// TestPostService_PublishPost_Already_Published tests publishing a post that is already public.
+func TestPostService_PublishPost_Already_Published(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	publishedAt := time.Now()
+	published := repository.Post{URLHandle: "testUrlHandle", Status: repository.PostStatusPublished, PublishedAt: &publishedAt}
+
+	c.mostPostRepository.EXPECT().GetPost(published.URLHandle).Return(published, nil)
+
+	p, err := c.sut.PublishPost(published.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, published, p, "publication time should be kept")
+}
+
+// TestPostService_UnpublishPost tests turning a post back into a draft.
+func TestPostService_UnpublishPost(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	draft := repository.Post{URLHandle: "testUrlHandle", Status: repository.PostStatusDraft}
+
+	c.mostPostRepository.EXPECT().UpdatePostStatus(draft.URLHandle, repository.PostStatusDraft, nil).Return(draft, nil)
+
+	p, err := c.sut.UnpublishPost(draft.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, draft, p, "post doesn't match the expected output")
+}
+
+// TestPostService_ArchivePost tests archiving a published post.
+func TestPostService_ArchivePost(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	publishedAt := time.Now()
+	published := repository.Post{URLHandle: "testUrlHandle", Status: repository.PostStatusPublished, PublishedAt: &publishedAt}
+	archived := repository.Post{URLHandle: published.URLHandle, Status: repository.PostStatusArchived, PublishedAt: &publishedAt}
+
+	c.mostPostRepository.EXPECT().GetPost(published.URLHandle).Return(published, nil)
+	c.mostPostRepository.EXPECT().UpdatePostStatus(published.URLHandle, repository.PostStatusArchived, &publishedAt).Return(archived, nil)
+
+	p, err := c.sut.ArchivePost(published.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, archived, p, "post doesn't match the expected output")
+}
+
+// TestPostService_AddPost_Invalid_Schedule tests adding a post that would be unpublished before being published.
+func TestPostService_AddPost_Invalid_Schedule(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	publishAt := time.Now().Add(time.Hour)
+	unpublishAt := time.Now()
+	newPost := repository.Post{
+		URLHandle:   "testUrlHandle",
+		Title:       &title,
+		PublishAt:   &publishAt,
+		UnpublishAt: &unpublishAt,
+	}
+	expectedError := errortypes.InvalidPostScheduleError{URLHandle: newPost.URLHandle}
+
+	c.mostUserRepository.EXPECT().GetUser("testAuthor").Return(repository.User{UserName: "testAuthor"}, nil)
+
+	_, err := c.sut.AddPost(newPost, "testAuthor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_UpdatePost_Invalid_Schedule tests updating a post so that it would be unpublished before being published.
+func TestPostService_UpdatePost_Invalid_Schedule(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	publishAt := time.Now()
+	updatedPost := repository.Post{
+		URLHandle:   "testUrlHandle",
+		PublishAt:   &publishAt,
+		UnpublishAt: &publishAt,
+	}
+	expectedError := errortypes.InvalidPostScheduleError{URLHandle: updatedPost.URLHandle}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+
+	_, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_UpdatePost_Invalid_Stored_Schedule tests moving the publication after the stored unpublication.
+func TestPostService_UpdatePost_Invalid_Stored_Schedule(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	unpublishAt := time.Now().Add(time.Hour)
+	publishAt := unpublishAt.Add(time.Hour)
+	updatedPost := repository.Post{URLHandle: "testUrlHandle", PublishAt: &publishAt}
+	storedPost := repository.Post{ID: 3, URLHandle: updatedPost.URLHandle, UnpublishAt: &unpublishAt}
+	expectedError := errortypes.InvalidPostScheduleError{URLHandle: updatedPost.URLHandle}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().GetPost(updatedPost.URLHandle).Return(storedPost, nil)
+
+	_, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_UpdatePost_Stored_Schedule tests moving the unpublication while keeping the stored publication.
+func TestPostService_UpdatePost_Stored_Schedule(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	publishAt := time.Now().Add(time.Hour)
+	unpublishAt := publishAt.Add(time.Hour)
+	updatedPost := repository.Post{URLHandle: "testUrlHandle", UnpublishAt: &unpublishAt}
+	storedPost := repository.Post{ID: 3, URLHandle: updatedPost.URLHandle, PublishAt: &publishAt}
+	postModel := repository.Post{ID: 3, URLHandle: updatedPost.URLHandle, PublishAt: &publishAt, UnpublishAt: &unpublishAt}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().GetPost(updatedPost.URLHandle).Return(storedPost, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(updatedPost, uint(2)).Return(postModel, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(repository.SearchDocument{PostID: 3}).Return(nil)
+
+	p, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, postModel, p, "updated post doesn't match the expected one")
+}
+
+// TestPostService_ApplyPostSchedules tests applying due publication schedules.
+func TestPostService_ApplyPostSchedules(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	posts := []repository.Post{
+		{ID: 1, URLHandle: "toPublish", Status: repository.PostStatusDraft},
+		{ID: 2, URLHandle: "toUnpublish", Status: repository.PostStatusPublished},
+		{ID: 3, URLHandle: "handledElsewhere", Status: repository.PostStatusDraft},
+	}
+
+	c.mostPostRepository.EXPECT().GetDuePosts(gomock.Any()).Return(posts, nil)
+	c.mostPostRepository.EXPECT().PublishDuePost(uint(1), gomock.Any()).Return(true, nil)
+	c.mostPostRepository.EXPECT().UnpublishDuePost(uint(2), gomock.Any()).Return(true, nil)
+	c.mostPostRepository.EXPECT().PublishDuePost(uint(3), gomock.Any()).Return(false, nil)
+
+	err := c.sut.ApplyPostSchedules()
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_ApplyPostSchedules_Unexpected_Error tests that a failing transition doesn't stop the remaining ones.
+func TestPostService_ApplyPostSchedules_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	posts := []repository.Post{
+		{ID: 1, URLHandle: "failing", Status: repository.PostStatusDraft},
+		{ID: 2, URLHandle: "toUnpublish", Status: repository.PostStatusPublished},
+	}
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mostPostRepository.EXPECT().GetDuePosts(gomock.Any()).Return(posts, nil)
+	c.mostPostRepository.EXPECT().PublishDuePost(uint(1), gomock.Any()).Return(false, expectedError)
+	c.mostPostRepository.EXPECT().UnpublishDuePost(uint(2), gomock.Any()).Return(true, nil)
+
+	err := c.sut.ApplyPostSchedules()
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_AddPost_Tags tests that the tags of a new post are normalized.
+func TestPostService_AddPost_Tags(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	userModel := repository.User{ID: 1, UserName: "testAuthor"}
+	newPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Tags:      []repository.Tag{{Name: "Go"}, {Name: " go"}, {Name: ""}, {Name: "Backend"}},
+	}
+	expectedPost := repository.Post{
+		URLHandle: newPost.URLHandle,
+		Title:     &title,
+		AuthorID:  userModel.ID,
+		Tags:      []repository.Tag{{Name: "go"}, {Name: "backend"}},
+	}
+
+	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
+	c.mostPostRepository.EXPECT().AddPost(expectedPost).Return(expectedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	_, err := c.sut.AddPost(newPost, userModel.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_AddPost_Tag_Too_Long tests adding a post with a tag name exceeding the maximum length.
+func TestPostService_AddPost_Tag_Too_Long(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	name := strings.Repeat("a", 65)
+	newPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Tags:      []repository.Tag{{Name: "go"}, {Name: name}},
+	}
+	expectedError := errortypes.InvalidTagError{Name: name, MaxLength: 64}
+
+	c.mostUserRepository.EXPECT().GetUser("testAuthor").Return(repository.User{UserName: "testAuthor"}, nil)
+
+	_, err := c.sut.AddPost(newPost, "testAuthor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_UpdatePost_Tag_Too_Long tests updating a post with a tag name exceeding the maximum length.
+func TestPostService_UpdatePost_Tag_Too_Long(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	name := strings.Repeat("ä", 65)
+	updatedPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Tags:      []repository.Tag{{Name: name}},
+	}
+	expectedError := errortypes.InvalidTagError{Name: name, MaxLength: 64}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+
+	_, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_GetPostsPage_Tag_Filter tests that the tag filter is normalized.
+func TestPostService_GetPostsPage_Tag_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	c.mostPostRepository.EXPECT().GetPosts(repository.PostFilter{Tag: "go"}, 1, 5).Return([]repository.Post{}, 0, nil)
+
+	_, pages, err := c.sut.GetPostsPage(repository.PostFilter{Tag: " Go"}, 1)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, 0, pages, "incorrect page count")
+}
+
+// TestPostService_AddPost_Invalid_Meta tests adding a post with metadata that doesn't match the schema.
+func TestPostService_AddPost_Invalid_Meta(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	newPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Meta:      map[string]interface{}{"canonicalUrl": "not a url"},
+	}
+	expectedError := errortypes.InvalidPostMetaError{Key: "canonicalUrl", Reason: "expected an absolute http or https URL"}
+
+	c.mostUserRepository.EXPECT().GetUser("testAuthor").Return(repository.User{UserName: "testAuthor"}, nil)
+
+	_, err := c.sut.AddPost(newPost, "testAuthor")
 
 	assert.Equal(t, expectedError, err, "error doesn't match expected one")
 }
+
+// TestPostService_UpdatePost_Meta tests that the metadata of an updated post is stored in canonical form.
+func TestPostService_UpdatePost_Meta(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	updatedPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Meta:      map[string]interface{}{"license": "cc-by", "originallyPublishedAt": "2023-11-21T10:00:00+02:00"},
+	}
+	expectedPost := repository.Post{
+		URLHandle: updatedPost.URLHandle,
+		Meta:      map[string]interface{}{"license": "cc-by", "originallyPublishedAt": "2023-11-21T08:00:00Z"},
+	}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(expectedPost, uint(2)).Return(expectedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	_, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_GetPostsPage_Invalid_Meta_Filter tests filtering posts by an unknown metadata field.
+func TestPostService_GetPostsPage_Invalid_Meta_Filter(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	expectedError := errortypes.InvalidPostMetaError{Key: "unknown", Reason: "unknown field"}
+
+	_, _, err := c.sut.GetPostsPage(repository.PostFilter{Meta: map[string]string{"unknown": "value"}}, 1)
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_AddPost_Category tests that the category of a new post is resolved to its ID.
+func TestPostService_AddPost_Category(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	categoryID := uint(3)
+	userModel := repository.User{ID: 1, UserName: "testAuthor"}
+	newPost := repository.Post{
+		URLHandle: "testUrlHandle",
+		Title:     &title,
+		Category:  &repository.Category{URLHandle: "performance"},
+	}
+	expectedPost := repository.Post{
+		URLHandle:  newPost.URLHandle,
+		Title:      &title,
+		AuthorID:   userModel.ID,
+		CategoryID: &categoryID,
+	}
+
+	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
+	c.mockCategoryRepository.EXPECT().GetCategory("performance").Return(repository.Category{ID: categoryID}, nil)
+	c.mostPostRepository.EXPECT().AddPost(expectedPost).Return(expectedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	_, err := c.sut.AddPost(newPost, userModel.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_UpdatePost_Missing_Category tests moving a post to a non-existing category.
+func TestPostService_UpdatePost_Missing_Category(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	expectedError := errortypes.CategoryNotFoundError{URLHandle: "rust"}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mockCategoryRepository.EXPECT().GetCategory("rust").Return(repository.Category{}, expectedError)
+
+	_, err := c.sut.UpdatePost(repository.Post{
+		URLHandle: "testUrlHandle",
+		Category:  &repository.Category{URLHandle: "rust"},
+	}, "testEditor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_GetPost_Series tests that the position of a post within its series is attached.
+func TestPostService_GetPost_Series(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	first := repository.Post{ID: 4, URLHandle: "part-1", Status: repository.PostStatusPublished}
+	draft := repository.Post{ID: 5, URLHandle: "part-2", Status: repository.PostStatusDraft}
+	post := repository.Post{ID: 6, URLHandle: "part-3", Status: repository.PostStatusPublished}
+	last := repository.Post{ID: 7, URLHandle: "part-4", Status: repository.PostStatusPublished}
+	series := repository.Series{
+		URLHandle: "go-tutorial",
+		Title:     "Go Tutorial",
+		Entries: []repository.SeriesEntry{
+			{PostID: first.ID, Position: 1, Post: first},
+			{PostID: draft.ID, Position: 2, Post: draft},
+			{PostID: post.ID, Position: 3, Post: post},
+			{PostID: last.ID, Position: 4, Post: last},
+		},
+	}
+	expectedNavigation := &repository.SeriesNavigation{
+		Series:   repository.Series{URLHandle: "go-tutorial", Title: "Go Tutorial"},
+		Position: 2,
+		Total:    3,
+		Previous: &first,
+		Next:     &last,
+	}
+
+	c.mostPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(post.ID).Return(series, nil)
+	c.mostPostRepository.EXPECT().GetAdjacentPosts(gomock.Any()).Return(nil, nil, nil)
+
+	p, err := c.sut.GetPost(post.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedNavigation, p.Series, "series navigation doesn't match the expected one")
+}
+
+// TestPostService_RestorePost tests restoring a post from the trash.
+func TestPostService_RestorePost(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{URLHandle: "testUrlHandle"}
+
+	c.mostPostRepository.EXPECT().RestorePost(postModel.URLHandle).Return(postModel, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(repository.SearchDocument{}).Return(nil)
+
+	p, err := c.sut.RestorePost(postModel.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, postModel, p, "restored post doesn't match the expected one")
+}
+
+// TestPostService_GetTrash tests getting the first page of trashed posts.
+func TestPostService_GetTrash(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	posts := []repository.Post{
+		{URLHandle: "trashed1"},
+		{URLHandle: "trashed2"},
+	}
+
+	c.mostPostRepository.EXPECT().GetTrashedPosts(repository.PostFilter{}, 1, 5).Return(posts, 6, nil)
+
+	p, pages, err := c.sut.GetTrash("")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, posts, p, "trashed posts don't match the expected output")
+	assert.Equal(t, 2, pages, "incorrect page count")
+}
+
+// TestPostService_GetTrashPage_Author tests that only the trashed posts of the given author are listed.
+func TestPostService_GetTrashPage_Author(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	author := repository.User{ID: 3, UserName: "testAuthor"}
+	posts := []repository.Post{
+		{URLHandle: "trashed1", Author: author},
+	}
+
+	c.mostUserRepository.EXPECT().GetUser(author.UserName).Return(author, nil)
+	c.mostPostRepository.EXPECT().GetTrashedPosts(repository.PostFilter{AuthorID: author.ID}, 2, 5).Return(posts, 6, nil)
+
+	p, pages, err := c.sut.GetTrashPage(author.UserName, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, posts, p, "trashed posts don't match the expected output")
+	assert.Equal(t, 2, pages, "incorrect page count")
+}
+
+// TestPostService_GetTrashPage_Invalid_Page tests getting trashed posts with an invalid page number.
+func TestPostService_GetTrashPage_Invalid_Page(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	_, _, err := c.sut.GetTrashPage("", 0)
+
+	assert.Equal(t, errortypes.InvalidPostPageError{Page: 0}, err, "error doesn't match expected one")
+}
+
+// TestPostService_PurgeTrash tests that only posts trashed before the retention period are purged.
+func TestPostService_PurgeTrash(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	c.mostPostRepository.EXPECT().PurgeTrashedPosts(gomock.Any()).DoAndReturn(func(deletedBefore time.Time) (int, error) {
+		assert.WithinDuration(t, time.Now().Add(-30*24*time.Hour), deletedBefore, time.Minute, "incorrect retention period")
+		return 2, nil
+	})
+
+	err := c.sut.PurgeTrash()
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_PurgeTrash_Unexpected_Error tests purging the trash with a repository error.
+func TestPostService_PurgeTrash_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	dbErr := fmt.Errorf("error")
+
+	c.mostPostRepository.EXPECT().PurgeTrashedPosts(gomock.Any()).Return(0, dbErr)
+
+	err := c.sut.PurgeTrash()
+
+	assert.Equal(t, dbErr, err, "should forward DB error to scheduler")
+}
+
+// TestPostService_RenamePost tests changing the URL handle of a post.
+func TestPostService_RenamePost(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{URLHandle: "newUrlHandle"}
+
+	c.mostPostRepository.EXPECT().RenamePost("oldUrlHandle", postModel.URLHandle).Return(postModel, nil)
+
+	p, err := c.sut.RenamePost("oldUrlHandle", postModel.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, postModel, p, "renamed post doesn't match the expected one")
+}
+
+// TestPostService_RenamePost_Missing_URL_Handle tests renaming a post without a new URL handle.
+func TestPostService_RenamePost_Missing_URL_Handle(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	_, err := c.sut.RenamePost("oldUrlHandle", "")
+
+	assert.Equal(t, errortypes.MissingUrlHandleError{}, err, "error doesn't match expected one")
+}
+
+// TestPostService_RenamePost_Same_URL_Handle tests that renaming a post to its current URL handle doesn't create a redirect.
+func TestPostService_RenamePost_Same_URL_Handle(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{URLHandle: "testUrlHandle"}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+
+	p, err := c.sut.RenamePost(postModel.URLHandle, postModel.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, postModel, p, "post doesn't match the expected one")
+}
+
+// TestPostService_GetPost_Redirect tests getting a post by the URL handle it had before it was renamed.
+func TestPostService_GetPost_Redirect(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{
+		URLHandle: "newUrlHandle",
+		Status:    repository.PostStatusPublished,
+	}
+	notFoundError := errortypes.PostNotFoundError{URLHandle: "oldUrlHandle"}
+	expectedError := errortypes.PostMovedError{URLHandle: "oldUrlHandle", NewURLHandle: postModel.URLHandle}
+
+	c.mostPostRepository.EXPECT().GetPost("oldUrlHandle").Return(repository.Post{}, notFoundError)
+	c.mostPostRepository.EXPECT().GetRedirectedPost("oldUrlHandle").Return(postModel, nil)
+
+	p, err := c.sut.GetPost("oldUrlHandle")
+
+	assert.Equal(t, expectedError, err, "should report the new URL handle")
+	assert.Equal(t, repository.Post{}, p, "should not return a post")
+}
+
+// TestPostService_GetPost_Redirect_Draft tests that redirects don't reveal the URL handle of unpublished posts.
+func TestPostService_GetPost_Redirect_Draft(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	postModel := repository.Post{
+		URLHandle: "newUrlHandle",
+		Status:    repository.PostStatusDraft,
+	}
+	notFoundError := errortypes.PostNotFoundError{URLHandle: "oldUrlHandle"}
+
+	c.mostPostRepository.EXPECT().GetPost("oldUrlHandle").Return(repository.Post{}, notFoundError)
+	c.mostPostRepository.EXPECT().GetRedirectedPost("oldUrlHandle").Return(postModel, nil)
+
+	_, err := c.sut.GetPost("oldUrlHandle")
+
+	assert.Equal(t, notFoundError, err, "unpublished posts should not be revealed")
+}
+
+// TestPostService_GetPost_Not_Found tests getting a post with a URL handle that never existed.
+func TestPostService_GetPost_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	notFoundError := errortypes.PostNotFoundError{URLHandle: "testUrlHandle"}
+
+	c.mostPostRepository.EXPECT().GetPost("testUrlHandle").Return(repository.Post{}, notFoundError)
+	c.mostPostRepository.EXPECT().GetRedirectedPost("testUrlHandle").Return(repository.Post{}, notFoundError)
+
+	_, err := c.sut.GetPost("testUrlHandle")
+
+	assert.Equal(t, notFoundError, err, "error doesn't match expected one")
+}
+
+// TestPostService_AddPost_Generated_URL_Handle tests adding a new post with a URL handle generated from its title.
+func TestPostService_AddPost_Generated_URL_Handle(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "Ünïcode & Go!"
+	userModel := repository.User{ID: 2, UserName: "testAuthor"}
+	newPost := repository.Post{Title: &title}
+	expectedPost := repository.Post{URLHandle: "unicode-and-go-3", AuthorID: userModel.ID, Title: &title}
+
+	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
+	c.mostPostRepository.EXPECT().GetReservedURLHandles("unicode-and-go").Return([]string{"unicode-and-go", "unicode-and-go-2"}, nil)
+	c.mostPostRepository.EXPECT().AddPost(expectedPost).Return(expectedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	p, err := c.sut.AddPost(newPost, userModel.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedPost, p, "added post doesn't match the expected one")
+}
+
+// TestPostService_AddPost_Generated_URL_Handle_Taken_Concurrently tests retrying when the generated URL handle gets taken.
+func TestPostService_AddPost_Generated_URL_Handle_Taken_Concurrently(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "Test Title"
+	userModel := repository.User{ID: 2, UserName: "testAuthor"}
+	newPost := repository.Post{Title: &title}
+	firstAttempt := repository.Post{URLHandle: "test-title", AuthorID: userModel.ID, Title: &title}
+	secondAttempt := repository.Post{URLHandle: "test-title-2", AuthorID: userModel.ID, Title: &title}
+
+	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
+	gomock.InOrder(
+		c.mostPostRepository.EXPECT().GetReservedURLHandles("test-title").Return(nil, nil),
+		c.mostPostRepository.EXPECT().AddPost(firstAttempt).Return(repository.Post{}, errortypes.DuplicateElementError{Key: "test-title"}),
+		c.mostPostRepository.EXPECT().GetReservedURLHandles("test-title").Return([]string{"test-title"}, nil),
+		c.mostPostRepository.EXPECT().AddPost(secondAttempt).Return(secondAttempt, nil),
+	)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	p, err := c.sut.AddPost(newPost, userModel.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, secondAttempt, p, "added post doesn't match the expected one")
+}
+
+// TestPostService_GetPost_Cached_Rendering tests that the rendering cached with the latest revision is reused.
+func TestPostService_GetPost_Cached_Rendering(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	policy, _ := render.ParsePolicy(render.DefaultElements, render.DefaultAttributes)
+	body := "testBody"
+	bodyHTML := "<p>cached</p>"
+	postModel := repository.Post{
+		ID:        2,
+		URLHandle: "testUrlHandle",
+		Body:      &body,
+		Status:    repository.PostStatusPublished,
+	}
+	revision := repository.Revision{
+		ID:        3,
+		Number:    1,
+		Body:      &body,
+		BodyHTML:  &bodyHTML,
+		RenderKey: render.CreateRenderer(policy).Key(repository.BodyFormatMarkdown),
+	}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(postModel.ID).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mostPostRepository.EXPECT().GetAdjacentPosts(postModel).Return(nil, nil, nil)
+	c.mockRevisionRepository.EXPECT().GetLatestRevision(postModel.ID).Return(revision, nil)
+
+	p, err := c.sut.GetPost(postModel.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, &bodyHTML, p.BodyHTML, "cached rendering should be used")
+}
+
+// TestPostService_GetPost_Outdated_Rendering tests that a rendering cached with another configuration is replaced.
+func TestPostService_GetPost_Outdated_Rendering(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	body := "testBody"
+	cachedHTML := "<p>cached</p>"
+	expectedHTML := "<p>testBody</p>\n"
+	postModel := repository.Post{
+		ID:        2,
+		URLHandle: "testUrlHandle",
+		Body:      &body,
+		Status:    repository.PostStatusPublished,
+	}
+	revision := repository.Revision{
+		ID:        3,
+		Number:    1,
+		Body:      &body,
+		BodyHTML:  &cachedHTML,
+		RenderKey: "outdatedKey",
+	}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(postModel.ID).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mostPostRepository.EXPECT().GetAdjacentPosts(postModel).Return(nil, nil, nil)
+	c.mockRevisionRepository.EXPECT().GetLatestRevision(postModel.ID).Return(revision, nil)
+	c.mockRevisionRepository.EXPECT().CacheRendering(revision.ID, expectedHTML, gomock.Not("outdatedKey")).Return(nil)
+
+	p, err := c.sut.GetPost(postModel.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, &expectedHTML, p.BodyHTML, "post should be rendered again")
+}
+
+// TestPostService_GetPost_Revision_Behind tests that a revision that doesn't match the post isn't used as cache.
+func TestPostService_GetPost_Revision_Behind(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	body := "newBody"
+	oldBody := "oldBody"
+	expectedHTML := "<p>newBody</p>\n"
+	postModel := repository.Post{
+		ID:        2,
+		URLHandle: "testUrlHandle",
+		Body:      &body,
+		Status:    repository.PostStatusPublished,
+	}
+	revision := repository.Revision{ID: 3, Number: 1, Body: &oldBody}
+
+	c.mostPostRepository.EXPECT().GetPost(postModel.URLHandle).Return(postModel, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(postModel.ID).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mostPostRepository.EXPECT().GetAdjacentPosts(postModel).Return(nil, nil, nil)
+	c.mockRevisionRepository.EXPECT().GetLatestRevision(postModel.ID).Return(revision, nil)
+
+	p, err := c.sut.GetPost(postModel.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, &expectedHTML, p.BodyHTML, "post should be rendered without caching")
+}
+
+// TestPostService_AddPost_Missing_Title tests that posts without a title are rejected.
+func TestPostService_AddPost_Missing_Title(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := " "
+	for _, newPost := range []repository.Post{{}, {URLHandle: "testUrlHandle", Title: &title}} {
+		expectedError := errortypes.MissingTitleError{URLHandle: newPost.URLHandle}
+
+		c.mostUserRepository.EXPECT().GetUser("testAuthor").Return(repository.User{UserName: "testAuthor"}, nil)
+
+		p, err := c.sut.AddPost(newPost, "testAuthor")
+
+		assert.Equal(t, expectedError, err, "error doesn't match expected one")
+		assert.Equal(t, repository.Post{}, p, "should not return a post")
+	}
+}
+
+// TestPostService_AddPost_Invalid_Body_Format tests adding a post with an unsupported body format.
+func TestPostService_AddPost_Invalid_Body_Format(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	newPost := repository.Post{
+		URLHandle:  "testUrlHandle",
+		Title:      &title,
+		BodyFormat: "rst",
+	}
+	expectedError := errortypes.InvalidBodyFormatError{Format: "rst"}
+
+	c.mostUserRepository.EXPECT().GetUser("testAuthor").Return(repository.User{UserName: "testAuthor"}, nil)
+
+	_, err := c.sut.AddPost(newPost, "testAuthor")
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestPostService_UpdatePost_Body_Format tests changing the body format of a post.
+func TestPostService_UpdatePost_Body_Format(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	updatedPost := repository.Post{
+		URLHandle:  "testUrlHandle",
+		BodyFormat: repository.BodyFormatHTML,
+	}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().GetPost(updatedPost.URLHandle).Return(repository.Post{URLHandle: updatedPost.URLHandle}, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(updatedPost, uint(2)).Return(updatedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	p, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, repository.BodyFormatHTML, p.BodyFormat, "body format should be changed")
+}
+
+// TestPostService_AddPost_Statistics tests that the excerpt, the word count and the reading time of a new post are
+// computed from the plain text of its body.
+func TestPostService_AddPost_Statistics(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	body := "# Heading\n\nSome **bold** text with a [link](https://example.com)."
+	userModel := repository.User{ID: 1, UserName: "testAuthor"}
+	newPost := repository.Post{URLHandle: "testUrlHandle", Title: &title, Body: &body}
+	excerpt := "Heading Some bold text with a link."
+	words := 7
+	minutes := 1
+	expectedPost := repository.Post{
+		URLHandle:   newPost.URLHandle,
+		Title:       &title,
+		AuthorID:    userModel.ID,
+		Body:        &body,
+		Excerpt:     &excerpt,
+		WordCount:   &words,
+		ReadingTime: &minutes,
+	}
+
+	c.mostUserRepository.EXPECT().GetUser(userModel.UserName).Return(userModel, nil)
+	c.mostPostRepository.EXPECT().AddPost(expectedPost).Return(expectedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	_, err := c.sut.AddPost(newPost, userModel.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_UpdatePost_Statistics_Body_Format tests that changing only the body format recomputes the
+// statistics from the current body of the post.
+func TestPostService_UpdatePost_Statistics_Body_Format(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	body := "Some **bold** text."
+	updatedPost := repository.Post{URLHandle: "testUrlHandle", BodyFormat: repository.BodyFormatText}
+	words := 3
+	minutes := 1
+	expectedPost := repository.Post{
+		URLHandle:   updatedPost.URLHandle,
+		BodyFormat:  repository.BodyFormatText,
+		Excerpt:     &body,
+		WordCount:   &words,
+		ReadingTime: &minutes,
+	}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().GetPost(updatedPost.URLHandle).Return(repository.Post{URLHandle: updatedPost.URLHandle, Body: &body}, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(expectedPost, uint(2)).Return(expectedPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(gomock.Any()).Return(nil)
+
+	_, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestPostService_UpdatePost_Search_Index_Error tests that the post is updated even if it can't be indexed.
+func TestPostService_UpdatePost_Search_Index_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	updatedPost := repository.Post{URLHandle: "testUrlHandle", Title: &title}
+	postModel := repository.Post{ID: 3, URLHandle: updatedPost.URLHandle, Title: &title}
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(updatedPost, uint(2)).Return(postModel, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(repository.SearchDocument{PostID: 3, Title: title}).Return(fmt.Errorf("error"))
+
+	p, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, postModel, p, "updated post doesn't match the expected one")
+}
+
+// TestPostService_UpdatePost_Revision_Error tests that the update fails and the post isn't indexed if its revision can't be recorded.
+func TestPostService_UpdatePost_Revision_Error(t *testing.T) {
+	t.Parallel()
+	c := createPostServiceContext(t)
+
+	title := "testTitle"
+	updatedPost := repository.Post{URLHandle: "testUrlHandle", Title: &title}
+	expectedError := fmt.Errorf("Error 1062: Duplicate entry '3-2' for key 'idx_revision_post_number'")
+
+	c.mostUserRepository.EXPECT().GetUser("testEditor").Return(repository.User{ID: 2, UserName: "testEditor"}, nil)
+	c.mostPostRepository.EXPECT().UpdatePost(updatedPost, uint(2)).Return(repository.Post{}, expectedError)
+
+	p, err := c.sut.UpdatePost(updatedPost, "testEditor")
+
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+	assert.Equal(t, repository.Post{}, p, "should not return a post")
+}
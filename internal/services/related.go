@@ -0,0 +1,125 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=related.go -destination=../mocks/mock_related_post_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/similarity"
+	"strings"
+)
+
+// RelatedPostService interface. Defines related post-related business logic.
+type RelatedPostService interface {
+	GetRelatedPosts(id string, limit int) ([]repository.Post, error)
+	UpdateRelatedPosts() error
+}
+
+// relatedPostService is the concrete implementation of the RelatedPostService interface.
+type relatedPostService struct {
+	cont     container.Container
+	renderer render.Renderer
+}
+
+// DefaultRelatedPostLimit sets how many related posts are returned if no limit is requested
+const DefaultRelatedPostLimit = 5
+
+// maxRelatedPosts sets how many related posts are stored per post, which is also the highest accepted limit
+const maxRelatedPosts = 10
+
+// relatedPostPageSize sets how many posts are loaded at once while computing the related posts
+const relatedPostPageSize = 100
+
+// titleWeight sets how many times the title is counted, as it describes the post better than the body
+const titleWeight = 3
+
+// CreateRelatedPostService instantiates the relatedPostService using the application container.
+func CreateRelatedPostService(cont container.Container) RelatedPostService {
+	return &relatedPostService{cont, loadRenderer(cont)}
+}
+
+// GetRelatedPosts retrieves the published posts most similar to the published post with the given URL handle.
+// The related posts are precomputed by UpdateRelatedPosts, posts added since then have none.
+func (r relatedPostService) GetRelatedPosts(urlHandle string, limit int) ([]repository.Post, error) {
+	log := r.cont.GetLogger()
+	postRepository := r.cont.GetPostRepository()
+	relatedPostRepository := r.cont.GetRelatedPostRepository()
+
+	if limit < 1 || limit > maxRelatedPosts {
+		log.Errorf("invalid related post limit %d", limit)
+		return nil, errortypes.InvalidRelatedPostLimitError{Limit: limit}
+	}
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	if !post.IsPublished() {
+		log.Debugf("post %s is not published, status: %s", urlHandle, post.Status)
+		return nil, errortypes.PostNotFoundError{URLHandle: urlHandle}
+	}
+
+	return relatedPostRepository.GetRelatedPosts(post.ID, limit)
+}
+
+// UpdateRelatedPosts recomputes the related posts of every published post by the TF-IDF similarity
+// of their title, summary and body.
+func (r relatedPostService) UpdateRelatedPosts() error {
+	log := r.cont.GetLogger()
+	postRepository := r.cont.GetPostRepository()
+	relatedPostRepository := r.cont.GetRelatedPostRepository()
+
+	var documents []similarity.Document
+	for page := 1; ; page++ {
+		posts, count, err := postRepository.GetPosts(repository.PostFilter{}, page, relatedPostPageSize)
+		if err != nil {
+			log.Errorf("failed to load posts for computing related posts: %v", err)
+			return err
+		}
+
+		for _, post := range posts {
+			documents = append(documents, similarity.Document{ID: post.ID, Text: r.documentText(post)})
+		}
+
+		if page*relatedPostPageSize >= count {
+			break
+		}
+	}
+
+	var relatedPosts []repository.RelatedPost
+	for postID, matches := range similarity.Related(documents, maxRelatedPosts) {
+		for i, match := range matches {
+			relatedPosts = append(relatedPosts, repository.RelatedPost{
+				PostID:        postID,
+				RelatedPostID: match.ID,
+				Position:      i + 1,
+				Score:         match.Score,
+			})
+		}
+	}
+
+	if err := relatedPostRepository.ReplaceRelatedPosts(relatedPosts); err != nil {
+		log.Errorf("failed to store related posts: %v", err)
+		return err
+	}
+
+	log.Infof("updated related posts of %d posts", len(documents))
+	return nil
+}
+
+// documentText returns the plain text the similarity of the post is computed from.
+func (r relatedPostService) documentText(post repository.Post) string {
+	log := r.cont.GetLogger()
+
+	body, err := r.renderer.PlainText(post.BodyFormat, derefString(post.Body))
+	if err != nil {
+		log.Errorf("failed to convert body of post %s to plain text: %v", post.URLHandle, err)
+		body = derefString(post.Body)
+	}
+
+	title := strings.Repeat(derefString(post.Title)+" ", titleWeight)
+	return title + derefString(post.Summary) + " " + body
+}
@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// relatedPostTestContext contains objects relevant for testing the RelatedPostService.
+type relatedPostTestContext struct {
+	mockPostRepository        *mocks.MockPostRepository
+	mockRelatedPostRepository *mocks.MockRelatedPostRepository
+	sut                       services.RelatedPostService
+}
+
+// createRelatedPostServiceContext creates the context for testing the RelatedPostService and reduces code duplication.
+func createRelatedPostServiceContext(t *testing.T) *relatedPostTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	mockRelatedPostRepository := mocks.NewMockRelatedPostRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), mockPostRepository, nil, nil, nil, nil, nil, nil, mockRelatedPostRepository, nil, nil, nil, nil)
+	sut := services.CreateRelatedPostService(cont)
+
+	return &relatedPostTestContext{mockPostRepository, mockRelatedPostRepository, sut}
+}
+
+// TestRelatedPostService_GetRelatedPosts tests getting the related posts of a published post.
+func TestRelatedPostService_GetRelatedPosts(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostServiceContext(t)
+
+	post := repository.Post{ID: 3, URLHandle: "testUrlHandle", Status: repository.PostStatusPublished}
+	related := []repository.Post{{ID: 4, URLHandle: "related"}}
+
+	c.mockPostRepository.EXPECT().GetPost("testUrlHandle").Return(post, nil)
+	c.mockRelatedPostRepository.EXPECT().GetRelatedPosts(uint(3), 2).Return(related, nil)
+
+	posts, err := c.sut.GetRelatedPosts("testUrlHandle", 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, related, posts, "incorrect related posts")
+}
+
+// TestRelatedPostService_GetRelatedPosts_Invalid_Limit tests getting related posts with a limit out of range.
+func TestRelatedPostService_GetRelatedPosts_Invalid_Limit(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostServiceContext(t)
+
+	for _, limit := range []int{0, 11} {
+		_, err := c.sut.GetRelatedPosts("testUrlHandle", limit)
+
+		assert.Equal(t, errortypes.InvalidRelatedPostLimitError{Limit: limit}, err, "incorrect error type")
+	}
+}
+
+// TestRelatedPostService_GetRelatedPosts_Draft tests that drafts are reported as missing.
+func TestRelatedPostService_GetRelatedPosts_Draft(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostServiceContext(t)
+
+	post := repository.Post{ID: 3, URLHandle: "testUrlHandle", Status: repository.PostStatusDraft}
+
+	c.mockPostRepository.EXPECT().GetPost("testUrlHandle").Return(post, nil)
+
+	_, err := c.sut.GetRelatedPosts("testUrlHandle", 5)
+
+	assert.Equal(t, errortypes.PostNotFoundError{URLHandle: "testUrlHandle"}, err, "incorrect error type")
+}
+
+// TestRelatedPostService_UpdateRelatedPosts tests recomputing the related posts of every published post.
+func TestRelatedPostService_UpdateRelatedPosts(t *testing.T) {
+	t.Parallel()
+	c := createRelatedPostServiceContext(t)
+
+	first, second, third := "Goroutines in Go", "Leaking goroutines", "Sourdough bread"
+	body := "Channels connect **goroutines**."
+	posts := []repository.Post{
+		{ID: 1, Title: &first, Body: &body},
+		{ID: 2, Title: &second},
+		{ID: 3, Title: &third},
+	}
+
+	c.mockPostRepository.EXPECT().GetPosts(repository.PostFilter{}, 1, 100).Return(posts, len(posts), nil)
+	c.mockRelatedPostRepository.EXPECT().ReplaceRelatedPosts(gomock.Any()).DoAndReturn(func(relatedPosts []repository.RelatedPost) error {
+		assert.Equal(t, 2, len(relatedPosts), "incorrect number of related posts")
+
+		for _, relatedPost := range relatedPosts {
+			assert.Equal(t, 1, relatedPost.Position, "incorrect position")
+			assert.NotEqual(t, uint(3), relatedPost.PostID, "unrelated post shouldn't be matched")
+			assert.NotEqual(t, uint(3), relatedPost.RelatedPostID, "unrelated post shouldn't be matched")
+		}
+
+		return nil
+	})
+
+	err := c.sut.UpdateRelatedPosts()
+
+	assert.Nil(t, err, "should complete without error")
+}
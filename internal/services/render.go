@@ -0,0 +1,123 @@
+package services
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"os"
+	"strconv"
+)
+
+// defaultExcerptLength sets the maximum number of characters of generated excerpts if EXCERPT_LENGTH is not set
+const defaultExcerptLength = 300
+
+// wordsPerMinute is the assumed reading speed used to estimate the reading time of posts
+const wordsPerMinute = 200
+
+// loadRenderer creates the post renderer with the HTML policy read from the HTML_ALLOWED_ELEMENTS and
+// HTML_ALLOWED_ATTRIBUTES environment variables. If the policy is invalid, the default policy is used.
+func loadRenderer(cont container.Container) render.Renderer {
+	log := cont.GetLogger()
+
+	elements, found := os.LookupEnv("HTML_ALLOWED_ELEMENTS")
+	if !found {
+		elements = render.DefaultElements
+	}
+
+	attributes, found := os.LookupEnv("HTML_ALLOWED_ATTRIBUTES")
+	if !found {
+		attributes = render.DefaultAttributes
+	}
+
+	policy, err := render.ParsePolicy(elements, attributes)
+	if err != nil {
+		log.Errorf("invalid HTML policy, falling back to the default policy: %v", err)
+		policy, _ = render.ParsePolicy(render.DefaultElements, render.DefaultAttributes)
+	}
+
+	return render.CreateRenderer(policy)
+}
+
+// renderPost attaches the body of the post rendered to sanitized HTML, using the renderer of the body format,
+// and the table of contents listing the anchored headings of the HTML.
+// The rendering is cached with the latest revision of the post, so that a revision is only rendered once.
+// Rendering errors are logged and leave the post without HTML.
+func renderPost(cont container.Container, renderer render.Renderer, post *repository.Post) {
+	log := cont.GetLogger()
+	revisionRepository := cont.GetRevisionRepository()
+
+	if post.Body == nil {
+		return
+	}
+
+	revision, err := revisionRepository.GetLatestRevision(post.ID)
+	if err != nil {
+		log.Errorf("failed to get latest revision of post %s: %v", post.URLHandle, err)
+	}
+
+	// The revision might lag behind the post while it is being updated, its rendering can't be used then
+	cacheable := err == nil && derefString(revision.Body) == *post.Body
+	if cacheable && revision.BodyHTML != nil && revision.RenderKey == renderer.Key(post.BodyFormat) {
+		post.BodyHTML = revision.BodyHTML
+		post.Contents = render.TableOfContents(*revision.BodyHTML)
+		return
+	}
+
+	bodyHTML, err := renderer.Render(post.BodyFormat, *post.Body)
+	if err != nil {
+		log.Errorf("failed to render post %s: %v", post.URLHandle, err)
+		return
+	}
+
+	post.BodyHTML = &bodyHTML
+	post.Contents = render.TableOfContents(bodyHTML)
+
+	if !cacheable {
+		return
+	}
+
+	if err := revisionRepository.CacheRendering(revision.ID, bodyHTML, renderer.Key(post.BodyFormat)); err != nil {
+		log.Errorf("failed to cache rendering of revision %d of post %s: %v", revision.Number, post.URLHandle, err)
+	}
+}
+
+// loadExcerptLength reads the maximum length of generated post excerpts from the EXCERPT_LENGTH environment variable.
+// If the variable is missing or invalid, the default length is used.
+func loadExcerptLength(cont container.Container) int {
+	log := cont.GetLogger()
+
+	value := os.Getenv("EXCERPT_LENGTH")
+	if value == "" {
+		return defaultExcerptLength
+	}
+
+	length, err := strconv.Atoi(value)
+	if err != nil || length < 1 {
+		log.Errorf("invalid EXCERPT_LENGTH value \"%s\", falling back to %d", value, defaultExcerptLength)
+		return defaultExcerptLength
+	}
+
+	return length
+}
+
+// analyzePost sets the excerpt, the word count and the reading time of the post, computed from the plain text of
+// its body written in the given format. Posts without body are left unchanged.
+func analyzePost(renderer render.Renderer, excerptLength int, post *repository.Post, format repository.BodyFormat) error {
+	if post.Body == nil {
+		return nil
+	}
+
+	text, err := renderer.PlainText(format, derefString(post.Body))
+	if err != nil {
+		return err
+	}
+
+	excerpt := render.Excerpt(text, excerptLength)
+	words := render.CountWords(text)
+	minutes := (words + wordsPerMinute - 1) / wordsPerMinute
+
+	post.Excerpt = &excerpt
+	post.WordCount = &words
+	post.ReadingTime = &minutes
+	return nil
+}
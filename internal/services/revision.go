@@ -0,0 +1,184 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=revision.go -destination=../mocks/mock_revision_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/diff"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"strconv"
+)
+
+// RevisionDiff is the line-based diff of the post body between two revisions.
+// Revision 0 stands for the empty post before the first revision.
+type RevisionDiff struct {
+	From  int
+	To    int
+	Lines []diff.Line
+}
+
+// RevisionService interface. Defines revision-related business logic.
+type RevisionService interface {
+	GetRevisions(urlHandle string) ([]repository.Revision, error)
+	GetRevision(urlHandle string, number int) (repository.Revision, error)
+	DiffRevisions(urlHandle string, from *int, to int) (RevisionDiff, error)
+	RestoreRevision(urlHandle string, number int, editorName string) (repository.Post, error)
+}
+
+// revisionService is the concrete implementation of the RevisionService interface.
+type revisionService struct {
+	cont          container.Container
+	renderer      render.Renderer
+	excerptLength int
+}
+
+// CreateRevisionService instantiates the revisionService using the application container.
+func CreateRevisionService(cont container.Container) RevisionService {
+	return &revisionService{cont, loadRenderer(cont), loadExcerptLength(cont)}
+}
+
+// GetRevisions retrieves every revision of the post with the given URL handle, most recent first.
+func (r revisionService) GetRevisions(urlHandle string) ([]repository.Revision, error) {
+	postRepository := r.cont.GetPostRepository()
+	revisionRepository := r.cont.GetRevisionRepository()
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	return revisionRepository.GetRevisions(post.ID)
+}
+
+// GetRevision retrieves the revision of the post with the given URL handle and revision number.
+func (r revisionService) GetRevision(urlHandle string, number int) (repository.Revision, error) {
+	postRepository := r.cont.GetPostRepository()
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return repository.Revision{}, err
+	}
+
+	return r.getRevision(post, number)
+}
+
+// DiffRevisions computes the line-based diff of the post body between two revisions of the post.
+// If no earlier revision is given, the revision is compared with the one preceding it.
+// Bodies differing in more than diff.MaxLines lines are not compared.
+func (r revisionService) DiffRevisions(urlHandle string, from *int, to int) (RevisionDiff, error) {
+	log := r.cont.GetLogger()
+	postRepository := r.cont.GetPostRepository()
+
+	if from == nil {
+		previous := to - 1
+		from = &previous
+	}
+
+	if *from < 0 {
+		log.Errorf("invalid revision %d of post %s", *from, urlHandle)
+		return RevisionDiff{}, errortypes.InvalidRevisionError{Revision: strconv.Itoa(*from)}
+	}
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	newRevision, err := r.getRevision(post, to)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	var oldRevision repository.Revision
+	if *from > 0 {
+		if oldRevision, err = r.getRevision(post, *from); err != nil {
+			return RevisionDiff{}, err
+		}
+	}
+
+	lines, err := diff.Lines(derefString(oldRevision.Body), derefString(newRevision.Body))
+	if err != nil {
+		log.Errorf("failed to compare revisions %d and %d of post %s: %v", *from, to, urlHandle, err)
+		return RevisionDiff{}, errortypes.RevisionDiffTooLargeError{URLHandle: urlHandle, From: *from, To: to}
+	}
+
+	return RevisionDiff{
+		From:  *from,
+		To:    to,
+		Lines: lines,
+	}, nil
+}
+
+// RestoreRevision replaces the title, the summary, the body and the body format of the post with the content of the given revision.
+// The restored content is saved as a new revision, so the history of the post is never rewritten.
+func (r revisionService) RestoreRevision(urlHandle string, number int, editorName string) (repository.Post, error) {
+	log := r.cont.GetLogger()
+	postRepository := r.cont.GetPostRepository()
+	userRepository := r.cont.GetUserRepository()
+
+	editor, err := userRepository.GetUser(editorName)
+	if err != nil {
+		log.Errorf("failed to get editor %s of post %s", editorName, urlHandle)
+		return repository.Post{}, err
+	}
+
+	post, err := postRepository.GetPost(urlHandle)
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	revision, err := r.getRevision(post, number)
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	restored := repository.Post{
+		URLHandle:  urlHandle,
+		Title:      revision.Title,
+		Summary:    revision.Summary,
+		Body:       revision.Body,
+		BodyFormat: revision.BodyFormat,
+	}
+
+	// Revisions saved before their body format was recorded keep the current format of the post
+	if restored.BodyFormat == "" {
+		restored.BodyFormat = post.BodyFormat
+	}
+
+	if err := analyzePost(r.renderer, r.excerptLength, &restored, restored.BodyFormat); err != nil {
+		log.Errorf("failed to analyze body of revision %d of post %s: %v", number, urlHandle, err)
+		return repository.Post{}, err
+	}
+
+	log.Infof("restoring revision %d of post %s", number, urlHandle)
+	post, err = postRepository.ReplacePostContent(restored, editor.ID)
+	if err != nil {
+		return repository.Post{}, err
+	}
+
+	indexPost(r.cont, r.renderer, post)
+	return post, nil
+}
+
+// getRevision retrieves the revision of the given post and reports missing revisions with the URL handle of the post.
+func (r revisionService) getRevision(post repository.Post, number int) (repository.Revision, error) {
+	revisionRepository := r.cont.GetRevisionRepository()
+
+	revision, err := revisionRepository.GetRevision(post.ID, number)
+	if _, ok := err.(errortypes.RevisionNotFoundError); ok {
+		return repository.Revision{}, errortypes.RevisionNotFoundError{URLHandle: post.URLHandle, Number: number}
+	}
+
+	return revision, err
+}
+
+// derefString returns the value of the string or an empty string if there is none.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
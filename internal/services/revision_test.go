@@ -0,0 +1,224 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/diff"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+)
+
+// revisionTestContext contains objects relevant for testing the RevisionService.
+type revisionTestContext struct {
+	mockPostRepository     *mocks.MockPostRepository
+	mockUserRepository     *mocks.MockUserRepository
+	mockRevisionRepository *mocks.MockRevisionRepository
+	mockSearchRepository   *mocks.MockSearchRepository
+	sut                    services.RevisionService
+}
+
+// createRevisionServiceContext creates the context for testing the RevisionService and reduces code duplication.
+func createRevisionServiceContext(t *testing.T) *revisionTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
+	mockRevisionRepository := mocks.NewMockRevisionRepository(mockCtrl)
+	mockSearchRepository := mocks.NewMockSearchRepository(mockCtrl)
+	cont := container.CreateContainer(
+		logger.CreateLogger(),
+		mockPostRepository,
+		mockUserRepository,
+		nil,
+		nil,
+		nil,
+		mockRevisionRepository,
+		mockSearchRepository,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+	)
+	sut := services.CreateRevisionService(cont)
+
+	return &revisionTestContext{mockPostRepository, mockUserRepository, mockRevisionRepository, mockSearchRepository, sut}
+}
+
+// TestRevisionService_GetRevisions tests listing the revisions of a post.
+func TestRevisionService_GetRevisions(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle"}
+	revisions := []repository.Revision{{PostID: 4, Number: 2}, {PostID: 4, Number: 1}}
+
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevisions(post.ID).Return(revisions, nil)
+
+	r, err := c.sut.GetRevisions(post.URLHandle)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, revisions, r, "revisions don't match the expected ones")
+}
+
+// TestRevisionService_GetRevision_Not_Found tests retrieving a non-existing revision.
+func TestRevisionService_GetRevision_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle"}
+	expectedError := errortypes.RevisionNotFoundError{URLHandle: post.URLHandle, Number: 3}
+
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 3).Return(repository.Revision{}, errortypes.RevisionNotFoundError{Number: 3})
+
+	_, err := c.sut.GetRevision(post.URLHandle, 3)
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestRevisionService_DiffRevisions tests comparing a revision with the one preceding it.
+func TestRevisionService_DiffRevisions(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	oldBody := "first\nsecond"
+	newBody := "first\nchanged"
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle"}
+	expectedDiff := services.RevisionDiff{
+		From: 1,
+		To:   2,
+		Lines: []diff.Line{
+			{Operation: diff.Equal, Text: "first"},
+			{Operation: diff.Delete, Text: "second"},
+			{Operation: diff.Insert, Text: "changed"},
+		},
+	}
+
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 2).Return(repository.Revision{Number: 2, Body: &newBody}, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 1).Return(repository.Revision{Number: 1, Body: &oldBody}, nil)
+
+	d, err := c.sut.DiffRevisions(post.URLHandle, nil, 2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedDiff, d, "diff doesn't match the expected one")
+}
+
+// TestRevisionService_DiffRevisions_Too_Large tests comparing revisions whose bodies differ in too many lines.
+func TestRevisionService_DiffRevisions_Too_Large(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	oldBody := strings.Repeat("old\n", diff.MaxLines+1)
+	newBody := strings.Repeat("new\n", diff.MaxLines+1)
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle"}
+	expectedError := errortypes.RevisionDiffTooLargeError{URLHandle: post.URLHandle, From: 1, To: 2}
+
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 2).Return(repository.Revision{Number: 2, Body: &newBody}, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 1).Return(repository.Revision{Number: 1, Body: &oldBody}, nil)
+
+	_, err := c.sut.DiffRevisions(post.URLHandle, nil, 2)
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestRevisionService_DiffRevisions_First_Revision tests comparing the first revision with the empty post.
+func TestRevisionService_DiffRevisions_First_Revision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	body := "first"
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle"}
+	expectedDiff := services.RevisionDiff{
+		From:  0,
+		To:    1,
+		Lines: []diff.Line{{Operation: diff.Insert, Text: "first"}},
+	}
+
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 1).Return(repository.Revision{Number: 1, Body: &body}, nil)
+
+	d, err := c.sut.DiffRevisions(post.URLHandle, nil, 1)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedDiff, d, "diff doesn't match the expected one")
+}
+
+// TestRevisionService_DiffRevisions_Invalid_Revision tests comparing a revision with a negative revision number.
+func TestRevisionService_DiffRevisions_Invalid_Revision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	from := -1
+	expectedError := errortypes.InvalidRevisionError{Revision: "-1"}
+
+	_, err := c.sut.DiffRevisions("testUrlHandle", &from, 2)
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestRevisionService_RestoreRevision tests that restoring a revision saves its content as a new revision.
+func TestRevisionService_RestoreRevision(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	title := "Old Title"
+	body := "Old Body"
+	words := 2
+	minutes := 1
+	editor := repository.User{ID: 2, UserName: "testEditor"}
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle"}
+	revision := repository.Revision{PostID: post.ID, Number: 1, Title: &title, Body: &body}
+	restoredPost := repository.Post{ID: 4, URLHandle: post.URLHandle, Title: &title, Body: &body}
+
+	c.mockUserRepository.EXPECT().GetUser(editor.UserName).Return(editor, nil)
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 1).Return(revision, nil)
+	c.mockPostRepository.EXPECT().
+		ReplacePostContent(repository.Post{URLHandle: post.URLHandle, Title: &title, Body: &body, Excerpt: &body, WordCount: &words, ReadingTime: &minutes}, editor.ID).
+		Return(restoredPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(repository.SearchDocument{PostID: 4, Title: title, Body: body}).Return(nil)
+
+	p, err := c.sut.RestoreRevision(post.URLHandle, 1, editor.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, restoredPost, p, "restored post doesn't match the expected one")
+}
+
+// TestRevisionService_RestoreRevision_Body_Format tests that the body format of the revision is restored along with the body.
+func TestRevisionService_RestoreRevision_Body_Format(t *testing.T) {
+	t.Parallel()
+	c := createRevisionServiceContext(t)
+
+	body := "<p>Old Body</p>"
+	text := "Old Body"
+	words := 2
+	minutes := 1
+	editor := repository.User{ID: 2, UserName: "testEditor"}
+	post := repository.Post{ID: 4, URLHandle: "testUrlHandle", BodyFormat: repository.BodyFormatMarkdown}
+	revision := repository.Revision{PostID: post.ID, Number: 1, Body: &body, BodyFormat: repository.BodyFormatHTML}
+	restoredPost := repository.Post{ID: 4, URLHandle: post.URLHandle, Body: &body, BodyFormat: repository.BodyFormatHTML}
+
+	c.mockUserRepository.EXPECT().GetUser(editor.UserName).Return(editor, nil)
+	c.mockPostRepository.EXPECT().GetPost(post.URLHandle).Return(post, nil)
+	c.mockRevisionRepository.EXPECT().GetRevision(post.ID, 1).Return(revision, nil)
+	c.mockPostRepository.EXPECT().
+		ReplacePostContent(repository.Post{URLHandle: post.URLHandle, Body: &body, BodyFormat: repository.BodyFormatHTML, Excerpt: &text, WordCount: &words, ReadingTime: &minutes}, editor.ID).
+		Return(restoredPost, nil)
+	c.mockSearchRepository.EXPECT().IndexPost(repository.SearchDocument{PostID: 4, Body: text}).Return(nil)
+
+	p, err := c.sut.RestoreRevision(post.URLHandle, 1, editor.UserName)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, restoredPost, p, "restored post doesn't match the expected one")
+}
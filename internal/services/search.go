@@ -0,0 +1,119 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=search.go -destination=../mocks/mock_search_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/render"
+	"github.com/wlachs/blog/internal/repository"
+	"html"
+	"math"
+	"strings"
+)
+
+// SearchResult is a post matching a search query, along with an HTML snippet highlighting the matching words.
+type SearchResult struct {
+	Post    repository.Post
+	Snippet string
+}
+
+// SearchService interface. Defines search-related business logic.
+type SearchService interface {
+	Search(query string) ([]SearchResult, int, error)
+	SearchPage(query string, page int) ([]SearchResult, int, error)
+}
+
+// searchService is the concrete implementation of the SearchService interface.
+type searchService struct {
+	cont container.Container
+}
+
+// snippetLength sets the approximate length of the snippets of search results
+const snippetLength = 200
+
+// CreateSearchService instantiates the searchService using the application container.
+func CreateSearchService(cont container.Container) SearchService {
+	return &searchService{cont}
+}
+
+// Search retrieves the first page of posts matching the query.
+func (s searchService) Search(query string) ([]SearchResult, int, error) {
+	return s.SearchPage(query, 1)
+}
+
+// SearchPage retrieves one page of posts matching the query, most relevant first.
+func (s searchService) SearchPage(query string, page int) ([]SearchResult, int, error) {
+	log := s.cont.GetLogger()
+	searchRepository := s.cont.GetSearchRepository()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		log.Errorf("missing search query")
+		return nil, -1, errortypes.MissingSearchQueryError{}
+	}
+
+	if page < 1 {
+		log.Errorf("invalid search page number %d", page)
+		return nil, -1, errortypes.InvalidPostPageError{Page: page}
+	}
+
+	documents, count, err := searchRepository.Search(query, page, postPageSize)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	results := make([]SearchResult, 0, len(documents))
+	for _, document := range documents {
+		results = append(results, SearchResult{Post: document.Post, Snippet: snippet(document, query)})
+	}
+
+	pages := int(math.Ceil(float64(count) / float64(postPageSize)))
+	return results, pages, nil
+}
+
+// snippet highlights the query in the body or the summary of the document.
+// If neither of them contains the words of the query, the beginning of the body is used.
+func snippet(document repository.SearchDocument, query string) string {
+	for _, text := range []string{document.Body, document.Summary} {
+		if highlighted, found := render.Highlight(text, query, snippetLength); found {
+			return highlighted
+		}
+	}
+
+	return html.EscapeString(render.Excerpt(document.Body, snippetLength))
+}
+
+// indexPost updates the search index with the current content of the post.
+// The post is saved even if indexing fails, so errors are only logged.
+func indexPost(cont container.Container, renderer render.Renderer, post repository.Post) {
+	log := cont.GetLogger()
+	searchRepository := cont.GetSearchRepository()
+
+	body, err := renderer.PlainText(post.BodyFormat, derefString(post.Body))
+	if err != nil {
+		log.Errorf("failed to convert body of post %s to plain text: %v", post.URLHandle, err)
+		body = derefString(post.Body)
+	}
+
+	err = searchRepository.IndexPost(repository.SearchDocument{
+		PostID:  post.ID,
+		Title:   derefString(post.Title),
+		Summary: derefString(post.Summary),
+		Body:    body,
+	})
+
+	if err != nil {
+		log.Errorf("failed to index post %s: %v", post.URLHandle, err)
+	}
+}
+
+// removeFromIndex removes the post from the search index. Errors are only logged.
+func removeFromIndex(cont container.Container, post repository.Post) {
+	log := cont.GetLogger()
+	searchRepository := cont.GetSearchRepository()
+
+	if err := searchRepository.RemovePost(post.ID); err != nil {
+		log.Errorf("failed to remove post %s from the search index: %v", post.URLHandle, err)
+	}
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// searchTestContext contains objects relevant for testing the SearchService.
+type searchTestContext struct {
+	mockSearchRepository *mocks.MockSearchRepository
+	sut                  services.SearchService
+}
+
+// createSearchServiceContext creates the context for testing the SearchService and reduces code duplication.
+func createSearchServiceContext(t *testing.T) *searchTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockSearchRepository := mocks.NewMockSearchRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, mockSearchRepository, nil, nil, nil, nil, nil)
+	sut := services.CreateSearchService(cont)
+
+	return &searchTestContext{mockSearchRepository, sut}
+}
+
+// TestSearchService_Search tests searching posts with highlighted snippets.
+func TestSearchService_Search(t *testing.T) {
+	t.Parallel()
+	c := createSearchServiceContext(t)
+
+	documents := []repository.SearchDocument{
+		{
+			PostID: 1,
+			Post:   repository.Post{ID: 1, URLHandle: "first"},
+			Body:   "Goroutines are cheap, but leaking <goroutines> isn't.",
+		},
+		{
+			PostID:  2,
+			Post:    repository.Post{ID: 2, URLHandle: "second"},
+			Summary: "All about Goroutines",
+			Body:    "Nothing to see here.",
+		},
+		{
+			PostID: 3,
+			Post:   repository.Post{ID: 3, URLHandle: "third"},
+			Title:  "Goroutines",
+			Body:   "No match in the body & summary.",
+		},
+	}
+	expectedResults := []services.SearchResult{
+		{Post: documents[0].Post, Snippet: "<mark>Goroutines</mark> are cheap, but leaking &lt;<mark>goroutines</mark>&gt; isn&#39;t."},
+		{Post: documents[1].Post, Snippet: "All about <mark>Goroutines</mark>"},
+		{Post: documents[2].Post, Snippet: "No match in the body &amp; summary."},
+	}
+
+	c.mockSearchRepository.EXPECT().Search("goroutines", 1, 5).Return(documents, 6, nil)
+
+	results, pages, err := c.sut.Search(" goroutines ")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedResults, results, "incorrect search results")
+	assert.Equal(t, 2, pages, "incorrect page count")
+}
+
+// TestSearchService_SearchPage_Missing_Query tests searching without a query.
+func TestSearchService_SearchPage_Missing_Query(t *testing.T) {
+	t.Parallel()
+	c := createSearchServiceContext(t)
+
+	_, _, err := c.sut.SearchPage("  ", 1)
+
+	assert.Equal(t, errortypes.MissingSearchQueryError{}, err, "error doesn't match expected one")
+}
+
+// TestSearchService_SearchPage_Invalid_Page tests searching with an invalid page number.
+func TestSearchService_SearchPage_Invalid_Page(t *testing.T) {
+	t.Parallel()
+	c := createSearchServiceContext(t)
+
+	_, _, err := c.sut.SearchPage("go", 0)
+
+	assert.Equal(t, errortypes.InvalidPostPageError{Page: 0}, err, "error doesn't match expected one")
+}
+
+// TestSearchService_SearchPage_Error tests that errors of the search backend are forwarded.
+func TestSearchService_SearchPage_Error(t *testing.T) {
+	t.Parallel()
+	c := createSearchServiceContext(t)
+
+	dbErr := fmt.Errorf("error")
+
+	c.mockSearchRepository.EXPECT().Search("go", 2, 5).Return(nil, -1, dbErr)
+
+	_, _, err := c.sut.SearchPage("go", 2)
+
+	assert.Equal(t, dbErr, err, "should forward DB error to controller")
+}
@@ -0,0 +1,180 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=series.go -destination=../mocks/mock_series_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+)
+
+// SeriesService interface. Defines series-related business logic.
+type SeriesService interface {
+	AddSeries(newSeries repository.Series) (repository.Series, error)
+	UpdateSeries(updatedSeries repository.Series) (repository.Series, error)
+	DeleteSeries(urlHandle string) error
+	GetSeries(urlHandle string) (repository.Series, error)
+	GetAllSeries() ([]repository.Series, error)
+}
+
+// seriesService is the concrete implementation of the SeriesService interface.
+type seriesService struct {
+	cont container.Container
+}
+
+// CreateSeriesService instantiates the seriesService using the application container.
+func CreateSeriesService(cont container.Container) SeriesService {
+	return &seriesService{cont}
+}
+
+// AddSeries adds a new series to the blog.
+// The posts of the series are identified by the URL handles of the entries and ordered as listed.
+func (s seriesService) AddSeries(newSeries repository.Series) (repository.Series, error) {
+	log := s.cont.GetLogger()
+	seriesRepository := s.cont.GetSeriesRepository()
+
+	if newSeries.Title == "" {
+		log.Errorf("missing title of series %s", newSeries.URLHandle)
+		return repository.Series{}, errortypes.MissingSeriesTitleError{}
+	}
+
+	entries, err := s.resolveEntries(newSeries)
+	if err != nil {
+		return repository.Series{}, err
+	}
+	newSeries.Entries = entries
+
+	log.Infof("adding new series %v", newSeries)
+	return seriesRepository.AddSeries(newSeries)
+}
+
+// UpdateSeries updates the title, the description or the posts of an existing series.
+// If entries are provided, they replace the current posts of the series in the listed order.
+func (s seriesService) UpdateSeries(updatedSeries repository.Series) (repository.Series, error) {
+	log := s.cont.GetLogger()
+	seriesRepository := s.cont.GetSeriesRepository()
+
+	entries, err := s.resolveEntries(updatedSeries)
+	if err != nil {
+		return repository.Series{}, err
+	}
+	updatedSeries.Entries = entries
+
+	log.Infof("updating series %v", updatedSeries)
+	return seriesRepository.UpdateSeries(updatedSeries)
+}
+
+// resolveEntries looks up the posts of the series by their URL handles and numbers them in the listed order.
+// Every post may be listed once and must not belong to another series.
+func (s seriesService) resolveEntries(series repository.Series) ([]repository.SeriesEntry, error) {
+	log := s.cont.GetLogger()
+	postRepository := s.cont.GetPostRepository()
+	seriesRepository := s.cont.GetSeriesRepository()
+
+	if series.Entries == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(series.Entries))
+	entries := make([]repository.SeriesEntry, 0, len(series.Entries))
+
+	for i, entry := range series.Entries {
+		urlHandle := entry.Post.URLHandle
+		if seen[urlHandle] {
+			return nil, errortypes.DuplicateSeriesPostError{URLHandle: urlHandle}
+		}
+		seen[urlHandle] = true
+
+		post, err := postRepository.GetPost(urlHandle)
+		if err != nil {
+			log.Errorf("failed to get post %s of series %s", urlHandle, series.URLHandle)
+			return nil, err
+		}
+
+		other, err := seriesRepository.GetSeriesOfPost(post.ID)
+		switch err.(type) {
+		case nil:
+			if other.URLHandle != series.URLHandle {
+				return nil, errortypes.PostInOtherSeriesError{URLHandle: urlHandle, Series: other.URLHandle}
+			}
+		case errortypes.SeriesNotFoundError:
+		default:
+			return nil, err
+		}
+
+		entries = append(entries, repository.SeriesEntry{PostID: post.ID, Position: i + 1})
+	}
+
+	return entries, nil
+}
+
+// DeleteSeries deletes a series of the blog. The posts of the series are kept.
+func (s seriesService) DeleteSeries(urlHandle string) error {
+	log := s.cont.GetLogger()
+	seriesRepository := s.cont.GetSeriesRepository()
+
+	log.Infof("deleting series %s", urlHandle)
+	return seriesRepository.DeleteSeries(urlHandle)
+}
+
+// GetSeries retrieves the series with the given URL handle. Only the published posts of the series are listed.
+func (s seriesService) GetSeries(urlHandle string) (repository.Series, error) {
+	seriesRepository := s.cont.GetSeriesRepository()
+
+	series, err := seriesRepository.GetSeries(urlHandle)
+	if err != nil {
+		return repository.Series{}, err
+	}
+
+	series.Entries = publishedEntries(series.Entries)
+	return series, nil
+}
+
+// GetAllSeries retrieves every series of the blog without their posts.
+func (s seriesService) GetAllSeries() ([]repository.Series, error) {
+	seriesRepository := s.cont.GetSeriesRepository()
+	return seriesRepository.GetAllSeries()
+}
+
+// publishedEntries drops the entries whose posts are not visible to readers.
+func publishedEntries(entries []repository.SeriesEntry) []repository.SeriesEntry {
+	published := make([]repository.SeriesEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.Post.IsPublished() {
+			published = append(published, entry)
+		}
+	}
+
+	return published
+}
+
+// seriesNavigation locates the post within the published posts of the series.
+// If the post isn't among them, nil is returned.
+func seriesNavigation(series repository.Series, post repository.Post) *repository.SeriesNavigation {
+	entries := publishedEntries(series.Entries)
+
+	for i, entry := range entries {
+		if entry.PostID != post.ID {
+			continue
+		}
+
+		navigation := repository.SeriesNavigation{
+			Series:   series,
+			Position: i + 1,
+			Total:    len(entries),
+		}
+		navigation.Series.Entries = nil
+
+		if i > 0 {
+			navigation.Previous = &entries[i-1].Post
+		}
+		if i < len(entries)-1 {
+			navigation.Next = &entries[i+1].Post
+		}
+
+		return &navigation
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// seriesTestContext contains objects relevant for testing the SeriesService.
+type seriesTestContext struct {
+	mockPostRepository   *mocks.MockPostRepository
+	mockSeriesRepository *mocks.MockSeriesRepository
+	sut                  services.SeriesService
+}
+
+// createSeriesServiceContext creates the context for testing the SeriesService and reduces code duplication.
+func createSeriesServiceContext(t *testing.T) *seriesTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	mockSeriesRepository := mocks.NewMockSeriesRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), mockPostRepository, nil, nil, nil, mockSeriesRepository, nil, nil, nil, nil, nil, nil, nil)
+	sut := services.CreateSeriesService(cont)
+
+	return &seriesTestContext{mockPostRepository, mockSeriesRepository, sut}
+}
+
+// seriesEntries creates series entries referencing the posts with the given URL handles.
+func seriesEntries(urlHandles ...string) []repository.SeriesEntry {
+	entries := make([]repository.SeriesEntry, 0, len(urlHandles))
+
+	for _, urlHandle := range urlHandles {
+		entries = append(entries, repository.SeriesEntry{Post: repository.Post{URLHandle: urlHandle}})
+	}
+
+	return entries
+}
+
+// TestSeriesService_AddSeries tests adding a new series with its posts in the listed order.
+func TestSeriesService_AddSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesServiceContext(t)
+
+	newSeries := repository.Series{
+		URLHandle: "go-tutorial",
+		Title:     "Go Tutorial",
+		Entries:   seriesEntries("part-2", "part-1"),
+	}
+	expectedSeries := repository.Series{
+		URLHandle: "go-tutorial",
+		Title:     "Go Tutorial",
+		Entries:   []repository.SeriesEntry{{PostID: 5, Position: 1}, {PostID: 4, Position: 2}},
+	}
+
+	c.mockPostRepository.EXPECT().GetPost("part-2").Return(repository.Post{ID: 5}, nil)
+	c.mockPostRepository.EXPECT().GetPost("part-1").Return(repository.Post{ID: 4}, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(uint(5)).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(uint(4)).Return(repository.Series{}, errortypes.SeriesNotFoundError{})
+	c.mockSeriesRepository.EXPECT().AddSeries(expectedSeries).Return(expectedSeries, nil)
+
+	series, err := c.sut.AddSeries(newSeries)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedSeries, series, "series doesn't match the expected one")
+}
+
+// TestSeriesService_AddSeries_Missing_Title tests adding a series without a title.
+func TestSeriesService_AddSeries_Missing_Title(t *testing.T) {
+	t.Parallel()
+	c := createSeriesServiceContext(t)
+
+	_, err := c.sut.AddSeries(repository.Series{URLHandle: "go-tutorial"})
+
+	assert.Equal(t, errortypes.MissingSeriesTitleError{}, err, "error doesn't match expected one")
+}
+
+// TestSeriesService_UpdateSeries_Duplicate_Post tests listing the same post twice.
+func TestSeriesService_UpdateSeries_Duplicate_Post(t *testing.T) {
+	t.Parallel()
+	c := createSeriesServiceContext(t)
+
+	c.mockPostRepository.EXPECT().GetPost("part-1").Return(repository.Post{ID: 4}, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(uint(4)).Return(repository.Series{URLHandle: "go-tutorial"}, nil)
+
+	_, err := c.sut.UpdateSeries(repository.Series{
+		URLHandle: "go-tutorial",
+		Entries:   seriesEntries("part-1", "part-1"),
+	})
+
+	assert.Equal(t, errortypes.DuplicateSeriesPostError{URLHandle: "part-1"}, err, "error doesn't match expected one")
+}
+
+// TestSeriesService_UpdateSeries_Post_In_Other_Series tests adding a post that already belongs to another series.
+func TestSeriesService_UpdateSeries_Post_In_Other_Series(t *testing.T) {
+	t.Parallel()
+	c := createSeriesServiceContext(t)
+
+	expectedError := errortypes.PostInOtherSeriesError{URLHandle: "part-1", Series: "rust-tutorial"}
+
+	c.mockPostRepository.EXPECT().GetPost("part-1").Return(repository.Post{ID: 4}, nil)
+	c.mockSeriesRepository.EXPECT().GetSeriesOfPost(uint(4)).Return(repository.Series{URLHandle: "rust-tutorial"}, nil)
+
+	_, err := c.sut.UpdateSeries(repository.Series{
+		URLHandle: "go-tutorial",
+		Entries:   seriesEntries("part-1"),
+	})
+
+	assert.Equal(t, expectedError, err, "error doesn't match expected one")
+}
+
+// TestSeriesService_UpdateSeries_Metadata tests updating a series without touching its posts.
+func TestSeriesService_UpdateSeries_Metadata(t *testing.T) {
+	t.Parallel()
+	c := createSeriesServiceContext(t)
+
+	updatedSeries := repository.Series{URLHandle: "go-tutorial", Title: "Learning Go"}
+
+	c.mockSeriesRepository.EXPECT().UpdateSeries(updatedSeries).Return(updatedSeries, nil)
+
+	_, err := c.sut.UpdateSeries(updatedSeries)
+
+	assert.Nil(t, err, "should complete without error")
+}
+
+// TestSeriesService_GetSeries tests that only the published posts of a series are listed.
+func TestSeriesService_GetSeries(t *testing.T) {
+	t.Parallel()
+	c := createSeriesServiceContext(t)
+
+	series := repository.Series{
+		URLHandle: "go-tutorial",
+		Entries: []repository.SeriesEntry{
+			{PostID: 4, Position: 1, Post: repository.Post{ID: 4, Status: repository.PostStatusPublished}},
+			{PostID: 5, Position: 2, Post: repository.Post{ID: 5, Status: repository.PostStatusDraft}},
+		},
+	}
+
+	c.mockSeriesRepository.EXPECT().GetSeries("go-tutorial").Return(series, nil)
+
+	result, err := c.sut.GetSeries("go-tutorial")
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, series.Entries[:1], result.Entries, "only published posts should be listed")
+}
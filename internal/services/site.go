@@ -0,0 +1,51 @@
+package services
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Site describes the public website of the blog, as linked from feeds and the sitemap.
+type Site struct {
+	Title       string
+	Description string
+	URL         string
+}
+
+// defaultSiteTitle sets the title of the blog if BLOG_TITLE is not set
+const defaultSiteTitle = "Blog"
+
+// defaultSiteURL sets the address of the website if BLOG_URL is not set
+const defaultSiteURL = "http://localhost:8080"
+
+// loadSite reads the description of the website from the BLOG_TITLE, BLOG_DESCRIPTION and BLOG_URL environment
+// variables. If the URL is missing or not absolute, the default URL is used.
+func loadSite(cont container.Container) Site {
+	log := cont.GetLogger()
+
+	site := Site{
+		Title:       os.Getenv("BLOG_TITLE"),
+		Description: os.Getenv("BLOG_DESCRIPTION"),
+		URL:         strings.TrimSuffix(os.Getenv("BLOG_URL"), "/"),
+	}
+
+	if site.Title == "" {
+		site.Title = defaultSiteTitle
+	}
+
+	if site.URL == "" {
+		site.URL = defaultSiteURL
+	} else if u, err := url.Parse(site.URL); err != nil || !u.IsAbs() {
+		log.Errorf("invalid BLOG_URL value \"%s\", falling back to %s", site.URL, defaultSiteURL)
+		site.URL = defaultSiteURL
+	}
+
+	return site
+}
+
+// PostURL returns the address of the post with the given URL handle on the website.
+func (s Site) PostURL(urlHandle string) string {
+	return s.URL + "/posts/" + url.PathEscape(urlHandle)
+}
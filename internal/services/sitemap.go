@@ -0,0 +1,169 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=sitemap.go -destination=../mocks/mock_sitemap_service.go -package=mocks
+
+import (
+	"fmt"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SitemapURL is an entry of a sitemap, either a page of the website or another sitemap.
+// A zero modification time is left out of the sitemap.
+type SitemapURL struct {
+	Location     string
+	LastModified time.Time
+}
+
+// Sitemap lists the URLs of the published posts. If there are more posts than a single sitemap may hold,
+// the sitemap is an index of the sitemaps listing the posts instead.
+type Sitemap struct {
+	URLs     []SitemapURL
+	Sitemaps []SitemapURL
+}
+
+// SitemapService interface. Defines the business logic of the sitemap and the robots.txt file of the website.
+type SitemapService interface {
+	GetSitemap() (Sitemap, error)
+	GetSitemapPage(page int) (Sitemap, error)
+	GetRobots() string
+}
+
+// sitemapService is the concrete implementation of the SitemapService interface.
+type sitemapService struct {
+	cont     container.Container
+	site     Site
+	size     int
+	disallow []string
+}
+
+// maxSitemapSize is the highest number of URLs a sitemap may hold according to the sitemap protocol
+const maxSitemapSize = 50000
+
+// defaultRobotsDisallow sets the paths crawlers shouldn't visit if ROBOTS_DISALLOW is not set
+const defaultRobotsDisallow = "/api/"
+
+// CreateSitemapService instantiates the sitemapService using the application container.
+func CreateSitemapService(cont container.Container) SitemapService {
+	return &sitemapService{cont, loadSite(cont), loadSitemapSize(cont), loadRobotsDisallow()}
+}
+
+// loadSitemapSize reads the maximum number of URLs per sitemap from the SITEMAP_SIZE environment variable.
+// If the variable is missing or invalid, the limit of the sitemap protocol is used.
+func loadSitemapSize(cont container.Container) int {
+	log := cont.GetLogger()
+
+	value := os.Getenv("SITEMAP_SIZE")
+	if value == "" {
+		return maxSitemapSize
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 || size > maxSitemapSize {
+		log.Errorf("invalid SITEMAP_SIZE value \"%s\", falling back to %d", value, maxSitemapSize)
+		return maxSitemapSize
+	}
+
+	return size
+}
+
+// loadRobotsDisallow reads the comma-separated paths crawlers shouldn't visit from the ROBOTS_DISALLOW
+// environment variable. If the variable is missing, the default paths are used. An empty value allows every path.
+func loadRobotsDisallow() []string {
+	value, found := os.LookupEnv("ROBOTS_DISALLOW")
+	if !found {
+		value = defaultRobotsDisallow
+	}
+
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+// GetSitemap retrieves the sitemap of the website. If the published posts don't fit in a single sitemap,
+// an index of the sitemap pages is returned.
+func (s sitemapService) GetSitemap() (Sitemap, error) {
+	log := s.cont.GetLogger()
+	postRepository := s.cont.GetPostRepository()
+
+	posts, count, err := postRepository.GetPostSitemap(1, s.size)
+	if err != nil {
+		log.Errorf("failed to get sitemap: %v", err)
+		return Sitemap{}, err
+	}
+
+	if count <= s.size {
+		return Sitemap{URLs: s.postURLs(posts)}, nil
+	}
+
+	var sitemap Sitemap
+	for page := 1; (page-1)*s.size < count; page++ {
+		sitemap.Sitemaps = append(sitemap.Sitemaps, SitemapURL{Location: s.sitemapPageURL(page)})
+	}
+
+	return sitemap, nil
+}
+
+// GetSitemapPage retrieves one of the sitemaps listed in the sitemap index.
+func (s sitemapService) GetSitemapPage(page int) (Sitemap, error) {
+	log := s.cont.GetLogger()
+	postRepository := s.cont.GetPostRepository()
+
+	if page < 1 {
+		log.Errorf("invalid sitemap page number %d", page)
+		return Sitemap{}, errortypes.SitemapNotFoundError{Page: strconv.Itoa(page)}
+	}
+
+	posts, _, err := postRepository.GetPostSitemap(page, s.size)
+	if err != nil {
+		log.Errorf("failed to get sitemap page %d: %v", page, err)
+		return Sitemap{}, err
+	}
+
+	if len(posts) == 0 {
+		log.Debugf("sitemap page %d is empty", page)
+		return Sitemap{}, errortypes.SitemapNotFoundError{Page: strconv.Itoa(page)}
+	}
+
+	return Sitemap{URLs: s.postURLs(posts)}, nil
+}
+
+// sitemapPageURL returns the address of a page of the sitemap on the website.
+func (s sitemapService) sitemapPageURL(page int) string {
+	return fmt.Sprintf("%s/sitemaps/%d.xml", s.site.URL, page)
+}
+
+// postURLs maps the posts to the URLs of the sitemap.
+func (s sitemapService) postURLs(posts []repository.Post) []SitemapURL {
+	urls := make([]SitemapURL, 0, len(posts))
+	for _, post := range posts {
+		urls = append(urls, SitemapURL{Location: s.site.PostURL(post.URLHandle), LastModified: post.UpdatedAt})
+	}
+	return urls
+}
+
+// GetRobots builds the robots.txt file of the website, pointing crawlers to the sitemap.
+func (s sitemapService) GetRobots() string {
+	var robots strings.Builder
+
+	robots.WriteString("User-agent: *\n")
+	if len(s.disallow) == 0 {
+		robots.WriteString("Disallow:\n")
+	}
+	for _, path := range s.disallow {
+		robots.WriteString("Disallow: " + path + "\n")
+	}
+	robots.WriteString("\nSitemap: " + s.site.URL + "/sitemap.xml\n")
+
+	return robots.String()
+}
@@ -0,0 +1,124 @@
+package services_test
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// sitemapTestContext contains objects relevant for testing the SitemapService.
+type sitemapTestContext struct {
+	mockPostRepository *mocks.MockPostRepository
+	sut                services.SitemapService
+}
+
+// createSitemapServiceContext creates the context for testing the SitemapService and reduces code duplication.
+func createSitemapServiceContext(t *testing.T) *sitemapTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockPostRepository := mocks.NewMockPostRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), mockPostRepository, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := services.CreateSitemapService(cont)
+
+	return &sitemapTestContext{mockPostRepository, sut}
+}
+
+// TestSitemapService_GetSitemap tests listing every published post in a single sitemap.
+func TestSitemapService_GetSitemap(t *testing.T) {
+	t.Parallel()
+	c := createSitemapServiceContext(t)
+
+	updatedAt := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.UTC)
+	posts := []repository.Post{{URLHandle: "first", UpdatedAt: updatedAt}}
+	expectedSitemap := services.Sitemap{
+		URLs: []services.SitemapURL{{Location: "http://localhost:8080/posts/first", LastModified: updatedAt}},
+	}
+
+	c.mockPostRepository.EXPECT().GetPostSitemap(1, 50000).Return(posts, 1, nil)
+
+	sitemap, err := c.sut.GetSitemap()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedSitemap, sitemap, "incorrect sitemap")
+}
+
+// TestSitemapService_GetSitemap_Index tests splitting the sitemap once the posts exceed the protocol limit.
+func TestSitemapService_GetSitemap_Index(t *testing.T) {
+	t.Parallel()
+	c := createSitemapServiceContext(t)
+
+	expectedSitemap := services.Sitemap{
+		Sitemaps: []services.SitemapURL{
+			{Location: "http://localhost:8080/sitemaps/1.xml"},
+			{Location: "http://localhost:8080/sitemaps/2.xml"},
+			{Location: "http://localhost:8080/sitemaps/3.xml"},
+		},
+	}
+
+	c.mockPostRepository.EXPECT().GetPostSitemap(1, 50000).Return([]repository.Post{{URLHandle: "first"}}, 100001, nil)
+
+	sitemap, err := c.sut.GetSitemap()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, expectedSitemap, sitemap, "incorrect sitemap index")
+}
+
+// TestSitemapService_GetSitemap_Unexpected_Error tests handling errors of the repository.
+func TestSitemapService_GetSitemap_Unexpected_Error(t *testing.T) {
+	t.Parallel()
+	c := createSitemapServiceContext(t)
+
+	expectedError := fmt.Errorf("unexpected error")
+
+	c.mockPostRepository.EXPECT().GetPostSitemap(1, 50000).Return(nil, -1, expectedError)
+
+	_, err := c.sut.GetSitemap()
+
+	assert.Equal(t, expectedError, err, "error should match expected value")
+}
+
+// TestSitemapService_GetSitemapPage tests getting a page of a split sitemap.
+func TestSitemapService_GetSitemapPage(t *testing.T) {
+	t.Parallel()
+	c := createSitemapServiceContext(t)
+
+	c.mockPostRepository.EXPECT().GetPostSitemap(2, 50000).Return([]repository.Post{{URLHandle: "second"}}, 50001, nil)
+
+	sitemap, err := c.sut.GetSitemapPage(2)
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, "http://localhost:8080/posts/second", sitemap.URLs[0].Location, "incorrect sitemap")
+}
+
+// TestSitemapService_GetSitemapPage_Not_Found tests getting pages of the sitemap that don't exist.
+func TestSitemapService_GetSitemapPage_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createSitemapServiceContext(t)
+
+	c.mockPostRepository.EXPECT().GetPostSitemap(3, 50000).Return([]repository.Post{}, 50001, nil)
+
+	_, err := c.sut.GetSitemapPage(3)
+	assert.Equal(t, errortypes.SitemapNotFoundError{Page: "3"}, err, "incorrect error type")
+
+	_, err = c.sut.GetSitemapPage(0)
+	assert.Equal(t, errortypes.SitemapNotFoundError{Page: "0"}, err, "incorrect error type")
+}
+
+// TestSitemapService_GetRobots tests building the default robots.txt file.
+func TestSitemapService_GetRobots(t *testing.T) {
+	t.Parallel()
+	c := createSitemapServiceContext(t)
+
+	expectedRobots := "User-agent: *\nDisallow: /api/\n\nSitemap: http://localhost:8080/sitemap.xml\n"
+
+	assert.Equal(t, expectedRobots, c.sut.GetRobots(), "incorrect robots.txt")
+}
@@ -0,0 +1,66 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=tag.go -destination=../mocks/mock_tag_service.go -package=mocks
+
+import (
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTagNameLength is the maximum number of characters of tag names, matching the size of their database column
+const maxTagNameLength = 64
+
+// TagService interface. Defines tag-related business logic.
+type TagService interface {
+	GetTags() ([]repository.TagCount, error)
+}
+
+// tagService is the concrete implementation of the TagService interface.
+type tagService struct {
+	cont container.Container
+}
+
+// CreateTagService instantiates the tagService using the application container.
+func CreateTagService(cont container.Container) TagService {
+	return &tagService{cont}
+}
+
+// GetTags retrieves every tag in use together with the number of public posts it is attached to.
+func (t tagService) GetTags() ([]repository.TagCount, error) {
+	tagRepository := t.cont.GetTagRepository()
+	return tagRepository.GetTags()
+}
+
+// NormalizeTagName brings a tag name to its canonical form, so that "Go" and " go " refer to the same tag.
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// normalizeTags normalizes the names of the given tags and drops empty and duplicate entries.
+// A nil slice is kept nil, since it means that the tags of the post shouldn't change.
+// Tag names longer than maxTagNameLength characters are rejected.
+func normalizeTags(tags []repository.Tag) ([]repository.Tag, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]repository.Tag, 0, len(tags))
+
+	for _, tag := range tags {
+		name := NormalizeTagName(tag.Name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxTagNameLength {
+			return nil, errortypes.InvalidTagError{Name: name, MaxLength: maxTagNameLength}
+		}
+		seen[name] = true
+		normalized = append(normalized, repository.Tag{Name: name})
+	}
+
+	return normalized, nil
+}
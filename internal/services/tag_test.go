@@ -0,0 +1,53 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+// tagTestContext contains objects relevant for testing the TagService.
+type tagTestContext struct {
+	mockTagRepository *mocks.MockTagRepository
+	sut               services.TagService
+}
+
+// createTagServiceContext creates the context for testing the TagService and reduces code duplication.
+func createTagServiceContext(t *testing.T) *tagTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockTagRepository := mocks.NewMockTagRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, mockTagRepository, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	sut := services.CreateTagService(cont)
+
+	return &tagTestContext{mockTagRepository, sut}
+}
+
+// TestTagService_GetTags tests getting every tag of the blog.
+func TestTagService_GetTags(t *testing.T) {
+	t.Parallel()
+	c := createTagServiceContext(t)
+
+	tags := []repository.TagCount{{Name: "go", PostCount: 2}}
+
+	c.mockTagRepository.EXPECT().GetTags().Return(tags, nil)
+
+	result, err := c.sut.GetTags()
+
+	assert.Nil(t, err, "should complete without error")
+	assert.Equal(t, tags, result, "tags don't match the expected output")
+}
+
+// TestNormalizeTagName tests bringing tag names to their canonical form.
+func TestNormalizeTagName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "go", services.NormalizeTagName("  Go "), "tag name should be trimmed and lowercased")
+	assert.Equal(t, "", services.NormalizeTagName(""), "empty tag name should stay empty")
+}
@@ -0,0 +1,248 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=token.go -destination=../mocks/mock_token_service.go -package=mocks
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/jwt"
+	"github.com/wlachs/blog/internal/repository"
+	"os"
+	"time"
+)
+
+// TokenPair contains the tokens issued upon login and refresh.
+// The access token authenticates requests, while the refresh token is exchanged for a new pair once it expires.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// TokenService interface. Defines the lifecycle of access and refresh tokens.
+type TokenService interface {
+	IssueTokens(user repository.User) (TokenPair, error)
+	RefreshTokens(refreshToken string) (TokenPair, error)
+	ValidateAccessToken(accessToken string) (jwt.Claims, error)
+	RevokeSession(sessionID string) error
+	PurgeExpiredTokens() error
+	PublicKeys() []jwt.JSONWebKey
+}
+
+// tokenService is the concrete implementation of the TokenService interface.
+type tokenService struct {
+	cont     container.Container
+	lifetime time.Duration
+}
+
+// defaultRefreshTokenLifetime sets how long refresh tokens are valid if REFRESH_TOKEN_TTL is not set
+const defaultRefreshTokenLifetime = 30 * 24 * time.Hour
+
+// CreateTokenService instantiates the tokenService using the application container.
+func CreateTokenService(cont container.Container) TokenService {
+	return &tokenService{cont, loadRefreshTokenLifetime(cont)}
+}
+
+// loadRefreshTokenLifetime reads how long refresh tokens are valid from the REFRESH_TOKEN_TTL environment variable.
+func loadRefreshTokenLifetime(cont container.Container) time.Duration {
+	log := cont.GetLogger()
+
+	value := os.Getenv("REFRESH_TOKEN_TTL")
+	if value == "" {
+		return defaultRefreshTokenLifetime
+	}
+
+	lifetime, err := time.ParseDuration(value)
+	if err != nil || lifetime <= 0 {
+		log.Errorf("invalid REFRESH_TOKEN_TTL value \"%s\", falling back to %v", value, defaultRefreshTokenLifetime)
+		return defaultRefreshTokenLifetime
+	}
+
+	return lifetime
+}
+
+// IssueTokens starts a new session for the user and issues its first token pair.
+func (t tokenService) IssueTokens(user repository.User) (TokenPair, error) {
+	log := t.cont.GetLogger()
+	tokenRepository := t.cont.GetRefreshTokenRepository()
+
+	sessionID, err := randomString(16, hex.EncodeToString)
+	if err != nil {
+		log.Errorf("failed to generate session ID: %v", err)
+		return TokenPair{}, errortypes.UnexpectedTokenError{}
+	}
+
+	refreshToken, model, err := t.newRefreshToken(user.ID, sessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if _, err = tokenRepository.AddRefreshToken(model); err != nil {
+		return TokenPair{}, err
+	}
+
+	log.Debugf("started session %s for user %s", sessionID, user.UserName)
+	return t.tokenPair(user, sessionID, refreshToken)
+}
+
+// RefreshTokens exchanges a valid refresh token for a new token pair of the same session.
+// The used refresh token is revoked. If a revoked token is presented again, it has been leaked,
+// so the whole session is revoked.
+func (t tokenService) RefreshTokens(refreshToken string) (TokenPair, error) {
+	log := t.cont.GetLogger()
+	tokenRepository := t.cont.GetRefreshTokenRepository()
+
+	stored, err := tokenRepository.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	if stored.RevokedAt != nil {
+		log.Infof("revoked refresh token of session %s was reused, revoking the session", stored.SessionID)
+		return TokenPair{}, t.revokeReusedSession(stored.SessionID)
+	}
+
+	if !stored.ExpiresAt.After(time.Now()) {
+		log.Debugf("refresh token of session %s expired", stored.SessionID)
+		return TokenPair{}, errortypes.InvalidRefreshTokenError{}
+	}
+
+	newRefreshToken, model, err := t.newRefreshToken(stored.UserID, stored.SessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	_, err = tokenRepository.RotateRefreshToken(stored, model)
+	switch err.(type) {
+	case nil:
+	case errortypes.InvalidRefreshTokenError:
+		log.Infof("refresh token of session %s was used concurrently, revoking the session", stored.SessionID)
+		return TokenPair{}, t.revokeReusedSession(stored.SessionID)
+	default:
+		return TokenPair{}, err
+	}
+
+	log.Debugf("refreshed session %s of user %s", stored.SessionID, stored.User.UserName)
+	return t.tokenPair(stored.User, stored.SessionID, newRefreshToken)
+}
+
+// ValidateAccessToken parses the access token and makes sure its session is still active.
+// Sessions end upon logout, password or role change, deletion of the user or reuse of a revoked refresh token.
+func (t tokenService) ValidateAccessToken(accessToken string) (jwt.Claims, error) {
+	log := t.cont.GetLogger()
+	jwtUtils := t.cont.GetJWTUtils()
+	tokenRepository := t.cont.GetRefreshTokenRepository()
+
+	claims, err := jwtUtils.ParseJWT(accessToken)
+	if err != nil {
+		log.Debugf("failed to parse access token: %v", err)
+		return jwt.Claims{}, errortypes.InvalidAuthTokenError{}
+	}
+
+	active, err := tokenRepository.IsSessionActive(claims.SessionID, time.Now())
+	if err != nil {
+		return jwt.Claims{}, err
+	}
+
+	if !active {
+		log.Debugf("session %s of user %s is no longer active", claims.SessionID, claims.UserName)
+		return jwt.Claims{}, errortypes.InvalidAuthTokenError{}
+	}
+
+	return claims, nil
+}
+
+// RevokeSession ends the session, invalidating its access and refresh tokens.
+func (t tokenService) RevokeSession(sessionID string) error {
+	log := t.cont.GetLogger()
+	tokenRepository := t.cont.GetRefreshTokenRepository()
+
+	log.Debugf("revoking session %s", sessionID)
+	return tokenRepository.RevokeSession(sessionID)
+}
+
+// PurgeExpiredTokens permanently deletes the expired refresh tokens.
+// Their sessions have ended, so they are not needed for detecting reuse anymore.
+func (t tokenService) PurgeExpiredTokens() error {
+	log := t.cont.GetLogger()
+	tokenRepository := t.cont.GetRefreshTokenRepository()
+
+	count, err := tokenRepository.PurgeExpiredRefreshTokens(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Infof("purged %d expired refresh tokens", count)
+	}
+
+	return nil
+}
+
+// PublicKeys returns the public keys verifying the access tokens.
+func (t tokenService) PublicKeys() []jwt.JSONWebKey {
+	jwtUtils := t.cont.GetJWTUtils()
+
+	return jwtUtils.PublicKeys()
+}
+
+// revokeReusedSession revokes the session of a reused refresh token and reports the token as invalid.
+func (t tokenService) revokeReusedSession(sessionID string) error {
+	if err := t.RevokeSession(sessionID); err != nil {
+		return err
+	}
+
+	return errortypes.InvalidRefreshTokenError{}
+}
+
+// newRefreshToken generates a refresh token and the model storing its hash.
+func (t tokenService) newRefreshToken(userID uint, sessionID string) (string, repository.RefreshToken, error) {
+	log := t.cont.GetLogger()
+
+	token, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		log.Errorf("failed to generate refresh token: %v", err)
+		return "", repository.RefreshToken{}, errortypes.UnexpectedTokenError{}
+	}
+
+	model := repository.RefreshToken{
+		TokenHash: hashToken(token),
+		SessionID: sessionID,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(t.lifetime),
+	}
+
+	return token, model, nil
+}
+
+// tokenPair issues an access token carrying the current role of the user and pairs it with the refresh token.
+func (t tokenService) tokenPair(user repository.User, sessionID string, refreshToken string) (TokenPair, error) {
+	jwtUtils := t.cont.GetJWTUtils()
+
+	accessToken, err := jwtUtils.GenerateJWT(user.UserName, string(user.Role), sessionID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// randomString encodes the given number of cryptographically secure random bytes.
+func randomString(size int, encode func([]byte) string) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return encode(b), nil
+}
+
+// hashToken calculates the hash under which a refresh token is stored.
+// Refresh tokens are random, so a fast hash is sufficient.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,222 @@
+package services_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/jwt"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+// tokenTestContext contains objects relevant for testing the TokenService.
+type tokenTestContext struct {
+	mockRefreshTokenRepository *mocks.MockRefreshTokenRepository
+	mockJwtUtils               *mocks.MockTokenUtils
+	sut                        services.TokenService
+}
+
+// createTokenServiceContext creates the context for testing the TokenService and reduces code duplication.
+func createTokenServiceContext(t *testing.T) *tokenTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepository(mockCtrl)
+	mockJwtUtils := mocks.NewMockTokenUtils(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, nil, nil, nil, nil, nil, nil, nil, mockRefreshTokenRepository, nil, nil, mockJwtUtils)
+	sut := services.CreateTokenService(cont)
+
+	return &tokenTestContext{mockRefreshTokenRepository, mockJwtUtils, sut}
+}
+
+// sha256Hex hashes the refresh token the same way it is stored.
+func sha256Hex(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TestTokenService_IssueTokens tests starting a new session upon login.
+func TestTokenService_IssueTokens(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	user := repository.User{ID: 3, UserName: "testAuthor", Role: repository.RoleAuthor}
+
+	var stored repository.RefreshToken
+	c.mockRefreshTokenRepository.EXPECT().AddRefreshToken(gomock.Any()).DoAndReturn(func(token repository.RefreshToken) (repository.RefreshToken, error) {
+		stored = token
+		return token, nil
+	})
+	c.mockJwtUtils.EXPECT().GenerateJWT("testAuthor", "author", gomock.Any()).Return("access", nil)
+
+	tokens, err := c.sut.IssueTokens(user)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, "access", tokens.AccessToken, "incorrect access token")
+	assert.Equal(t, sha256Hex(tokens.RefreshToken), stored.TokenHash, "only the hash of the refresh token should be stored")
+	assert.Len(t, stored.SessionID, 32, "incorrect session ID length")
+	assert.Equal(t, uint(3), stored.UserID, "refresh token should belong to the user")
+	assert.True(t, stored.ExpiresAt.After(time.Now().Add(29*24*time.Hour)), "refresh token should be valid for 30 days")
+}
+
+// TestTokenService_RefreshTokens tests rotating a valid refresh token.
+func TestTokenService_RefreshTokens(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	stored := repository.RefreshToken{
+		ID:        1,
+		SessionID: "session",
+		UserID:    3,
+		User:      repository.User{ID: 3, UserName: "testAuthor", Role: repository.RoleEditor},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	var rotated repository.RefreshToken
+	c.mockRefreshTokenRepository.EXPECT().GetRefreshToken(sha256Hex("refresh")).Return(stored, nil)
+	c.mockRefreshTokenRepository.EXPECT().RotateRefreshToken(stored, gomock.Any()).DoAndReturn(func(_ repository.RefreshToken, token repository.RefreshToken) (repository.RefreshToken, error) {
+		rotated = token
+		return token, nil
+	})
+	c.mockJwtUtils.EXPECT().GenerateJWT("testAuthor", "editor", "session").Return("access", nil)
+
+	tokens, err := c.sut.RefreshTokens("refresh")
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, "access", tokens.AccessToken, "incorrect access token")
+	assert.NotEqual(t, "refresh", tokens.RefreshToken, "refresh token should be rotated")
+	assert.Equal(t, sha256Hex(tokens.RefreshToken), rotated.TokenHash, "new refresh token should be stored")
+	assert.Equal(t, "session", rotated.SessionID, "new refresh token should belong to the same session")
+}
+
+// TestTokenService_RefreshTokens_Reused tests presenting a refresh token that has already been used.
+func TestTokenService_RefreshTokens_Reused(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	revokedAt := time.Now().Add(-time.Minute)
+	stored := repository.RefreshToken{ID: 1, SessionID: "session", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+
+	c.mockRefreshTokenRepository.EXPECT().GetRefreshToken(sha256Hex("refresh")).Return(stored, nil)
+	c.mockRefreshTokenRepository.EXPECT().RevokeSession("session").Return(nil)
+
+	_, err := c.sut.RefreshTokens("refresh")
+
+	assert.Equal(t, errortypes.InvalidRefreshTokenError{}, err, "incorrect error type")
+}
+
+// TestTokenService_RefreshTokens_Concurrent_Use tests using the same refresh token twice at the same time.
+func TestTokenService_RefreshTokens_Concurrent_Use(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	stored := repository.RefreshToken{ID: 1, SessionID: "session", ExpiresAt: time.Now().Add(time.Hour)}
+
+	c.mockRefreshTokenRepository.EXPECT().GetRefreshToken(sha256Hex("refresh")).Return(stored, nil)
+	c.mockRefreshTokenRepository.EXPECT().RotateRefreshToken(stored, gomock.Any()).Return(repository.RefreshToken{}, errortypes.InvalidRefreshTokenError{})
+	c.mockRefreshTokenRepository.EXPECT().RevokeSession("session").Return(nil)
+
+	_, err := c.sut.RefreshTokens("refresh")
+
+	assert.Equal(t, errortypes.InvalidRefreshTokenError{}, err, "incorrect error type")
+}
+
+// TestTokenService_RefreshTokens_Expired tests presenting an expired refresh token.
+func TestTokenService_RefreshTokens_Expired(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	stored := repository.RefreshToken{ID: 1, SessionID: "session", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	c.mockRefreshTokenRepository.EXPECT().GetRefreshToken(sha256Hex("refresh")).Return(stored, nil)
+
+	_, err := c.sut.RefreshTokens("refresh")
+
+	assert.Equal(t, errortypes.InvalidRefreshTokenError{}, err, "incorrect error type")
+}
+
+// TestTokenService_RefreshTokens_Unknown tests presenting a refresh token that was never issued.
+func TestTokenService_RefreshTokens_Unknown(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	c.mockRefreshTokenRepository.EXPECT().GetRefreshToken(sha256Hex("refresh")).Return(repository.RefreshToken{}, errortypes.InvalidRefreshTokenError{})
+
+	_, err := c.sut.RefreshTokens("refresh")
+
+	assert.Equal(t, errortypes.InvalidRefreshTokenError{}, err, "incorrect error type")
+}
+
+// TestTokenService_ValidateAccessToken tests validating an access token of an active session.
+func TestTokenService_ValidateAccessToken(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	claims := jwt.Claims{UserName: "testAuthor", Role: "author", SessionID: "session"}
+
+	c.mockJwtUtils.EXPECT().ParseJWT("access").Return(claims, nil)
+	c.mockRefreshTokenRepository.EXPECT().IsSessionActive("session", gomock.Any()).Return(true, nil)
+
+	result, err := c.sut.ValidateAccessToken("access")
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, claims, result, "incorrect claims")
+}
+
+// TestTokenService_ValidateAccessToken_Revoked_Session tests validating an access token of an ended session.
+func TestTokenService_ValidateAccessToken_Revoked_Session(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	claims := jwt.Claims{UserName: "testAuthor", Role: "author", SessionID: "session"}
+
+	c.mockJwtUtils.EXPECT().ParseJWT("access").Return(claims, nil)
+	c.mockRefreshTokenRepository.EXPECT().IsSessionActive("session", gomock.Any()).Return(false, nil)
+
+	_, err := c.sut.ValidateAccessToken("access")
+
+	assert.Equal(t, errortypes.InvalidAuthTokenError{}, err, "incorrect error type")
+}
+
+// TestTokenService_ValidateAccessToken_Invalid_Token tests validating a malformed or expired access token.
+func TestTokenService_ValidateAccessToken_Invalid_Token(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	c.mockJwtUtils.EXPECT().ParseJWT("access").Return(jwt.Claims{}, fmt.Errorf("Token is expired"))
+
+	_, err := c.sut.ValidateAccessToken("access")
+
+	assert.Equal(t, errortypes.InvalidAuthTokenError{}, err, "incorrect error type")
+}
+
+// TestTokenService_PurgeExpiredTokens tests purging expired refresh tokens.
+func TestTokenService_PurgeExpiredTokens(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	c.mockRefreshTokenRepository.EXPECT().PurgeExpiredRefreshTokens(gomock.Any()).Return(2, nil)
+
+	err := c.sut.PurgeExpiredTokens()
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestTokenService_PublicKeys tests listing the public keys verifying the access tokens.
+func TestTokenService_PublicKeys(t *testing.T) {
+	t.Parallel()
+	c := createTokenServiceContext(t)
+
+	keys := []jwt.JSONWebKey{{KeyType: "OKP", KeyID: "key", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "x"}}
+	c.mockJwtUtils.EXPECT().PublicKeys().Return(keys)
+
+	assert.Equal(t, keys, c.sut.PublicKeys(), "incorrect keys")
+}
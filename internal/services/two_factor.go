@@ -0,0 +1,212 @@
+package services
+
+//go:generate mockgen-v0.4.0 -source=two_factor.go -destination=../mocks/mock_two_factor_service.go -package=mocks
+
+import (
+	"encoding/base32"
+	"github.com/wlachs/blog/internal/auth"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/repository"
+	"strings"
+	"time"
+)
+
+// recoveryCodeCount sets how many recovery codes are issued upon enrolment
+const recoveryCodeCount = 10
+
+// recoveryCodeEncoding encodes recovery codes with characters that are easy to type
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// TOTPEnrolment contains the secret of a pending TOTP enrolment,
+// along with its otpauth URI which authenticator apps read from QR codes.
+type TOTPEnrolment struct {
+	Secret string
+	URI    string
+}
+
+// TwoFactorService interface. Defines the lifecycle of TOTP two-factor authentication and its recovery codes.
+type TwoFactorService interface {
+	BeginEnrolment(userID string) (TOTPEnrolment, error)
+	ConfirmEnrolment(userID string, code string) ([]string, error)
+	Disable(userID string) error
+	Verify(user repository.User, code string) error
+	Reauthenticate(userID string, password string, code string) error
+}
+
+// twoFactorService is the concrete implementation of the TwoFactorService interface.
+type twoFactorService struct {
+	cont container.Container
+}
+
+// CreateTwoFactorService instantiates the twoFactorService using the application container.
+func CreateTwoFactorService(cont container.Container) TwoFactorService {
+	return &twoFactorService{cont}
+}
+
+// BeginEnrolment generates a new TOTP secret for the user, which is pending until its first code is confirmed.
+// If two-factor authentication is already enabled, the current secret stays in use until then.
+func (t twoFactorService) BeginEnrolment(userID string) (TOTPEnrolment, error) {
+	log := t.cont.GetLogger()
+	userRepository := t.cont.GetUserRepository()
+	twoFactorRepository := t.cont.GetTwoFactorRepository()
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return TOTPEnrolment{}, err
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		log.Errorf("failed to generate TOTP secret: %v", err)
+		return TOTPEnrolment{}, errortypes.UnexpectedTwoFactorError{}
+	}
+
+	if err = twoFactorRepository.SetPendingTOTPSecret(user.ID, secret); err != nil {
+		return TOTPEnrolment{}, err
+	}
+
+	log.Infof("user %s started two-factor authentication enrolment", userID)
+	return TOTPEnrolment{
+		Secret: secret,
+		URI:    auth.TOTPURI(loadSite(t.cont).Title, user.UserName, secret),
+	}, nil
+}
+
+// ConfirmEnrolment enables two-factor authentication with the pending secret of the user, if the code matches it.
+// New recovery codes are returned, replacing the previous ones. Later on, only their hashes are known.
+func (t twoFactorService) ConfirmEnrolment(userID string, code string) ([]string, error) {
+	log := t.cont.GetLogger()
+	userRepository := t.cont.GetUserRepository()
+	twoFactorRepository := t.cont.GetTwoFactorRepository()
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TOTPPendingSecret == nil {
+		return nil, errortypes.TwoFactorNotPendingError{}
+	}
+
+	step, ok := auth.ValidateTOTPCode(*user.TOTPPendingSecret, code, time.Now())
+	if !ok {
+		log.Debugf("invalid TOTP code provided by user %s upon enrolment", userID)
+		return nil, errortypes.InvalidTwoFactorCodeError{}
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		recoveryCode, err := randomString(5, recoveryCodeEncoding.EncodeToString)
+		if err != nil {
+			log.Errorf("failed to generate recovery code: %v", err)
+			return nil, errortypes.UnexpectedTwoFactorError{}
+		}
+
+		codes = append(codes, recoveryCode[:4]+"-"+recoveryCode[4:])
+		hashes = append(hashes, hashToken(recoveryCode))
+	}
+
+	err = twoFactorRepository.ActivateTOTPSecret(user.ID, *user.TOTPPendingSecret, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("user %s enabled two-factor authentication", userID)
+	return codes, nil
+}
+
+// Disable turns off two-factor authentication for the user, removing its secrets and recovery codes.
+func (t twoFactorService) Disable(userID string) error {
+	log := t.cont.GetLogger()
+	userRepository := t.cont.GetUserRepository()
+	twoFactorRepository := t.cont.GetTwoFactorRepository()
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TwoFactorEnabled() && user.TOTPPendingSecret == nil {
+		return errortypes.TwoFactorNotEnabledError{}
+	}
+
+	if err = twoFactorRepository.DisableTOTP(user.ID); err != nil {
+		return err
+	}
+
+	log.Infof("two-factor authentication of user %s disabled", userID)
+	return nil
+}
+
+// Verify checks the second factor of a login, which is either a TOTP code or an unused recovery code.
+// Users without two-factor authentication don't need to provide a code.
+// Each TOTP code and recovery code is only accepted once.
+func (t twoFactorService) Verify(user repository.User, code string) error {
+	log := t.cont.GetLogger()
+	twoFactorRepository := t.cont.GetTwoFactorRepository()
+
+	if !user.TwoFactorEnabled() {
+		return nil
+	}
+
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return errortypes.TwoFactorRequiredError{}
+	}
+
+	if step, ok := auth.ValidateTOTPCode(*user.TOTPSecret, code, time.Now()); ok {
+		used, err := twoFactorRepository.UseTOTPStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+
+		if !used {
+			log.Infof("TOTP code of user %s was replayed", user.UserName)
+			return errortypes.InvalidTwoFactorCodeError{}
+		}
+
+		return nil
+	}
+
+	used, err := twoFactorRepository.UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		log.Debugf("invalid two-factor authentication code provided by user %s", user.UserName)
+		return errortypes.InvalidTwoFactorCodeError{}
+	}
+
+	log.Infof("user %s logged in with a recovery code", user.UserName)
+	return nil
+}
+
+// Reauthenticate confirms the identity of a logged-in user before changing two-factor authentication, as if logging in
+// again: the password is required, along with a TOTP or recovery code if the user enabled two-factor authentication.
+// This way, a stolen access token alone cannot replace or remove the second factor.
+func (t twoFactorService) Reauthenticate(userID string, password string, code string) error {
+	log := t.cont.GetLogger()
+	userRepository := t.cont.GetUserRepository()
+
+	user, err := userRepository.GetUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if !auth.CompareStringWithHash(password, user.PasswordHash) {
+		log.Debugf("incorrect password provided by user %s upon re-authentication", userID)
+		return errortypes.IncorrectUsernameOrPasswordError{}
+	}
+
+	return t.Verify(user, code)
+}
+
+// normalizeRecoveryCode removes the separators and the capitalization users might add when typing recovery codes.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ReplaceAll(code, "-", "")
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ToLower(code)
+}
@@ -0,0 +1,280 @@
+package services_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/auth"
+	"github.com/wlachs/blog/internal/container"
+	"github.com/wlachs/blog/internal/errortypes"
+	"github.com/wlachs/blog/internal/logger"
+	"github.com/wlachs/blog/internal/mocks"
+	"github.com/wlachs/blog/internal/repository"
+	"github.com/wlachs/blog/internal/services"
+	"go.uber.org/mock/gomock"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testTOTPSecret is the TOTP secret of the test users
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+// twoFactorTestContext contains objects relevant for testing the TwoFactorService.
+type twoFactorTestContext struct {
+	mockUserRepository      *mocks.MockUserRepository
+	mockTwoFactorRepository *mocks.MockTwoFactorRepository
+	sut                     services.TwoFactorService
+}
+
+// createTwoFactorServiceContext creates the context for testing the TwoFactorService and reduces code duplication.
+func createTwoFactorServiceContext(t *testing.T) *twoFactorTestContext {
+	t.Helper()
+
+	mockCtrl := gomock.NewController(t)
+	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
+	mockTwoFactorRepository := mocks.NewMockTwoFactorRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, mockUserRepository, nil, nil, nil, nil, nil, nil, nil, nil, mockTwoFactorRepository, nil)
+	sut := services.CreateTwoFactorService(cont)
+
+	return &twoFactorTestContext{mockUserRepository, mockTwoFactorRepository, sut}
+}
+
+// currentTOTPCode returns the TOTP code of the secret for the current period.
+func currentTOTPCode(t *testing.T, secret string) (string, int64) {
+	t.Helper()
+
+	step := auth.TOTPStep(time.Now())
+	code, err := auth.TOTPCode(secret, step)
+	assert.Nil(t, err, "TOTP code should be calculated")
+
+	return code, step
+}
+
+// TestTwoFactorService_BeginEnrolment tests generating a pending TOTP secret.
+func TestTwoFactorService_BeginEnrolment(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	var stored string
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, UserName: "testUser"}, nil)
+	c.mockTwoFactorRepository.EXPECT().SetPendingTOTPSecret(uint(2), gomock.Any()).DoAndReturn(func(_ uint, secret string) error {
+		stored = secret
+		return nil
+	})
+
+	enrolment, err := c.sut.BeginEnrolment("testUser")
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, stored, enrolment.Secret, "returned secret should be the pending one")
+	assert.True(t, strings.HasPrefix(enrolment.URI, "otpauth://totp/Blog:testUser?"), "URI should name the blog and the user")
+	assert.Contains(t, enrolment.URI, "secret="+enrolment.Secret, "URI should contain the secret")
+}
+
+// TestTwoFactorService_BeginEnrolment_User_Not_Found tests enrolling a user that doesn't exist.
+func TestTwoFactorService_BeginEnrolment_User_Not_Found(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	expectedError := errortypes.UserNotFoundError{UserName: "testUser"}
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{}, expectedError)
+
+	_, err := c.sut.BeginEnrolment("testUser")
+
+	assert.Equal(t, expectedError, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_ConfirmEnrolment tests enabling two-factor authentication and issuing recovery codes.
+func TestTwoFactorService_ConfirmEnrolment(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	code, step := currentTOTPCode(t, secret)
+
+	var storedHashes []string
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, TOTPPendingSecret: &secret}, nil)
+	c.mockTwoFactorRepository.EXPECT().ActivateTOTPSecret(uint(2), secret, step, gomock.Any()).
+		DoAndReturn(func(_ uint, _ string, _ int64, hashes []string) error {
+			storedHashes = hashes
+			return nil
+		})
+
+	codes, err := c.sut.ConfirmEnrolment("testUser", code)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, 10, len(codes), "incorrect number of recovery codes")
+	assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{4}-[a-z2-7]{4}$`), codes[0], "recovery code should be easy to type")
+	assert.Equal(t, sha256Hex(strings.ReplaceAll(codes[0], "-", "")), storedHashes[0], "only the hash of the recovery code should be stored")
+}
+
+// TestTwoFactorService_ConfirmEnrolment_Invalid_Code tests confirming an enrolment with an incorrect code.
+func TestTwoFactorService_ConfirmEnrolment_Invalid_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, TOTPPendingSecret: &secret}, nil)
+
+	codes, err := c.sut.ConfirmEnrolment("testUser", "abcdef")
+
+	assert.Nil(t, codes, "should not issue recovery codes")
+	assert.Equal(t, errortypes.InvalidTwoFactorCodeError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_ConfirmEnrolment_Not_Pending tests confirming an enrolment that hasn't been started.
+func TestTwoFactorService_ConfirmEnrolment_Not_Pending(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2}, nil)
+
+	_, err := c.sut.ConfirmEnrolment("testUser", "123456")
+
+	assert.Equal(t, errortypes.TwoFactorNotPendingError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_Disable tests turning off two-factor authentication.
+func TestTwoFactorService_Disable(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, TOTPSecret: &secret}, nil)
+	c.mockTwoFactorRepository.EXPECT().DisableTOTP(uint(2)).Return(nil)
+
+	err := c.sut.Disable("testUser")
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestTwoFactorService_Disable_Not_Enabled tests turning off two-factor authentication of a user without it.
+func TestTwoFactorService_Disable_Not_Enabled(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2}, nil)
+
+	err := c.sut.Disable("testUser")
+
+	assert.Equal(t, errortypes.TwoFactorNotEnabledError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_Verify_Not_Enabled tests that users without two-factor authentication need no code.
+func TestTwoFactorService_Verify_Not_Enabled(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	err := c.sut.Verify(repository.User{ID: 2}, "")
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestTwoFactorService_Verify_Missing_Code tests logging in without a code while two-factor authentication is enabled.
+func TestTwoFactorService_Verify_Missing_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	err := c.sut.Verify(repository.User{ID: 2, TOTPSecret: &secret}, " ")
+
+	assert.Equal(t, errortypes.TwoFactorRequiredError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_Verify_TOTP tests logging in with a TOTP code.
+func TestTwoFactorService_Verify_TOTP(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	code, step := currentTOTPCode(t, secret)
+	c.mockTwoFactorRepository.EXPECT().UseTOTPStep(uint(2), step).Return(true, nil)
+
+	err := c.sut.Verify(repository.User{ID: 2, TOTPSecret: &secret}, code)
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestTwoFactorService_Verify_TOTP_Replayed tests logging in with a TOTP code that has already been used.
+func TestTwoFactorService_Verify_TOTP_Replayed(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	code, step := currentTOTPCode(t, secret)
+	c.mockTwoFactorRepository.EXPECT().UseTOTPStep(uint(2), step).Return(false, nil)
+
+	err := c.sut.Verify(repository.User{ID: 2, TOTPSecret: &secret}, code)
+
+	assert.Equal(t, errortypes.InvalidTwoFactorCodeError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_Verify_Recovery_Code tests logging in with a recovery code, regardless of its formatting.
+func TestTwoFactorService_Verify_Recovery_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	c.mockTwoFactorRepository.EXPECT().UseRecoveryCode(uint(2), sha256Hex("abcd2345")).Return(true, nil)
+
+	err := c.sut.Verify(repository.User{ID: 2, TOTPSecret: &secret}, "ABCD-2345")
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestTwoFactorService_Verify_Invalid_Code tests logging in with a code that is neither a TOTP nor a recovery code.
+func TestTwoFactorService_Verify_Invalid_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	secret := testTOTPSecret
+	c.mockTwoFactorRepository.EXPECT().UseRecoveryCode(uint(2), gomock.Any()).Return(false, nil)
+
+	err := c.sut.Verify(repository.User{ID: 2, TOTPSecret: &secret}, "wrong")
+
+	assert.Equal(t, errortypes.InvalidTwoFactorCodeError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_Reauthenticate tests confirming the identity of a user with their password and current TOTP code.
+func TestTwoFactorService_Reauthenticate(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	hash, _ := auth.HashString("testPassword")
+	secret := testTOTPSecret
+	code, step := currentTOTPCode(t, secret)
+
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, PasswordHash: hash, TOTPSecret: &secret}, nil)
+	c.mockTwoFactorRepository.EXPECT().UseTOTPStep(uint(2), step).Return(true, nil)
+
+	err := c.sut.Reauthenticate("testUser", "testPassword", code)
+
+	assert.Nil(t, err, "expected to complete without error")
+}
+
+// TestTwoFactorService_Reauthenticate_Incorrect_Password tests that a stolen access token alone cannot change
+// two-factor authentication.
+func TestTwoFactorService_Reauthenticate_Incorrect_Password(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	hash, _ := auth.HashString("testPassword")
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, PasswordHash: hash}, nil)
+
+	err := c.sut.Reauthenticate("testUser", "wrong", "")
+
+	assert.Equal(t, errortypes.IncorrectUsernameOrPasswordError{}, err, "received error should match the expected one")
+}
+
+// TestTwoFactorService_Reauthenticate_Missing_Code tests that the password alone cannot change an enabled second factor.
+func TestTwoFactorService_Reauthenticate_Missing_Code(t *testing.T) {
+	t.Parallel()
+	c := createTwoFactorServiceContext(t)
+
+	hash, _ := auth.HashString("testPassword")
+	secret := testTOTPSecret
+	c.mockUserRepository.EXPECT().GetUser("testUser").Return(repository.User{ID: 2, PasswordHash: hash, TOTPSecret: &secret}, nil)
+
+	err := c.sut.Reauthenticate("testUser", "testPassword", "")
+
+	assert.Equal(t, errortypes.TwoFactorRequiredError{}, err, "received error should match the expected one")
+}
@@ -13,14 +13,15 @@ import (
 
 // UserService interface. Defines user-related business logic.
 type UserService interface {
-	AuthenticateUser(userID string, password string) (string, error)
+	AuthenticateUser(userID string, password string) (repository.User, error)
 	CheckUserPassword(userID string, password string) bool
 	GetUser(userID string) (repository.User, error)
 	GetUsers() ([]repository.User, int, error)
 	GetUsersPage(page int) ([]repository.User, int, error)
 	RegisterFirstUser() error
-	RegisterUser(userID string, password string) (repository.User, error)
+	RegisterUser(userID string, password string, role repository.Role) (repository.User, error)
 	UpdateUser(userID string, oldPassword string, newPassword string) (repository.User, error)
+	UpdateUserRole(userID string, role repository.Role) (repository.User, error)
 	DeleteUser(userID string) error
 }
 
@@ -54,18 +55,24 @@ func initUserService(service *userService) {
 }
 
 // AuthenticateUser authenticates the user.
-// If the password hash matches the one stored in the database, a JWT is generated.
-func (u userService) AuthenticateUser(userID string, password string) (string, error) {
+// If the password hash matches the one stored in the database, the user is returned, so that tokens can be issued.
+func (u userService) AuthenticateUser(userID string, password string) (repository.User, error) {
 	log := u.cont.GetLogger()
-	jwtUtils := u.cont.GetJWTUtils()
+	userRepository := u.cont.GetUserRepository()
+
+	userModel, err := userRepository.GetUser(userID)
+	if err != nil {
+		log.Errorf("failed to get user %s from DB: %v", userID, err)
+		return repository.User{}, errortypes.IncorrectUsernameOrPasswordError{}
+	}
 
-	if !u.CheckUserPassword(userID, password) {
+	if !auth.CompareStringWithHash(password, userModel.PasswordHash) {
 		log.Debugf("the provided password hash for user \"%s\" doesn't match the one stored in the DB", userID)
-		return "", errortypes.IncorrectUsernameOrPasswordError{}
+		return repository.User{}, errortypes.IncorrectUsernameOrPasswordError{}
 	}
 
 	log.Debugf("authentication complete for user: %s", userID)
-	return jwtUtils.GenerateJWT(userID)
+	return userModel, nil
 }
 
 // CheckUserPassword fetches the user's password hash from the database and compares it to the input.
@@ -110,7 +117,7 @@ func (u userService) GetUsersPage(page int) ([]repository.User, int, error) {
 	return users, pages, err
 }
 
-// RegisterFirstUser creates the main user if it doesn't exist yet.
+// RegisterFirstUser creates the main user with the admin role if it doesn't exist yet.
 // The default username and password are read from environment variables.
 func (u userService) RegisterFirstUser() error {
 	log := u.cont.GetLogger()
@@ -129,12 +136,13 @@ func (u userService) RegisterFirstUser() error {
 	}
 
 	log.Infof("initializing first user with name %s", defaultUser)
-	_, err := u.RegisterUser(defaultUser, defaultPassword)
+	_, err := u.RegisterUser(defaultUser, defaultPassword, repository.RoleAdmin)
 	return err
 }
 
-// RegisterUser creates a new user with the provided username and password.
-func (u userService) RegisterUser(userID string, password string) (repository.User, error) {
+// RegisterUser creates a new user with the provided username, password and role.
+// Without a role, the user is registered as an author.
+func (u userService) RegisterUser(userID string, password string, role repository.Role) (repository.User, error) {
 	log := u.cont.GetLogger()
 	userRepository := u.cont.GetUserRepository()
 
@@ -143,6 +151,12 @@ func (u userService) RegisterUser(userID string, password string) (repository.Us
 		return repository.User{}, errortypes.MissingPasswordError{}
 	}
 
+	if role == "" {
+		role = repository.RoleAuthor
+	} else if !role.Valid() {
+		return repository.User{}, errortypes.InvalidRoleError{Role: string(role)}
+	}
+
 	hash, err := auth.HashString(password)
 	if err != nil {
 		log.Errorf("failed to calculate password hash: %v", err)
@@ -152,6 +166,7 @@ func (u userService) RegisterUser(userID string, password string) (repository.Us
 	newUser := repository.User{
 		UserName:     userID,
 		PasswordHash: hash,
+		Role:         role,
 	}
 
 	return userRepository.AddUser(newUser)
@@ -159,13 +174,16 @@ func (u userService) RegisterUser(userID string, password string) (repository.Us
 
 // UpdateUser receives two user input objects, one with the user's current password, and one with the new attributes.
 // If the old password matches the currently set one, the new fields are set.
+// Every session of the user is revoked, so tokens issued before the password change stop working.
 func (u userService) UpdateUser(userID string, oldPassword string, newPassword string) (repository.User, error) {
 	log := u.cont.GetLogger()
 	userRepository := u.cont.GetUserRepository()
+	tokenRepository := u.cont.GetRefreshTokenRepository()
 
-	if ok := u.CheckUserPassword(userID, oldPassword); !ok {
+	userModel, err := u.AuthenticateUser(userID, oldPassword)
+	if err != nil {
 		log.Debugf("incorrect password for user: %s", userID)
-		return repository.User{}, errortypes.IncorrectUsernameOrPasswordError{}
+		return repository.User{}, err
 	}
 
 	hash, err := auth.HashString(newPassword)
@@ -185,10 +203,40 @@ func (u userService) UpdateUser(userID string, oldPassword string, newPassword s
 		return repository.User{}, err
 	}
 
+	if err = tokenRepository.RevokeUserSessions(userModel.ID); err != nil {
+		log.Errorf("failed to revoke sessions of user %s: %v", user.UserName, err)
+		return repository.User{}, err
+	}
+
 	log.Debugf("updated user: %s", user.UserName)
 	return updatedUser, nil
 }
 
+// UpdateUserRole changes the role of an existing user.
+// Access and refresh tokens carry the role of the user, so every session of the user is ended.
+func (u userService) UpdateUserRole(userID string, role repository.Role) (repository.User, error) {
+	log := u.cont.GetLogger()
+	userRepository := u.cont.GetUserRepository()
+	tokenRepository := u.cont.GetRefreshTokenRepository()
+
+	if !role.Valid() {
+		return repository.User{}, errortypes.InvalidRoleError{Role: string(role)}
+	}
+
+	log.Debugf("changing role of user %s to %s", userID, role)
+	updatedUser, err := userRepository.UpdateUserRole(userID, role)
+	if err != nil {
+		return repository.User{}, err
+	}
+
+	if err = tokenRepository.RevokeUserSessions(updatedUser.ID); err != nil {
+		log.Errorf("failed to revoke sessions of user %s: %v", userID, err)
+		return repository.User{}, err
+	}
+
+	return updatedUser, nil
+}
+
 // DeleteUser receives a userID and deletes the user from the database
 // Before the user can be deleted, every authored post must be unassigned.
 func (u userService) DeleteUser(userID string) error {
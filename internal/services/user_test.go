@@ -17,9 +17,9 @@ import (
 
 // userTestContext contains objects relevant for testing the UserService.
 type userTestContext struct {
-	mockUserRepository *mocks.MockUserRepository
-	mockJwtUtils       *mocks.MockTokenUtils
-	sut                services.UserService
+	mockUserRepository         *mocks.MockUserRepository
+	mockRefreshTokenRepository *mocks.MockRefreshTokenRepository
+	sut                        services.UserService
 }
 
 // createUserServiceContext creates the context for testing the UserService and reduces code duplication.
@@ -31,13 +31,13 @@ func createUserServiceContext(t *testing.T) *userTestContext {
 
 	mockCtrl := gomock.NewController(t)
 	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
-	mockJwtUtils := mocks.NewMockTokenUtils(mockCtrl)
-	cont := container.CreateContainer(logger.CreateLogger(), nil, mockUserRepository, mockJwtUtils)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, mockUserRepository, nil, nil, nil, nil, nil, nil, mockRefreshTokenRepository, nil, nil, nil)
 
 	mockUserRepository.EXPECT().GetUser("TEST").Return(repository.User{}, nil)
 	sut := services.CreateUserService(cont)
 
-	return &userTestContext{mockUserRepository, mockJwtUtils, sut}
+	return &userTestContext{mockUserRepository, mockRefreshTokenRepository, sut}
 }
 
 // createUserServiceContext creates the context for testing the UserService and reduces code duplication.
@@ -46,12 +46,12 @@ func createUserServiceContextWithoutDefaults(t *testing.T) *userTestContext {
 
 	mockCtrl := gomock.NewController(t)
 	mockUserRepository := mocks.NewMockUserRepository(mockCtrl)
-	mockJwtUtils := mocks.NewMockTokenUtils(mockCtrl)
-	cont := container.CreateContainer(logger.CreateLogger(), nil, mockUserRepository, mockJwtUtils)
+	mockRefreshTokenRepository := mocks.NewMockRefreshTokenRepository(mockCtrl)
+	cont := container.CreateContainer(logger.CreateLogger(), nil, mockUserRepository, nil, nil, nil, nil, nil, nil, mockRefreshTokenRepository, nil, nil, nil)
 
 	sut := services.CreateUserService(cont)
 
-	return &userTestContext{mockUserRepository, mockJwtUtils, sut}
+	return &userTestContext{mockUserRepository, mockRefreshTokenRepository, sut}
 }
 
 // TestUserService_AuthenticateUser tests user authentication.
@@ -62,6 +62,7 @@ func TestUserService_AuthenticateUser(t *testing.T) {
 		ID:           0,
 		UserName:     "testAuthor",
 		PasswordHash: "$2y$10$Hb7smnjLlPtN.VMyNi5dYuMaCmEgCbus/Tapxf2u5jhxkKE1Pr50.",
+		Role:         repository.RoleEditor,
 		Posts:        []repository.Post{},
 	}
 
@@ -71,12 +72,11 @@ func TestUserService_AuthenticateUser(t *testing.T) {
 	}
 
 	c.mockUserRepository.EXPECT().GetUser(input.UserID).Return(userModel, nil)
-	c.mockJwtUtils.EXPECT().GenerateJWT(input.UserID).Return("TOKEN", nil)
 
-	token, err := c.sut.AuthenticateUser(input.UserID, input.Password)
+	user, err := c.sut.AuthenticateUser(input.UserID, input.Password)
 
 	assert.Nil(t, err, "expected to complete without error")
-	assert.Equal(t, "TOKEN", token)
+	assert.Equal(t, userModel, user, "authenticated user doesn't match the expected one")
 }
 
 // TestUserService_AuthenticateUser_Invalid_Password tests user authentication with invalid password.
@@ -99,9 +99,9 @@ func TestUserService_AuthenticateUser_Invalid_Password(t *testing.T) {
 
 	c.mockUserRepository.EXPECT().GetUser(input.UserID).Return(userModel, nil)
 
-	token, err := c.sut.AuthenticateUser(input.UserID, input.Password)
+	user, err := c.sut.AuthenticateUser(input.UserID, input.Password)
 
-	assert.Equal(t, token, "", "no token should be generated")
+	assert.Equal(t, repository.User{}, user, "no user should be returned")
 	assert.Equal(t, expectedError, err, "incorrect error type")
 }
 
@@ -301,7 +301,10 @@ func TestUserService_RegisterFirstUser(t *testing.T) {
 	t.Setenv("DEFAULT_PASSWORD", "Test")
 
 	c.mockUserRepository.EXPECT().GetUser(userModel.UserName).Return(repository.User{}, fmt.Errorf("internal error"))
-	c.mockUserRepository.EXPECT().AddUser(gomock.Any()).Return(userModel, nil)
+	c.mockUserRepository.EXPECT().AddUser(gomock.Any()).DoAndReturn(func(user repository.User) (repository.User, error) {
+		assert.Equal(t, repository.RoleAdmin, user.Role, "first user should be an admin")
+		return userModel, nil
+	})
 
 	err := c.sut.RegisterFirstUser()
 
@@ -336,9 +339,12 @@ func TestUserService_RegisterUser(t *testing.T) {
 		Password: "Test",
 	}
 
-	c.mockUserRepository.EXPECT().AddUser(gomock.Any()).Return(userModel, nil)
+	c.mockUserRepository.EXPECT().AddUser(gomock.Any()).DoAndReturn(func(user repository.User) (repository.User, error) {
+		assert.Equal(t, repository.RoleAuthor, user.Role, "users should be authors by default")
+		return userModel, nil
+	})
 
-	user, err := c.sut.RegisterUser(input.UserID, input.Password)
+	user, err := c.sut.RegisterUser(input.UserID, input.Password, "")
 
 	assert.Nil(t, err, "expected to complete without error")
 	assert.Equal(t, userModel, user, "response doesn't match expected user data")
@@ -355,7 +361,7 @@ func TestUserService_RegisterUser_Invalid_Password(t *testing.T) {
 
 	expectedError := errortypes.PasswordHashingError{}
 
-	_, err := c.sut.RegisterUser(input.UserID, input.Password)
+	_, err := c.sut.RegisterUser(input.UserID, input.Password, repository.RoleAuthor)
 
 	assert.Equal(t, expectedError, err, "incorrect error type")
 }
@@ -371,7 +377,18 @@ func TestUserService_RegisterUser_Missing_Password(t *testing.T) {
 
 	expectedError := errortypes.MissingPasswordError{}
 
-	_, err := c.sut.RegisterUser(input.UserID, input.Password)
+	_, err := c.sut.RegisterUser(input.UserID, input.Password, repository.RoleAuthor)
+
+	assert.Equal(t, expectedError, err, "incorrect error type")
+}
+
+// TestUserService_RegisterUser_Invalid_Role tests adding a new user to the system with an unknown role.
+func TestUserService_RegisterUser_Invalid_Role(t *testing.T) {
+	c := createUserServiceContext(t)
+
+	expectedError := errortypes.InvalidRoleError{Role: "owner"}
+
+	_, err := c.sut.RegisterUser("testAuthor", "Test", "owner")
 
 	assert.Equal(t, expectedError, err, "incorrect error type")
 }
@@ -384,7 +401,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 	oldPassword := "Test"
 	newPassword := "Test1"
 	oldUserModel := repository.User{
-		ID:           0,
+		ID:           7,
 		UserName:     userID,
 		PasswordHash: "$2y$10$Hb7smnjLlPtN.VMyNi5dYuMaCmEgCbus/Tapxf2u5jhxkKE1Pr50.",
 		Posts:        []repository.Post{},
@@ -399,6 +416,7 @@ func TestUserService_UpdateUser(t *testing.T) {
 
 	c.mockUserRepository.EXPECT().GetUser(userID).Return(oldUserModel, nil)
 	c.mockUserRepository.EXPECT().UpdateUser(gomock.Any()).Return(newUserModel, nil)
+	c.mockRefreshTokenRepository.EXPECT().RevokeUserSessions(uint(7)).Return(nil)
 
 	user, err := c.sut.UpdateUser(userID, oldPassword, newPassword)
 
@@ -490,3 +508,53 @@ func TestUserService_DeleteUser(t *testing.T) {
 
 	assert.Equal(t, dbErr, err, "should forward DB error to controller")
 }
+
+// TestUserService_UpdateUserRole tests changing the role of an existing user.
+func TestUserService_UpdateUserRole(t *testing.T) {
+	c := createUserServiceContext(t)
+
+	userModel := repository.User{
+		ID:       7,
+		UserName: "testAuthor",
+		Role:     repository.RoleEditor,
+	}
+
+	c.mockUserRepository.EXPECT().UpdateUserRole(userModel.UserName, repository.RoleEditor).Return(userModel, nil)
+	c.mockRefreshTokenRepository.EXPECT().RevokeUserSessions(uint(7)).Return(nil)
+
+	user, err := c.sut.UpdateUserRole(userModel.UserName, repository.RoleEditor)
+
+	assert.Nil(t, err, "expected to complete without error")
+	assert.Equal(t, userModel, user, "response doesn't match expected user data")
+}
+
+// TestUserService_UpdateUserRole_Revoke_Error tests changing the role of a user whose sessions can't be revoked.
+func TestUserService_UpdateUserRole_Revoke_Error(t *testing.T) {
+	c := createUserServiceContext(t)
+
+	userModel := repository.User{
+		ID:       7,
+		UserName: "testAuthor",
+		Role:     repository.RoleAuthor,
+	}
+	dbErr := fmt.Errorf("unexpected error")
+
+	c.mockUserRepository.EXPECT().UpdateUserRole(userModel.UserName, repository.RoleAuthor).Return(userModel, nil)
+	c.mockRefreshTokenRepository.EXPECT().RevokeUserSessions(uint(7)).Return(dbErr)
+
+	user, err := c.sut.UpdateUserRole(userModel.UserName, repository.RoleAuthor)
+
+	assert.Equal(t, dbErr, err, "should forward DB error to controller")
+	assert.Equal(t, repository.User{}, user, "should not return a user")
+}
+
+// TestUserService_UpdateUserRole_Invalid_Role tests changing the role of a user to an unknown one.
+func TestUserService_UpdateUserRole_Invalid_Role(t *testing.T) {
+	c := createUserServiceContext(t)
+
+	expectedError := errortypes.InvalidRoleError{Role: ""}
+
+	_, err := c.sut.UpdateUserRole("testAuthor", "")
+
+	assert.Equal(t, expectedError, err, "incorrect error type")
+}
@@ -0,0 +1,144 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is a text identified by an ID, such as the content of a post.
+type Document struct {
+	ID   uint
+	Text string
+}
+
+// Match is a document similar to another one. The score is the cosine similarity of their TF-IDF vectors.
+type Match struct {
+	ID    uint
+	Score float64
+}
+
+// minWordLength excludes very short words, which rarely carry meaning
+const minWordLength = 3
+
+// stopWords are frequent English words that are ignored
+var stopWords = map[string]bool{
+	"about": true, "after": true, "all": true, "also": true, "and": true, "any": true, "are": true, "because": true,
+	"been": true, "before": true, "but": true, "can": true, "could": true, "did": true, "does": true, "for": true,
+	"from": true, "had": true, "has": true, "have": true, "here": true, "how": true, "into": true, "its": true,
+	"just": true, "more": true, "most": true, "not": true, "now": true, "one": true, "only": true, "other": true,
+	"our": true, "out": true, "over": true, "should": true, "some": true, "such": true, "than": true, "that": true,
+	"the": true, "their": true, "them": true, "then": true, "there": true, "these": true, "they": true, "this": true,
+	"those": true, "through": true, "very": true, "was": true, "way": true, "were": true, "what": true, "when": true,
+	"where": true, "which": true, "while": true, "who": true, "why": true, "will": true, "with": true, "would": true,
+	"you": true, "your": true,
+}
+
+// Related returns the given number of most similar documents of every document, most similar first.
+// Documents without any common word are never related.
+func Related(documents []Document, count int) map[uint][]Match {
+	vectors := weigh(documents)
+	related := make(map[uint][]Match, len(documents))
+
+	for i, document := range documents {
+		var matches []Match
+
+		for j, other := range documents {
+			if i == j {
+				continue
+			}
+
+			if score := cosine(vectors[i], vectors[j]); score > 0 {
+				matches = append(matches, Match{ID: other.ID, Score: score})
+			}
+		}
+
+		// Ties are broken by the ID to keep the result deterministic
+		sort.Slice(matches, func(a, b int) bool {
+			if matches[a].Score != matches[b].Score {
+				return matches[a].Score > matches[b].Score
+			}
+			return matches[a].ID < matches[b].ID
+		})
+
+		if len(matches) > count {
+			matches = matches[:count]
+		}
+
+		related[document.ID] = matches
+	}
+
+	return related
+}
+
+// weigh computes the normalized TF-IDF vector of every document.
+func weigh(documents []Document) []map[string]float64 {
+	frequencies := make([]map[string]float64, len(documents))
+	documentFrequency := map[string]int{}
+
+	for i, document := range documents {
+		words := tokenize(document.Text)
+		frequencies[i] = map[string]float64{}
+
+		for _, word := range words {
+			frequencies[i][word]++
+		}
+
+		for word := range frequencies[i] {
+			documentFrequency[word]++
+		}
+
+		for word := range frequencies[i] {
+			frequencies[i][word] /= float64(len(words))
+		}
+	}
+
+	for _, vector := range frequencies {
+		var norm float64
+
+		for word, tf := range vector {
+			// Words used by every document don't tell documents apart
+			vector[word] = tf * math.Log(float64(len(documents))/float64(documentFrequency[word]))
+			norm += vector[word] * vector[word]
+		}
+
+		norm = math.Sqrt(norm)
+		for word := range vector {
+			if norm > 0 {
+				vector[word] /= norm
+			}
+		}
+	}
+
+	return frequencies
+}
+
+// cosine computes the cosine similarity of two normalized vectors.
+func cosine(a map[string]float64, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var score float64
+	for word, weight := range a {
+		score += weight * b[word]
+	}
+
+	return score
+}
+
+// tokenize splits the text into lowercase words, leaving out stop words and short words.
+func tokenize(text string) []string {
+	var words []string
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) >= minWordLength && !stopWords[word] {
+			words = append(words, word)
+		}
+	}
+
+	return words
+}
@@ -0,0 +1,60 @@
+package similarity_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/similarity"
+	"testing"
+)
+
+// TestRelated tests ranking documents by their similarity.
+func TestRelated(t *testing.T) {
+	t.Parallel()
+
+	documents := []similarity.Document{
+		{ID: 1, Text: "Goroutines and channels in Go"},
+		{ID: 2, Text: "Buffered channels and goroutines explained"},
+		{ID: 3, Text: "Baking sourdough bread at home"},
+		{ID: 4, Text: "Channels of communication in teams"},
+	}
+
+	related := similarity.Related(documents, 2)
+
+	assert.Equal(t, 4, len(related), "every document should have an entry")
+	assert.Equal(t, []uint{2, 4}, ids(related[1]), "incorrect related documents")
+	assert.Empty(t, related[3], "unrelated documents shouldn't be matched")
+	assert.Greater(t, related[1][0].Score, related[1][1].Score, "matches should be ordered by score")
+}
+
+// TestRelated_Stop_Words tests that stop words don't make documents similar.
+func TestRelated_Stop_Words(t *testing.T) {
+	t.Parallel()
+
+	documents := []similarity.Document{
+		{ID: 1, Text: "The first and the best"},
+		{ID: 2, Text: "What about the rest and the others"},
+		{ID: 3, Text: "Something else entirely"},
+	}
+
+	related := similarity.Related(documents, 5)
+
+	assert.Empty(t, related[1], "stop words shouldn't relate documents")
+}
+
+// TestRelated_Empty tests relating documents without any text.
+func TestRelated_Empty(t *testing.T) {
+	t.Parallel()
+
+	related := similarity.Related([]similarity.Document{{ID: 1}, {ID: 2}}, 3)
+
+	assert.Empty(t, related[1], "empty documents shouldn't be related")
+	assert.Empty(t, related[2], "empty documents shouldn't be related")
+}
+
+// ids returns the IDs of the matches.
+func ids(matches []similarity.Match) []uint {
+	result := make([]uint, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, match.ID)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package slug
+
+import (
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"strings"
+	"unicode"
+)
+
+// maxLength limits the length of generated slugs. Longer slugs are cut at the last word boundary.
+const maxLength = 80
+
+// fallback is used if nothing is left of the text after removing the unsupported characters
+const fallback = "post"
+
+// transliterations maps letters that can't be decomposed into ASCII letters and diacritics.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'ł': "l", 'þ': "th", 'ı': "i", 'ŀ': "l",
+	'&': " and ",
+
+	// Greek
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k",
+	'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+	'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// Make creates a URL-safe slug from the given text, e.g. "Ünïcode & Go!" becomes "unicode-and-go".
+// The slug only contains lowercase ASCII letters, digits and single hyphens between words.
+func Make(text string) string {
+	return MakeWithFallback(text, fallback)
+}
+
+// MakeWithFallback creates a slug like Make, but returns the given fallback if nothing is left of the text.
+func MakeWithFallback(text string, defaultSlug string) string {
+	// Letters with diacritics are transliterated both before and after decomposing them,
+	// so that both "й" and "έ" are handled correctly
+	transliterated := transliterate(strings.ToLower(text))
+	if decomposed, _, err := transform.String(stripMarks(), transliterated); err == nil {
+		transliterated = transliterate(decomposed)
+	}
+
+	words := strings.FieldsFunc(transliterated, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	slug := ""
+	for _, word := range words {
+		if slug == "" {
+			slug = word
+		} else if len(slug)+1+len(word) <= maxLength {
+			slug += "-" + word
+		} else {
+			break
+		}
+	}
+
+	if len(slug) > maxLength {
+		slug = slug[:maxLength]
+	}
+
+	if slug == "" {
+		return defaultSlug
+	}
+
+	return slug
+}
+
+// stripMarks decomposes the letters and drops the diacritics, e.g. "é" becomes "e".
+func stripMarks() transform.Transformer {
+	return transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)))
+}
+
+// transliterate replaces the letters of the text listed in transliterations.
+func transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if t, ok := transliterations[r]; ok {
+			b.WriteString(t)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
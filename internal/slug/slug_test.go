@@ -0,0 +1,54 @@
+package slug_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/wlachs/blog/internal/slug"
+	"strings"
+	"testing"
+)
+
+// TestMake tests creating slugs from titles with punctuation, whitespace and mixed case.
+func TestMake(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "hello-world", slug.Make("Hello, World!"), "incorrect slug")
+	assert.Equal(t, "go-1-22-release-notes", slug.Make("  Go 1.22: Release -- Notes  "), "incorrect slug")
+	assert.Equal(t, "rock-and-roll", slug.Make("Rock & Roll"), "incorrect slug")
+}
+
+// TestMake_Unicode tests transliterating non-ASCII letters.
+func TestMake_Unicode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "arvizturo-tukorfurogep", slug.Make("Árvíztűrő tükörfúrógép"), "incorrect slug")
+	assert.Equal(t, "strasse-und-grosse", slug.Make("Straße und Größe"), "incorrect slug")
+	assert.Equal(t, "privet-mir", slug.Make("Привет, мир"), "incorrect slug")
+	assert.Equal(t, "kalimera", slug.Make("Καλημέρα"), "incorrect slug")
+}
+
+// TestMake_Long_Text tests that long slugs are cut at a word boundary.
+func TestMake_Long_Text(t *testing.T) {
+	t.Parallel()
+
+	s := slug.Make(strings.Repeat("performance ", 20))
+
+	assert.LessOrEqual(t, len(s), 80, "slug is too long")
+	assert.False(t, strings.HasSuffix(s, "-"), "slug shouldn't end with a hyphen")
+	assert.True(t, strings.HasSuffix(s, "performance"), "slug should end with a whole word")
+}
+
+// TestMake_Empty tests creating a slug from a text without any usable characters.
+func TestMake_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "post", slug.Make("!?"), "incorrect fallback slug")
+	assert.Equal(t, "post", slug.Make("日本語"), "incorrect fallback slug")
+}
+
+// TestMakeWithFallback tests creating a slug with a custom fallback.
+func TestMakeWithFallback(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "hello-world", slug.MakeWithFallback("Hello, World!", "section"), "incorrect slug")
+	assert.Equal(t, "section", slug.MakeWithFallback("🚀 !", "section"), "fallback should be used")
+}